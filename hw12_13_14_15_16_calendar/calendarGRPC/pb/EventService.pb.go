@@ -12,6 +12,7 @@ import (
 	unsafe "unsafe"
 
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	_ "google.golang.org/protobuf/types/descriptorpb"
@@ -166,6 +167,74 @@ func (x *CreateEventResponse) GetError() string {
 	return ""
 }
 
+type ListEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimeZone      string                 `protobuf:"bytes,1,opt,name=timeZone,proto3" json:"timeZone,omitempty"` // IANA zone for day/week/month boundaries, e.g. "Europe/Berlin"; default UTC
+	Clinic        string                 `protobuf:"bytes,2,opt,name=clinic,proto3" json:"clinic,omitempty"`     // only events at the clinic
+	Service       string                 `protobuf:"bytes,3,opt,name=service,proto3" json:"service,omitempty"`   // only events of the service
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`         // only events tagged with any of the categories
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsRequest) Reset() {
+	*x = ListEventsRequest{}
+	mi := &file_EventService_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsRequest) ProtoMessage() {}
+
+func (x *ListEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListEventsRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetClinic() string {
+	if x != nil {
+		return x.Clinic
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
 type ListEventsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
@@ -175,7 +244,7 @@ type ListEventsResponse struct {
 
 func (x *ListEventsResponse) Reset() {
 	*x = ListEventsResponse{}
-	mi := &file_EventService_proto_msgTypes[3]
+	mi := &file_EventService_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -187,7 +256,7 @@ func (x *ListEventsResponse) String() string {
 func (*ListEventsResponse) ProtoMessage() {}
 
 func (x *ListEventsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_EventService_proto_msgTypes[3]
+	mi := &file_EventService_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -200,7 +269,7 @@ func (x *ListEventsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListEventsResponse.ProtoReflect.Descriptor instead.
 func (*ListEventsResponse) Descriptor() ([]byte, []int) {
-	return file_EventService_proto_rawDescGZIP(), []int{3}
+	return file_EventService_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ListEventsResponse) GetEvents() []*Event {
@@ -219,7 +288,7 @@ type GetEventRequest struct {
 
 func (x *GetEventRequest) Reset() {
 	*x = GetEventRequest{}
-	mi := &file_EventService_proto_msgTypes[4]
+	mi := &file_EventService_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -231,7 +300,7 @@ func (x *GetEventRequest) String() string {
 func (*GetEventRequest) ProtoMessage() {}
 
 func (x *GetEventRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_EventService_proto_msgTypes[4]
+	mi := &file_EventService_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -244,7 +313,7 @@ func (x *GetEventRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEventRequest.ProtoReflect.Descriptor instead.
 func (*GetEventRequest) Descriptor() ([]byte, []int) {
-	return file_EventService_proto_rawDescGZIP(), []int{4}
+	return file_EventService_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetEventRequest) GetId() int32 {
@@ -264,7 +333,7 @@ type GetEventResponse struct {
 
 func (x *GetEventResponse) Reset() {
 	*x = GetEventResponse{}
-	mi := &file_EventService_proto_msgTypes[5]
+	mi := &file_EventService_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -276,7 +345,7 @@ func (x *GetEventResponse) String() string {
 func (*GetEventResponse) ProtoMessage() {}
 
 func (x *GetEventResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_EventService_proto_msgTypes[5]
+	mi := &file_EventService_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -289,7 +358,7 @@ func (x *GetEventResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEventResponse.ProtoReflect.Descriptor instead.
 func (*GetEventResponse) Descriptor() ([]byte, []int) {
-	return file_EventService_proto_rawDescGZIP(), []int{5}
+	return file_EventService_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetEventResponse) GetEvent() *Event {
@@ -315,7 +384,7 @@ type DeleteEventRequest struct {
 
 func (x *DeleteEventRequest) Reset() {
 	*x = DeleteEventRequest{}
-	mi := &file_EventService_proto_msgTypes[6]
+	mi := &file_EventService_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -327,7 +396,7 @@ func (x *DeleteEventRequest) String() string {
 func (*DeleteEventRequest) ProtoMessage() {}
 
 func (x *DeleteEventRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_EventService_proto_msgTypes[6]
+	mi := &file_EventService_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -340,7 +409,7 @@ func (x *DeleteEventRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteEventRequest.ProtoReflect.Descriptor instead.
 func (*DeleteEventRequest) Descriptor() ([]byte, []int) {
-	return file_EventService_proto_rawDescGZIP(), []int{6}
+	return file_EventService_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DeleteEventRequest) GetId() int32 {
@@ -360,7 +429,7 @@ type DeleteEventResponse struct {
 
 func (x *DeleteEventResponse) Reset() {
 	*x = DeleteEventResponse{}
-	mi := &file_EventService_proto_msgTypes[7]
+	mi := &file_EventService_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -372,7 +441,7 @@ func (x *DeleteEventResponse) String() string {
 func (*DeleteEventResponse) ProtoMessage() {}
 
 func (x *DeleteEventResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_EventService_proto_msgTypes[7]
+	mi := &file_EventService_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,7 +454,7 @@ func (x *DeleteEventResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteEventResponse.ProtoReflect.Descriptor instead.
 func (*DeleteEventResponse) Descriptor() ([]byte, []int) {
-	return file_EventService_proto_rawDescGZIP(), []int{7}
+	return file_EventService_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeleteEventResponse) GetSuccess() bool {
@@ -411,7 +480,7 @@ type UpdateEventRequest struct {
 
 func (x *UpdateEventRequest) Reset() {
 	*x = UpdateEventRequest{}
-	mi := &file_EventService_proto_msgTypes[8]
+	mi := &file_EventService_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -423,7 +492,7 @@ func (x *UpdateEventRequest) String() string {
 func (*UpdateEventRequest) ProtoMessage() {}
 
 func (x *UpdateEventRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_EventService_proto_msgTypes[8]
+	mi := &file_EventService_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -436,7 +505,7 @@ func (x *UpdateEventRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateEventRequest.ProtoReflect.Descriptor instead.
 func (*UpdateEventRequest) Descriptor() ([]byte, []int) {
-	return file_EventService_proto_rawDescGZIP(), []int{8}
+	return file_EventService_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UpdateEventRequest) GetEvent() *Event {
@@ -456,7 +525,7 @@ type UpdateEventResponse struct {
 
 func (x *UpdateEventResponse) Reset() {
 	*x = UpdateEventResponse{}
-	mi := &file_EventService_proto_msgTypes[9]
+	mi := &file_EventService_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -468,7 +537,7 @@ func (x *UpdateEventResponse) String() string {
 func (*UpdateEventResponse) ProtoMessage() {}
 
 func (x *UpdateEventResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_EventService_proto_msgTypes[9]
+	mi := &file_EventService_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -481,7 +550,7 @@ func (x *UpdateEventResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateEventResponse.ProtoReflect.Descriptor instead.
 func (*UpdateEventResponse) Descriptor() ([]byte, []int) {
-	return file_EventService_proto_rawDescGZIP(), []int{9}
+	return file_EventService_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UpdateEventResponse) GetSuccess() bool {
@@ -498,37 +567,31 @@ func (x *UpdateEventResponse) GetError() string {
 	return ""
 }
 
-// ====== Event ======
-type Event struct {
+type SearchEventsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Start         string                 `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"` // ISO8601 datetime
-	End           string                 `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`     // optional
-	AllDay        bool                   `protobuf:"varint,6,opt,name=allDay,proto3" json:"allDay,omitempty"`
-	Clinic        string                 `protobuf:"bytes,7,opt,name=clinic,proto3" json:"clinic,omitempty"`
-	UserId        int32                  `protobuf:"varint,8,opt,name=userId,proto3" json:"userId,omitempty"`
-	Service       string                 `protobuf:"bytes,9,opt,name=service,proto3" json:"service,omitempty"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`  // all words and "quoted phrases" must occur
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`    // optional RFC3339; only events overlapping [from, to)
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`        // optional RFC3339
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"` // default 20, at most 100
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Event) Reset() {
-	*x = Event{}
-	mi := &file_EventService_proto_msgTypes[10]
+func (x *SearchEventsRequest) Reset() {
+	*x = SearchEventsRequest{}
+	mi := &file_EventService_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Event) String() string {
+func (x *SearchEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Event) ProtoMessage() {}
+func (*SearchEventsRequest) ProtoMessage() {}
 
-func (x *Event) ProtoReflect() protoreflect.Message {
-	mi := &file_EventService_proto_msgTypes[10]
+func (x *SearchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -539,103 +602,2489 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Event.ProtoReflect.Descriptor instead.
-func (*Event) Descriptor() ([]byte, []int) {
-	return file_EventService_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use SearchEventsRequest.ProtoReflect.Descriptor instead.
+func (*SearchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *Event) GetId() int32 {
+func (x *SearchEventsRequest) GetQuery() string {
 	if x != nil {
-		return x.Id
+		return x.Query
 	}
-	return 0
+	return ""
 }
 
-func (x *Event) GetTitle() string {
+func (x *SearchEventsRequest) GetFrom() string {
 	if x != nil {
-		return x.Title
+		return x.From
 	}
 	return ""
 }
 
-func (x *Event) GetDescription() string {
+func (x *SearchEventsRequest) GetTo() string {
 	if x != nil {
-		return x.Description
+		return x.To
 	}
 	return ""
 }
 
-func (x *Event) GetStart() string {
+func (x *SearchEventsRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Start
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-func (x *Event) GetEnd() string {
+type SearchEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchEventsResponse) Reset() {
+	*x = SearchEventsResponse{}
+	mi := &file_EventService_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchEventsResponse) ProtoMessage() {}
+
+func (x *SearchEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[12]
 	if x != nil {
-		return x.End
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Event) GetAllDay() bool {
+// Deprecated: Use SearchEventsResponse.ProtoReflect.Descriptor instead.
+func (*SearchEventsResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchEventsResponse) GetResults() []*SearchResult {
 	if x != nil {
-		return x.AllDay
+		return x.Results
 	}
-	return false
+	return nil
 }
 
-func (x *Event) GetClinic() string {
+type SearchResult struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Event                *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Rank                 float64                `protobuf:"fixed64,2,opt,name=rank,proto3" json:"rank,omitempty"`                               // higher is better; the scale depends on the storage
+	TitleHighlight       string                 `protobuf:"bytes,3,opt,name=titleHighlight,proto3" json:"titleHighlight,omitempty"`             // the title with matches in <b></b>
+	DescriptionHighlight string                 `protobuf:"bytes,4,opt,name=descriptionHighlight,proto3" json:"descriptionHighlight,omitempty"` // a fragment of the description with matches in <b></b>
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_EventService_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[13]
 	if x != nil {
-		return x.Clinic
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SearchResult) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *SearchResult) GetRank() float64 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *SearchResult) GetTitleHighlight() string {
+	if x != nil {
+		return x.TitleHighlight
 	}
 	return ""
 }
 
-func (x *Event) GetUserId() int32 {
+func (x *SearchResult) GetDescriptionHighlight() string {
 	if x != nil {
-		return x.UserId
+		return x.DescriptionHighlight
+	}
+	return ""
+}
+
+type Category struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"` // "#rrggbb"; optional
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_EventService_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Category) GetId() int32 {
+	if x != nil {
+		return x.Id
 	}
 	return 0
 }
 
-func (x *Event) GetService() string {
+func (x *Category) GetName() string {
 	if x != nil {
-		return x.Service
+		return x.Name
 	}
 	return ""
 }
 
-var File_EventService_proto protoreflect.FileDescriptor
+func (x *Category) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
 
-const file_EventService_proto_rawDesc = "" +
-	"\n" +
-	"\x12EventService.proto\x12\fcalendarGRPC\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/descriptor.proto\x1a\x15google/api/http.proto\"(\n" +
-	"\x0eHealthResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"?\n" +
-	"\x12CreateEventRequest\x12)\n" +
-	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\"E\n" +
-	"\x13CreateEventResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"A\n" +
-	"\x12ListEventsResponse\x12+\n" +
-	"\x06events\x18\x01 \x03(\v2\x13.calendarGRPC.EventR\x06events\"!\n" +
-	"\x0fGetEventRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"S\n" +
-	"\x10GetEventResponse\x12)\n" +
-	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"$\n" +
-	"\x12DeleteEventRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"E\n" +
-	"\x13DeleteEventResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"?\n" +
-	"\x12UpdateEventRequest\x12)\n" +
-	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\"E\n" +
-	"\x13UpdateEventResponse\x12\x18\n" +
+type CreateCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCategoryRequest) Reset() {
+	*x = CreateCategoryRequest{}
+	mi := &file_EventService_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCategoryRequest) ProtoMessage() {}
+
+func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*CreateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateCategoryRequest) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+type ListCategoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesRequest) Reset() {
+	*x = ListCategoriesRequest{}
+	mi := &file_EventService_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesRequest) ProtoMessage() {}
+
+func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{16}
+}
+
+type ListCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesResponse) Reset() {
+	*x = ListCategoriesResponse{}
+	mi := &file_EventService_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesResponse) ProtoMessage() {}
+
+func (x *ListCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListCategoriesResponse) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type UpdateCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCategoryRequest) Reset() {
+	*x = UpdateCategoryRequest{}
+	mi := &file_EventService_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCategoryRequest) ProtoMessage() {}
+
+func (x *UpdateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateCategoryRequest) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+type DeleteCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCategoryRequest) Reset() {
+	*x = DeleteCategoryRequest{}
+	mi := &file_EventService_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCategoryRequest) ProtoMessage() {}
+
+func (x *DeleteCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCategoryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteCategoryRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteCategoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCategoryResponse) Reset() {
+	*x = DeleteCategoryResponse{}
+	mi := &file_EventService_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCategoryResponse) ProtoMessage() {}
+
+func (x *DeleteCategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCategoryResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCategoryResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteCategoryResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type SetEventTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int32                  `protobuf:"varint,1,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"` // category names; empty removes all tags
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEventTagsRequest) Reset() {
+	*x = SetEventTagsRequest{}
+	mi := &file_EventService_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEventTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEventTagsRequest) ProtoMessage() {}
+
+func (x *SetEventTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEventTagsRequest.ProtoReflect.Descriptor instead.
+func (*SetEventTagsRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetEventTagsRequest) GetEventId() int32 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *SetEventTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SetEventTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEventTagsResponse) Reset() {
+	*x = SetEventTagsResponse{}
+	mi := &file_EventService_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEventTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEventTagsResponse) ProtoMessage() {}
+
+func (x *SetEventTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEventTagsResponse.ProtoReflect.Descriptor instead.
+func (*SetEventTagsResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetEventTagsResponse) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type InviteAttendeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int32                  `protobuf:"varint,1,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"` // "organizer", "required" (default) or "optional"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteAttendeeRequest) Reset() {
+	*x = InviteAttendeeRequest{}
+	mi := &file_EventService_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteAttendeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteAttendeeRequest) ProtoMessage() {}
+
+func (x *InviteAttendeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteAttendeeRequest.ProtoReflect.Descriptor instead.
+func (*InviteAttendeeRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *InviteAttendeeRequest) GetEventId() int32 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *InviteAttendeeRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *InviteAttendeeRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type InviteAttendeeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteAttendeeResponse) Reset() {
+	*x = InviteAttendeeResponse{}
+	mi := &file_EventService_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteAttendeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteAttendeeResponse) ProtoMessage() {}
+
+func (x *InviteAttendeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteAttendeeResponse.ProtoReflect.Descriptor instead.
+func (*InviteAttendeeResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *InviteAttendeeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *InviteAttendeeResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RespondInvitationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int32                  `protobuf:"varint,1,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"` // 0 = the authenticated caller
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`  // "accepted", "declined" or "tentative"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondInvitationRequest) Reset() {
+	*x = RespondInvitationRequest{}
+	mi := &file_EventService_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondInvitationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondInvitationRequest) ProtoMessage() {}
+
+func (x *RespondInvitationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondInvitationRequest.ProtoReflect.Descriptor instead.
+func (*RespondInvitationRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RespondInvitationRequest) GetEventId() int32 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *RespondInvitationRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RespondInvitationRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type RespondInvitationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondInvitationResponse) Reset() {
+	*x = RespondInvitationResponse{}
+	mi := &file_EventService_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondInvitationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondInvitationResponse) ProtoMessage() {}
+
+func (x *RespondInvitationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondInvitationResponse.ProtoReflect.Descriptor instead.
+func (*RespondInvitationResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RespondInvitationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RespondInvitationResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RemoveAttendeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int32                  `protobuf:"varint,1,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAttendeeRequest) Reset() {
+	*x = RemoveAttendeeRequest{}
+	mi := &file_EventService_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAttendeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAttendeeRequest) ProtoMessage() {}
+
+func (x *RemoveAttendeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAttendeeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveAttendeeRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RemoveAttendeeRequest) GetEventId() int32 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *RemoveAttendeeRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type RemoveAttendeeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAttendeeResponse) Reset() {
+	*x = RemoveAttendeeResponse{}
+	mi := &file_EventService_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAttendeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAttendeeResponse) ProtoMessage() {}
+
+func (x *RemoveAttendeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAttendeeResponse.ProtoReflect.Descriptor instead.
+func (*RemoveAttendeeResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RemoveAttendeeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RemoveAttendeeResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ExportICSRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"` // owner or attendee; 0 = all users
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`      // RFC3339; default: unbounded
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`          // RFC3339, exclusive; default: unbounded
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportICSRequest) Reset() {
+	*x = ExportICSRequest{}
+	mi := &file_EventService_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportICSRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportICSRequest) ProtoMessage() {}
+
+func (x *ExportICSRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportICSRequest.ProtoReflect.Descriptor instead.
+func (*ExportICSRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ExportICSRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ExportICSRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ExportICSRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type ImportICSRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Body          *httpbody.HttpBody     `protobuf:"bytes,1,opt,name=body,proto3" json:"body,omitempty"`      // text/calendar document
+	UserId        int32                  `protobuf:"varint,2,opt,name=userId,proto3" json:"userId,omitempty"` // owner of the created events
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportICSRequest) Reset() {
+	*x = ImportICSRequest{}
+	mi := &file_EventService_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportICSRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportICSRequest) ProtoMessage() {}
+
+func (x *ImportICSRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportICSRequest.ProtoReflect.Descriptor instead.
+func (*ImportICSRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ImportICSRequest) GetBody() *httpbody.HttpBody {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *ImportICSRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type ImportICSResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       int32                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Errors        []*ImportICSError      `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportICSResponse) Reset() {
+	*x = ImportICSResponse{}
+	mi := &file_EventService_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportICSResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportICSResponse) ProtoMessage() {}
+
+func (x *ImportICSResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportICSResponse.ProtoReflect.Descriptor instead.
+func (*ImportICSResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ImportICSResponse) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportICSResponse) GetErrors() []*ImportICSError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ImportICSError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // position of the VEVENT in the document, from 0
+	Uid           string                 `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportICSError) Reset() {
+	*x = ImportICSError{}
+	mi := &file_EventService_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportICSError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportICSError) ProtoMessage() {}
+
+func (x *ImportICSError) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportICSError.ProtoReflect.Descriptor instead.
+func (*ImportICSError) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ImportICSError) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportICSError) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *ImportICSError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type FindFreeSlotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []int32                `protobuf:"varint,1,rep,packed,name=userIds,proto3" json:"userIds,omitempty"`  // everyone has to be free
+	Clinic        string                 `protobuf:"bytes,2,opt,name=clinic,proto3" json:"clinic,omitempty"`            // the clinic has to be free
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`                // RFC3339
+	To            string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`                    // RFC3339, exclusive; at most 93 days after from
+	SlotMinutes   int32                  `protobuf:"varint,5,opt,name=slotMinutes,proto3" json:"slotMinutes,omitempty"` // length of the appointment
+	WorkStart     string                 `protobuf:"bytes,6,opt,name=workStart,proto3" json:"workStart,omitempty"`      // "HH:MM" local time; empty with workEnd = around the clock
+	WorkEnd       string                 `protobuf:"bytes,7,opt,name=workEnd,proto3" json:"workEnd,omitempty"`          // "HH:MM" local time, "24:00" for midnight
+	TimeZone      string                 `protobuf:"bytes,8,opt,name=timeZone,proto3" json:"timeZone,omitempty"`        // zone of the working hours and the response; default UTC
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindFreeSlotsRequest) Reset() {
+	*x = FindFreeSlotsRequest{}
+	mi := &file_EventService_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindFreeSlotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindFreeSlotsRequest) ProtoMessage() {}
+
+func (x *FindFreeSlotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindFreeSlotsRequest.ProtoReflect.Descriptor instead.
+func (*FindFreeSlotsRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *FindFreeSlotsRequest) GetUserIds() []int32 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *FindFreeSlotsRequest) GetClinic() string {
+	if x != nil {
+		return x.Clinic
+	}
+	return ""
+}
+
+func (x *FindFreeSlotsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *FindFreeSlotsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *FindFreeSlotsRequest) GetSlotMinutes() int32 {
+	if x != nil {
+		return x.SlotMinutes
+	}
+	return 0
+}
+
+func (x *FindFreeSlotsRequest) GetWorkStart() string {
+	if x != nil {
+		return x.WorkStart
+	}
+	return ""
+}
+
+func (x *FindFreeSlotsRequest) GetWorkEnd() string {
+	if x != nil {
+		return x.WorkEnd
+	}
+	return ""
+}
+
+func (x *FindFreeSlotsRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type FindFreeSlotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Free          []*TimeInterval        `protobuf:"bytes,1,rep,name=free,proto3" json:"free,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindFreeSlotsResponse) Reset() {
+	*x = FindFreeSlotsResponse{}
+	mi := &file_EventService_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindFreeSlotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindFreeSlotsResponse) ProtoMessage() {}
+
+func (x *FindFreeSlotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindFreeSlotsResponse.ProtoReflect.Descriptor instead.
+func (*FindFreeSlotsResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *FindFreeSlotsResponse) GetFree() []*TimeInterval {
+	if x != nil {
+		return x.Free
+	}
+	return nil
+}
+
+type FreeBusyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []int32                `protobuf:"varint,1,rep,packed,name=userIds,proto3" json:"userIds,omitempty"`
+	Clinic        string                 `protobuf:"bytes,2,opt,name=clinic,proto3" json:"clinic,omitempty"`
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`         // RFC3339
+	To            string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`             // RFC3339, exclusive; at most 93 days after from
+	TimeZone      string                 `protobuf:"bytes,5,opt,name=timeZone,proto3" json:"timeZone,omitempty"` // zone of the response times; default UTC
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreeBusyRequest) Reset() {
+	*x = FreeBusyRequest{}
+	mi := &file_EventService_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeBusyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeBusyRequest) ProtoMessage() {}
+
+func (x *FreeBusyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeBusyRequest.ProtoReflect.Descriptor instead.
+func (*FreeBusyRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *FreeBusyRequest) GetUserIds() []int32 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *FreeBusyRequest) GetClinic() string {
+	if x != nil {
+		return x.Clinic
+	}
+	return ""
+}
+
+func (x *FreeBusyRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *FreeBusyRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *FreeBusyRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type FreeBusyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Busy          []*TimeInterval        `protobuf:"bytes,1,rep,name=busy,proto3" json:"busy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreeBusyResponse) Reset() {
+	*x = FreeBusyResponse{}
+	mi := &file_EventService_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeBusyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeBusyResponse) ProtoMessage() {}
+
+func (x *FreeBusyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeBusyResponse.ProtoReflect.Descriptor instead.
+func (*FreeBusyResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *FreeBusyResponse) GetBusy() []*TimeInterval {
+	if x != nil {
+		return x.Busy
+	}
+	return nil
+}
+
+type TimeInterval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"` // RFC3339
+	End           string                 `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`     // RFC3339, exclusive
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeInterval) Reset() {
+	*x = TimeInterval{}
+	mi := &file_EventService_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeInterval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeInterval) ProtoMessage() {}
+
+func (x *TimeInterval) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeInterval.ProtoReflect.Descriptor instead.
+func (*TimeInterval) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *TimeInterval) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *TimeInterval) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type BatchCreateEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"` // at most 10000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateEventsRequest) Reset() {
+	*x = BatchCreateEventsRequest{}
+	mi := &file_EventService_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateEventsRequest) ProtoMessage() {}
+
+func (x *BatchCreateEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateEventsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateEventsRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *BatchCreateEventsRequest) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type BatchDeleteEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int32                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"` // at most 10000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteEventsRequest) Reset() {
+	*x = BatchDeleteEventsRequest{}
+	mi := &file_EventService_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteEventsRequest) ProtoMessage() {}
+
+func (x *BatchDeleteEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteEventsRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteEventsRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *BatchDeleteEventsRequest) GetIds() []int32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type BatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Applied       bool                   `protobuf:"varint,1,opt,name=applied,proto3" json:"applied,omitempty"` // false: nothing was changed
+	Results       []*BatchResult         `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResponse) Reset() {
+	*x = BatchResponse{}
+	mi := &file_EventService_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResponse) ProtoMessage() {}
+
+func (x *BatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BatchResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *BatchResponse) GetResults() []*BatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetEventHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int32                  `protobuf:"varint,1,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventHistoryRequest) Reset() {
+	*x = GetEventHistoryRequest{}
+	mi := &file_EventService_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventHistoryRequest) ProtoMessage() {}
+
+func (x *GetEventHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetEventHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetEventHistoryRequest) GetEventId() int32 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+type GetEventHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*HistoryEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventHistoryResponse) Reset() {
+	*x = GetEventHistoryResponse{}
+	mi := &file_EventService_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventHistoryResponse) ProtoMessage() {}
+
+func (x *GetEventHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetEventHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetEventHistoryResponse) GetEntries() []*HistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type HistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`            // "created", "updated", "deleted" or "restored"
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`              // authenticated subject, "anonymous" without authentication
+	ActorUserId   int32                  `protobuf:"varint,4,opt,name=actorUserId,proto3" json:"actorUserId,omitempty"` // calendar user of the actor; 0 if unknown
+	Time          string                 `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`                // RFC3339
+	Before        *Event                 `protobuf:"bytes,6,opt,name=before,proto3" json:"before,omitempty"`            // not set for "created" and "restored"
+	After         *Event                 `protobuf:"bytes,7,opt,name=after,proto3" json:"after,omitempty"`              // not set for "deleted"
+	Changes       []*FieldChange         `protobuf:"bytes,8,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryEntry) Reset() {
+	*x = HistoryEntry{}
+	mi := &file_EventService_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEntry) ProtoMessage() {}
+
+func (x *HistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEntry.ProtoReflect.Descriptor instead.
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *HistoryEntry) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetActorUserId() int32 {
+	if x != nil {
+		return x.ActorUserId
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetBefore() *Event {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *HistoryEntry) GetAfter() *Event {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *HistoryEntry) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type FieldChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`   // name of the Event field, e.g. "start"
+	Before        string                 `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"` // empty if unset
+	After         string                 `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`   // empty if unset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_EventService_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *FieldChange) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldChange) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *FieldChange) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+type ListDeletedEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeletedEventsRequest) Reset() {
+	*x = ListDeletedEventsRequest{}
+	mi := &file_EventService_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeletedEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeletedEventsRequest) ProtoMessage() {}
+
+func (x *ListDeletedEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeletedEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeletedEventsRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{45}
+}
+
+type RestoreEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreEventRequest) Reset() {
+	*x = RestoreEventRequest{}
+	mi := &file_EventService_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreEventRequest) ProtoMessage() {}
+
+func (x *RestoreEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreEventRequest.ProtoReflect.Descriptor instead.
+func (*RestoreEventRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RestoreEventRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RestoreEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreEventResponse) Reset() {
+	*x = RestoreEventResponse{}
+	mi := &file_EventService_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreEventResponse) ProtoMessage() {}
+
+func (x *RestoreEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreEventResponse.ProtoReflect.Descriptor instead.
+func (*RestoreEventResponse) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RestoreEventResponse) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type WatchEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"` // owner or attendee; 0 = the authenticated caller, or all users
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_EventService_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *WatchEventsRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type EventChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // "created", "updated" or "deleted"
+	EventId       int32                  `protobuf:"varint,2,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	Event         *Event                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"` // the event after the change; not set for "deleted"
+	Time          string                 `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`   // RFC3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventChange) Reset() {
+	*x = EventChange{}
+	mi := &file_EventService_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventChange) ProtoMessage() {}
+
+func (x *EventChange) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventChange.ProtoReflect.Descriptor instead.
+func (*EventChange) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *EventChange) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EventChange) GetEventId() int32 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *EventChange) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *EventChange) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+type BatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // position of the item in the request, from 0
+	Id            int32                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`       // created or deleted event
+	Success       bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_EventService_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *BatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchResult) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BatchResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *BatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ====== Event ======
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Start         string                 `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`    // ISO8601 datetime
+	End           string                 `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`        // optional; exclusive (for all-day events the day after the last day)
+	AllDay        bool                   `protobuf:"varint,6,opt,name=allDay,proto3" json:"allDay,omitempty"` // spans whole local dates of timeZone; only the dates of start/end are used
+	Clinic        string                 `protobuf:"bytes,7,opt,name=clinic,proto3" json:"clinic,omitempty"`
+	UserId        int32                  `protobuf:"varint,8,opt,name=userId,proto3" json:"userId,omitempty"`
+	Service       string                 `protobuf:"bytes,9,opt,name=service,proto3" json:"service,omitempty"`
+	Attendees     []*Attendee            `protobuf:"bytes,10,rep,name=attendees,proto3" json:"attendees,omitempty"` // read only; managed with InviteAttendee/RespondInvitation
+	TimeZone      string                 `protobuf:"bytes,11,opt,name=timeZone,proto3" json:"timeZone,omitempty"`   // IANA zone the event is planned in; default UTC
+	DeletedAt     string                 `protobuf:"bytes,12,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"` // read only; RFC3339, set for events in the trash
+	Tags          []string               `protobuf:"bytes,13,rep,name=tags,proto3" json:"tags,omitempty"`           // read only; category names, managed with SetEventTags
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_EventService_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *Event) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Event) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Event) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Event) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Event) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *Event) GetAllDay() bool {
+	if x != nil {
+		return x.AllDay
+	}
+	return false
+}
+
+func (x *Event) GetClinic() string {
+	if x != nil {
+		return x.Clinic
+	}
+	return ""
+}
+
+func (x *Event) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Event) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *Event) GetAttendees() []*Attendee {
+	if x != nil {
+		return x.Attendees
+	}
+	return nil
+}
+
+func (x *Event) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Event) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+func (x *Event) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type Attendee struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // "pending", "accepted", "declined" or "tentative"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attendee) Reset() {
+	*x = Attendee{}
+	mi := &file_EventService_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attendee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attendee) ProtoMessage() {}
+
+func (x *Attendee) ProtoReflect() protoreflect.Message {
+	mi := &file_EventService_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attendee.ProtoReflect.Descriptor instead.
+func (*Attendee) Descriptor() ([]byte, []int) {
+	return file_EventService_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *Attendee) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Attendee) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Attendee) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+var File_EventService_proto protoreflect.FileDescriptor
+
+const file_EventService_proto_rawDesc = "" +
+	"\n" +
+	"\x12EventService.proto\x12\fcalendarGRPC\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/descriptor.proto\x1a\x15google/api/http.proto\x1a\x19google/api/httpbody.proto\"(\n" +
+	"\x0eHealthResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"?\n" +
+	"\x12CreateEventRequest\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\"E\n" +
+	"\x13CreateEventResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"u\n" +
+	"\x11ListEventsRequest\x12\x1a\n" +
+	"\btimeZone\x18\x01 \x01(\tR\btimeZone\x12\x16\n" +
+	"\x06clinic\x18\x02 \x01(\tR\x06clinic\x12\x18\n" +
+	"\aservice\x18\x03 \x01(\tR\aservice\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\"A\n" +
+	"\x12ListEventsResponse\x12+\n" +
+	"\x06events\x18\x01 \x03(\v2\x13.calendarGRPC.EventR\x06events\"!\n" +
+	"\x0fGetEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"S\n" +
+	"\x10GetEventResponse\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"$\n" +
+	"\x12DeleteEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"E\n" +
+	"\x13DeleteEventResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"?\n" +
+	"\x12UpdateEventRequest\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\"E\n" +
+	"\x13UpdateEventResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"e\n" +
+	"\x13SearchEventsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"L\n" +
+	"\x14SearchEventsResponse\x124\n" +
+	"\aresults\x18\x01 \x03(\v2\x1a.calendarGRPC.SearchResultR\aresults\"\xa9\x01\n" +
+	"\fSearchResult\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x01R\x04rank\x12&\n" +
+	"\x0etitleHighlight\x18\x03 \x01(\tR\x0etitleHighlight\x122\n" +
+	"\x14descriptionHighlight\x18\x04 \x01(\tR\x14descriptionHighlight\"D\n" +
+	"\bCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\"K\n" +
+	"\x15CreateCategoryRequest\x122\n" +
+	"\bcategory\x18\x01 \x01(\v2\x16.calendarGRPC.CategoryR\bcategory\"\x17\n" +
+	"\x15ListCategoriesRequest\"P\n" +
+	"\x16ListCategoriesResponse\x126\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x16.calendarGRPC.CategoryR\n" +
+	"categories\"K\n" +
+	"\x15UpdateCategoryRequest\x122\n" +
+	"\bcategory\x18\x01 \x01(\v2\x16.calendarGRPC.CategoryR\bcategory\"'\n" +
+	"\x15DeleteCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"2\n" +
+	"\x16DeleteCategoryResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"C\n" +
+	"\x13SetEventTagsRequest\x12\x18\n" +
+	"\aeventId\x18\x01 \x01(\x05R\aeventId\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\"A\n" +
+	"\x14SetEventTagsResponse\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\"]\n" +
+	"\x15InviteAttendeeRequest\x12\x18\n" +
+	"\aeventId\x18\x01 \x01(\x05R\aeventId\x12\x16\n" +
+	"\x06userId\x18\x02 \x01(\x05R\x06userId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"H\n" +
+	"\x16InviteAttendeeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"d\n" +
+	"\x18RespondInvitationRequest\x12\x18\n" +
+	"\aeventId\x18\x01 \x01(\x05R\aeventId\x12\x16\n" +
+	"\x06userId\x18\x02 \x01(\x05R\x06userId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\"K\n" +
+	"\x19RespondInvitationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"I\n" +
+	"\x15RemoveAttendeeRequest\x12\x18\n" +
+	"\aeventId\x18\x01 \x01(\x05R\aeventId\x12\x16\n" +
+	"\x06userId\x18\x02 \x01(\x05R\x06userId\"H\n" +
+	"\x16RemoveAttendeeResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"\xd9\x01\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"N\n" +
+	"\x10ExportICSRequest\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\x05R\x06userId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\"T\n" +
+	"\x10ImportICSRequest\x12(\n" +
+	"\x04body\x18\x01 \x01(\v2\x14.google.api.HttpBodyR\x04body\x12\x16\n" +
+	"\x06userId\x18\x02 \x01(\x05R\x06userId\"c\n" +
+	"\x11ImportICSResponse\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x05R\acreated\x124\n" +
+	"\x06errors\x18\x02 \x03(\v2\x1c.calendarGRPC.ImportICSErrorR\x06errors\"N\n" +
+	"\x0eImportICSError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x10\n" +
+	"\x03uid\x18\x02 \x01(\tR\x03uid\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xe2\x01\n" +
+	"\x14FindFreeSlotsRequest\x12\x18\n" +
+	"\auserIds\x18\x01 \x03(\x05R\auserIds\x12\x16\n" +
+	"\x06clinic\x18\x02 \x01(\tR\x06clinic\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12 \n" +
+	"\vslotMinutes\x18\x05 \x01(\x05R\vslotMinutes\x12\x1c\n" +
+	"\tworkStart\x18\x06 \x01(\tR\tworkStart\x12\x18\n" +
+	"\aworkEnd\x18\a \x01(\tR\aworkEnd\x12\x1a\n" +
+	"\btimeZone\x18\b \x01(\tR\btimeZone\"G\n" +
+	"\x15FindFreeSlotsResponse\x12.\n" +
+	"\x04free\x18\x01 \x03(\v2\x1a.calendarGRPC.TimeIntervalR\x04free\"\x83\x01\n" +
+	"\x0fFreeBusyRequest\x12\x18\n" +
+	"\auserIds\x18\x01 \x03(\x05R\auserIds\x12\x16\n" +
+	"\x06clinic\x18\x02 \x01(\tR\x06clinic\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x1a\n" +
+	"\btimeZone\x18\x05 \x01(\tR\btimeZone\"B\n" +
+	"\x10FreeBusyResponse\x12.\n" +
+	"\x04busy\x18\x01 \x03(\v2\x1a.calendarGRPC.TimeIntervalR\x04busy\"6\n" +
+	"\fTimeInterval\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\"G\n" +
+	"\x18BatchCreateEventsRequest\x12+\n" +
+	"\x06events\x18\x01 \x03(\v2\x13.calendarGRPC.EventR\x06events\",\n" +
+	"\x18BatchDeleteEventsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x05R\x03ids\"^\n" +
+	"\rBatchResponse\x12\x18\n" +
+	"\aapplied\x18\x01 \x01(\bR\aapplied\x123\n" +
+	"\aresults\x18\x02 \x03(\v2\x19.calendarGRPC.BatchResultR\aresults\"2\n" +
+	"\x16GetEventHistoryRequest\x12\x18\n" +
+	"\aeventId\x18\x01 \x01(\x05R\aeventId\"O\n" +
+	"\x17GetEventHistoryResponse\x124\n" +
+	"\aentries\x18\x01 \x03(\v2\x1a.calendarGRPC.HistoryEntryR\aentries\"\x8f\x02\n" +
+	"\fHistoryEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12 \n" +
+	"\vactorUserId\x18\x04 \x01(\x05R\vactorUserId\x12\x12\n" +
+	"\x04time\x18\x05 \x01(\tR\x04time\x12+\n" +
+	"\x06before\x18\x06 \x01(\v2\x13.calendarGRPC.EventR\x06before\x12)\n" +
+	"\x05after\x18\a \x01(\v2\x13.calendarGRPC.EventR\x05after\x123\n" +
+	"\achanges\x18\b \x03(\v2\x19.calendarGRPC.FieldChangeR\achanges\"Q\n" +
+	"\vFieldChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x16\n" +
+	"\x06before\x18\x02 \x01(\tR\x06before\x12\x14\n" +
+	"\x05after\x18\x03 \x01(\tR\x05after\"\x1a\n" +
+	"\x18ListDeletedEventsRequest\"%\n" +
+	"\x13RestoreEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"A\n" +
+	"\x14RestoreEventResponse\x12)\n" +
+	"\x05event\x18\x01 \x01(\v2\x13.calendarGRPC.EventR\x05event\",\n" +
+	"\x12WatchEventsRequest\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\x05R\x06userId\"z\n" +
+	"\vEventChange\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\aeventId\x18\x02 \x01(\x05R\aeventId\x12)\n" +
+	"\x05event\x18\x03 \x01(\v2\x13.calendarGRPC.EventR\x05event\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\tR\x04time\"c\n" +
+	"\vBatchResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x05R\x02id\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xdd\x02\n" +
 	"\x05Event\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
@@ -645,18 +3094,47 @@ const file_EventService_proto_rawDesc = "" +
 	"\x06allDay\x18\x06 \x01(\bR\x06allDay\x12\x16\n" +
 	"\x06clinic\x18\a \x01(\tR\x06clinic\x12\x16\n" +
 	"\x06userId\x18\b \x01(\x05R\x06userId\x12\x18\n" +
-	"\aservice\x18\t \x01(\tR\aservice2\xa6\a\n" +
+	"\aservice\x18\t \x01(\tR\aservice\x124\n" +
+	"\tattendees\x18\n" +
+	" \x03(\v2\x16.calendarGRPC.AttendeeR\tattendees\x12\x1a\n" +
+	"\btimeZone\x18\v \x01(\tR\btimeZone\x12\x1c\n" +
+	"\tdeletedAt\x18\f \x01(\tR\tdeletedAt\x12\x12\n" +
+	"\x04tags\x18\r \x03(\tR\x04tags\"N\n" +
+	"\bAttendee\x12\x16\n" +
+	"\x06userId\x18\x01 \x01(\x05R\x06userId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status2\xdd\x18\n" +
 	"\x0fCalendarService\x12T\n" +
 	"\vHealthCheck\x12\x16.google.protobuf.Empty\x1a\x1c.calendarGRPC.HealthResponse\"\x0f\x82\xd3\xe4\x93\x02\t\x12\a/health\x12j\n" +
-	"\vCreateEvent\x12 .calendarGRPC.CreateEventRequest\x1a!.calendarGRPC.CreateEventResponse\"\x16\x82\xd3\xe4\x93\x02\x10\"\v/api/create:\x01*\x12[\n" +
+	"\vCreateEvent\x12 .calendarGRPC.CreateEventRequest\x1a!.calendarGRPC.CreateEventResponse\"\x16\x82\xd3\xe4\x93\x02\x10\"\v/api/create:\x01*\x12d\n" +
 	"\n" +
-	"ListEvents\x12\x16.google.protobuf.Empty\x1a .calendarGRPC.ListEventsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/api/events\x12a\n" +
-	"\rListEventsDay\x12\x16.google.protobuf.Empty\x1a .calendarGRPC.ListEventsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/api/eventsDay\x12c\n" +
-	"\x0eListEventsWeek\x12\x16.google.protobuf.Empty\x1a .calendarGRPC.ListEventsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/api/eventsWeek\x12e\n" +
-	"\x0fListEventsMonth\x12\x16.google.protobuf.Empty\x1a .calendarGRPC.ListEventsResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api/eventsMonth\x12`\n" +
+	"ListEvents\x12\x1f.calendarGRPC.ListEventsRequest\x1a .calendarGRPC.ListEventsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/api/events\x12j\n" +
+	"\rListEventsDay\x12\x1f.calendarGRPC.ListEventsRequest\x1a .calendarGRPC.ListEventsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/api/eventsDay\x12l\n" +
+	"\x0eListEventsWeek\x12\x1f.calendarGRPC.ListEventsRequest\x1a .calendarGRPC.ListEventsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/api/eventsWeek\x12n\n" +
+	"\x0fListEventsMonth\x12\x1f.calendarGRPC.ListEventsRequest\x1a .calendarGRPC.ListEventsResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api/eventsMonth\x12`\n" +
 	"\bGetEvent\x12\x1d.calendarGRPC.GetEventRequest\x1a\x1e.calendarGRPC.GetEventResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/api/get/{id}\x12l\n" +
 	"\vDeleteEvent\x12 .calendarGRPC.DeleteEventRequest\x1a!.calendarGRPC.DeleteEventResponse\"\x18\x82\xd3\xe4\x93\x02\x12*\x10/api/delete/{id}\x12u\n" +
-	"\vUpdateEvent\x12 .calendarGRPC.UpdateEventRequest\x1a!.calendarGRPC.UpdateEventResponse\"!\x82\xd3\xe4\x93\x02\x1b\x1a\x16/api/update/{event.id}:\x01*B\x14Z\x12calendarGRPC/pb;pbb\x06proto3"
+	"\vUpdateEvent\x12 .calendarGRPC.UpdateEventRequest\x1a!.calendarGRPC.UpdateEventResponse\"!\x82\xd3\xe4\x93\x02\x1b\x1a\x16/api/update/{event.id}:\x01*\x12s\n" +
+	"\x0eInviteAttendee\x12#.calendarGRPC.InviteAttendeeRequest\x1a$.calendarGRPC.InviteAttendeeResponse\"\x16\x82\xd3\xe4\x93\x02\x10\"\v/api/invite:\x01*\x12}\n" +
+	"\x11RespondInvitation\x12&.calendarGRPC.RespondInvitationRequest\x1a'.calendarGRPC.RespondInvitationResponse\"\x17\x82\xd3\xe4\x93\x02\x11\"\f/api/respond:\x01*\x12\x85\x01\n" +
+	"\x0eRemoveAttendee\x12#.calendarGRPC.RemoveAttendeeRequest\x1a$.calendarGRPC.RemoveAttendeeResponse\"(\x82\xd3\xe4\x93\x02\"* /api/attendee/{eventId}/{userId}\x12j\n" +
+	"\fSearchEvents\x12!.calendarGRPC.SearchEventsRequest\x1a\".calendarGRPC.SearchEventsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/api/search\x12p\n" +
+	"\x0eCreateCategory\x12#.calendarGRPC.CreateCategoryRequest\x1a\x16.calendarGRPC.Category\"!\x82\xd3\xe4\x93\x02\x1b\"\x0f/api/categories:\bcategory\x12t\n" +
+	"\x0eListCategories\x12#.calendarGRPC.ListCategoriesRequest\x1a$.calendarGRPC.ListCategoriesResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/api/categories\x12~\n" +
+	"\x0eUpdateCategory\x12#.calendarGRPC.UpdateCategoryRequest\x1a\x16.calendarGRPC.Category\"/\x82\xd3\xe4\x93\x02)\x1a\x1d/api/categories/{category.id}:\bcategory\x12y\n" +
+	"\x0eDeleteCategory\x12#.calendarGRPC.DeleteCategoryRequest\x1a$.calendarGRPC.DeleteCategoryResponse\"\x1c\x82\xd3\xe4\x93\x02\x16*\x14/api/categories/{id}\x12u\n" +
+	"\fSetEventTags\x12!.calendarGRPC.SetEventTagsRequest\x1a\".calendarGRPC.SetEventTagsResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x1a\x13/api/tags/{eventId}:\x01*\x12Z\n" +
+	"\tExportICS\x12\x1e.calendarGRPC.ExportICSRequest\x1a\x14.google.api.HttpBody\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/api/ics/export\x12k\n" +
+	"\tImportICS\x12\x1e.calendarGRPC.ImportICSRequest\x1a\x1f.calendarGRPC.ImportICSResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\"\x0f/api/ics/import:\x04body\x12x\n" +
+	"\rFindFreeSlots\x12\".calendarGRPC.FindFreeSlotsRequest\x1a#.calendarGRPC.FindFreeSlotsResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/availability/free\x12i\n" +
+	"\bFreeBusy\x12\x1d.calendarGRPC.FreeBusyRequest\x1a\x1e.calendarGRPC.FreeBusyResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/availability/busy\x12v\n" +
+	"\x11BatchCreateEvents\x12&.calendarGRPC.BatchCreateEventsRequest\x1a\x1b.calendarGRPC.BatchResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\"\x11/api/batch/create:\x01*\x12v\n" +
+	"\x11BatchDeleteEvents\x12&.calendarGRPC.BatchDeleteEventsRequest\x1a\x1b.calendarGRPC.BatchResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\"\x11/api/batch/delete:\x01*\x12~\n" +
+	"\x0fGetEventHistory\x12$.calendarGRPC.GetEventHistoryRequest\x1a%.calendarGRPC.GetEventHistoryResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/history/{eventId}\x12q\n" +
+	"\x11ListDeletedEvents\x12&.calendarGRPC.ListDeletedEventsRequest\x1a .calendarGRPC.ListEventsResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/api/trash\x12p\n" +
+	"\fRestoreEvent\x12!.calendarGRPC.RestoreEventRequest\x1a\".calendarGRPC.RestoreEventResponse\"\x19\x82\xd3\xe4\x93\x02\x13\"\x11/api/restore/{id}\x12L\n" +
+	"\vWatchEvents\x12 .calendarGRPC.WatchEventsRequest\x1a\x19.calendarGRPC.EventChange0\x01B\x14Z\x12calendarGRPC/pb;pbb\x06proto3"
 
 var (
 	file_EventService_proto_rawDescOnce sync.Once
@@ -670,49 +3148,149 @@ func file_EventService_proto_rawDescGZIP() []byte {
 	return file_EventService_proto_rawDescData
 }
 
-var file_EventService_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_EventService_proto_msgTypes = make([]protoimpl.MessageInfo, 53)
 var file_EventService_proto_goTypes = []any{
-	(*HealthResponse)(nil),      // 0: calendarGRPC.HealthResponse
-	(*CreateEventRequest)(nil),  // 1: calendarGRPC.CreateEventRequest
-	(*CreateEventResponse)(nil), // 2: calendarGRPC.CreateEventResponse
-	(*ListEventsResponse)(nil),  // 3: calendarGRPC.ListEventsResponse
-	(*GetEventRequest)(nil),     // 4: calendarGRPC.GetEventRequest
-	(*GetEventResponse)(nil),    // 5: calendarGRPC.GetEventResponse
-	(*DeleteEventRequest)(nil),  // 6: calendarGRPC.DeleteEventRequest
-	(*DeleteEventResponse)(nil), // 7: calendarGRPC.DeleteEventResponse
-	(*UpdateEventRequest)(nil),  // 8: calendarGRPC.UpdateEventRequest
-	(*UpdateEventResponse)(nil), // 9: calendarGRPC.UpdateEventResponse
-	(*Event)(nil),               // 10: calendarGRPC.Event
-	(*emptypb.Empty)(nil),       // 11: google.protobuf.Empty
+	(*HealthResponse)(nil),            // 0: calendarGRPC.HealthResponse
+	(*CreateEventRequest)(nil),        // 1: calendarGRPC.CreateEventRequest
+	(*CreateEventResponse)(nil),       // 2: calendarGRPC.CreateEventResponse
+	(*ListEventsRequest)(nil),         // 3: calendarGRPC.ListEventsRequest
+	(*ListEventsResponse)(nil),        // 4: calendarGRPC.ListEventsResponse
+	(*GetEventRequest)(nil),           // 5: calendarGRPC.GetEventRequest
+	(*GetEventResponse)(nil),          // 6: calendarGRPC.GetEventResponse
+	(*DeleteEventRequest)(nil),        // 7: calendarGRPC.DeleteEventRequest
+	(*DeleteEventResponse)(nil),       // 8: calendarGRPC.DeleteEventResponse
+	(*UpdateEventRequest)(nil),        // 9: calendarGRPC.UpdateEventRequest
+	(*UpdateEventResponse)(nil),       // 10: calendarGRPC.UpdateEventResponse
+	(*SearchEventsRequest)(nil),       // 11: calendarGRPC.SearchEventsRequest
+	(*SearchEventsResponse)(nil),      // 12: calendarGRPC.SearchEventsResponse
+	(*SearchResult)(nil),              // 13: calendarGRPC.SearchResult
+	(*Category)(nil),                  // 14: calendarGRPC.Category
+	(*CreateCategoryRequest)(nil),     // 15: calendarGRPC.CreateCategoryRequest
+	(*ListCategoriesRequest)(nil),     // 16: calendarGRPC.ListCategoriesRequest
+	(*ListCategoriesResponse)(nil),    // 17: calendarGRPC.ListCategoriesResponse
+	(*UpdateCategoryRequest)(nil),     // 18: calendarGRPC.UpdateCategoryRequest
+	(*DeleteCategoryRequest)(nil),     // 19: calendarGRPC.DeleteCategoryRequest
+	(*DeleteCategoryResponse)(nil),    // 20: calendarGRPC.DeleteCategoryResponse
+	(*SetEventTagsRequest)(nil),       // 21: calendarGRPC.SetEventTagsRequest
+	(*SetEventTagsResponse)(nil),      // 22: calendarGRPC.SetEventTagsResponse
+	(*InviteAttendeeRequest)(nil),     // 23: calendarGRPC.InviteAttendeeRequest
+	(*InviteAttendeeResponse)(nil),    // 24: calendarGRPC.InviteAttendeeResponse
+	(*RespondInvitationRequest)(nil),  // 25: calendarGRPC.RespondInvitationRequest
+	(*RespondInvitationResponse)(nil), // 26: calendarGRPC.RespondInvitationResponse
+	(*RemoveAttendeeRequest)(nil),     // 27: calendarGRPC.RemoveAttendeeRequest
+	(*RemoveAttendeeResponse)(nil),    // 28: calendarGRPC.RemoveAttendeeResponse
+	(*ExportICSRequest)(nil),          // 29: calendarGRPC.ExportICSRequest
+	(*ImportICSRequest)(nil),          // 30: calendarGRPC.ImportICSRequest
+	(*ImportICSResponse)(nil),         // 31: calendarGRPC.ImportICSResponse
+	(*ImportICSError)(nil),            // 32: calendarGRPC.ImportICSError
+	(*FindFreeSlotsRequest)(nil),      // 33: calendarGRPC.FindFreeSlotsRequest
+	(*FindFreeSlotsResponse)(nil),     // 34: calendarGRPC.FindFreeSlotsResponse
+	(*FreeBusyRequest)(nil),           // 35: calendarGRPC.FreeBusyRequest
+	(*FreeBusyResponse)(nil),          // 36: calendarGRPC.FreeBusyResponse
+	(*TimeInterval)(nil),              // 37: calendarGRPC.TimeInterval
+	(*BatchCreateEventsRequest)(nil),  // 38: calendarGRPC.BatchCreateEventsRequest
+	(*BatchDeleteEventsRequest)(nil),  // 39: calendarGRPC.BatchDeleteEventsRequest
+	(*BatchResponse)(nil),             // 40: calendarGRPC.BatchResponse
+	(*GetEventHistoryRequest)(nil),    // 41: calendarGRPC.GetEventHistoryRequest
+	(*GetEventHistoryResponse)(nil),   // 42: calendarGRPC.GetEventHistoryResponse
+	(*HistoryEntry)(nil),              // 43: calendarGRPC.HistoryEntry
+	(*FieldChange)(nil),               // 44: calendarGRPC.FieldChange
+	(*ListDeletedEventsRequest)(nil),  // 45: calendarGRPC.ListDeletedEventsRequest
+	(*RestoreEventRequest)(nil),       // 46: calendarGRPC.RestoreEventRequest
+	(*RestoreEventResponse)(nil),      // 47: calendarGRPC.RestoreEventResponse
+	(*WatchEventsRequest)(nil),        // 48: calendarGRPC.WatchEventsRequest
+	(*EventChange)(nil),               // 49: calendarGRPC.EventChange
+	(*BatchResult)(nil),               // 50: calendarGRPC.BatchResult
+	(*Event)(nil),                     // 51: calendarGRPC.Event
+	(*Attendee)(nil),                  // 52: calendarGRPC.Attendee
+	(*httpbody.HttpBody)(nil),         // 53: google.api.HttpBody
+	(*emptypb.Empty)(nil),             // 54: google.protobuf.Empty
 }
 var file_EventService_proto_depIdxs = []int32{
-	10, // 0: calendarGRPC.CreateEventRequest.event:type_name -> calendarGRPC.Event
-	10, // 1: calendarGRPC.ListEventsResponse.events:type_name -> calendarGRPC.Event
-	10, // 2: calendarGRPC.GetEventResponse.event:type_name -> calendarGRPC.Event
-	10, // 3: calendarGRPC.UpdateEventRequest.event:type_name -> calendarGRPC.Event
-	11, // 4: calendarGRPC.CalendarService.HealthCheck:input_type -> google.protobuf.Empty
-	1,  // 5: calendarGRPC.CalendarService.CreateEvent:input_type -> calendarGRPC.CreateEventRequest
-	11, // 6: calendarGRPC.CalendarService.ListEvents:input_type -> google.protobuf.Empty
-	11, // 7: calendarGRPC.CalendarService.ListEventsDay:input_type -> google.protobuf.Empty
-	11, // 8: calendarGRPC.CalendarService.ListEventsWeek:input_type -> google.protobuf.Empty
-	11, // 9: calendarGRPC.CalendarService.ListEventsMonth:input_type -> google.protobuf.Empty
-	4,  // 10: calendarGRPC.CalendarService.GetEvent:input_type -> calendarGRPC.GetEventRequest
-	6,  // 11: calendarGRPC.CalendarService.DeleteEvent:input_type -> calendarGRPC.DeleteEventRequest
-	8,  // 12: calendarGRPC.CalendarService.UpdateEvent:input_type -> calendarGRPC.UpdateEventRequest
-	0,  // 13: calendarGRPC.CalendarService.HealthCheck:output_type -> calendarGRPC.HealthResponse
-	2,  // 14: calendarGRPC.CalendarService.CreateEvent:output_type -> calendarGRPC.CreateEventResponse
-	3,  // 15: calendarGRPC.CalendarService.ListEvents:output_type -> calendarGRPC.ListEventsResponse
-	3,  // 16: calendarGRPC.CalendarService.ListEventsDay:output_type -> calendarGRPC.ListEventsResponse
-	3,  // 17: calendarGRPC.CalendarService.ListEventsWeek:output_type -> calendarGRPC.ListEventsResponse
-	3,  // 18: calendarGRPC.CalendarService.ListEventsMonth:output_type -> calendarGRPC.ListEventsResponse
-	5,  // 19: calendarGRPC.CalendarService.GetEvent:output_type -> calendarGRPC.GetEventResponse
-	7,  // 20: calendarGRPC.CalendarService.DeleteEvent:output_type -> calendarGRPC.DeleteEventResponse
-	9,  // 21: calendarGRPC.CalendarService.UpdateEvent:output_type -> calendarGRPC.UpdateEventResponse
-	13, // [13:22] is the sub-list for method output_type
-	4,  // [4:13] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	51, // 0: calendarGRPC.CreateEventRequest.event:type_name -> calendarGRPC.Event
+	51, // 1: calendarGRPC.ListEventsResponse.events:type_name -> calendarGRPC.Event
+	51, // 2: calendarGRPC.GetEventResponse.event:type_name -> calendarGRPC.Event
+	51, // 3: calendarGRPC.UpdateEventRequest.event:type_name -> calendarGRPC.Event
+	13, // 4: calendarGRPC.SearchEventsResponse.results:type_name -> calendarGRPC.SearchResult
+	51, // 5: calendarGRPC.SearchResult.event:type_name -> calendarGRPC.Event
+	14, // 6: calendarGRPC.CreateCategoryRequest.category:type_name -> calendarGRPC.Category
+	14, // 7: calendarGRPC.ListCategoriesResponse.categories:type_name -> calendarGRPC.Category
+	14, // 8: calendarGRPC.UpdateCategoryRequest.category:type_name -> calendarGRPC.Category
+	51, // 9: calendarGRPC.SetEventTagsResponse.event:type_name -> calendarGRPC.Event
+	53, // 10: calendarGRPC.ImportICSRequest.body:type_name -> google.api.HttpBody
+	32, // 11: calendarGRPC.ImportICSResponse.errors:type_name -> calendarGRPC.ImportICSError
+	37, // 12: calendarGRPC.FindFreeSlotsResponse.free:type_name -> calendarGRPC.TimeInterval
+	37, // 13: calendarGRPC.FreeBusyResponse.busy:type_name -> calendarGRPC.TimeInterval
+	51, // 14: calendarGRPC.BatchCreateEventsRequest.events:type_name -> calendarGRPC.Event
+	50, // 15: calendarGRPC.BatchResponse.results:type_name -> calendarGRPC.BatchResult
+	43, // 16: calendarGRPC.GetEventHistoryResponse.entries:type_name -> calendarGRPC.HistoryEntry
+	51, // 17: calendarGRPC.HistoryEntry.before:type_name -> calendarGRPC.Event
+	51, // 18: calendarGRPC.HistoryEntry.after:type_name -> calendarGRPC.Event
+	44, // 19: calendarGRPC.HistoryEntry.changes:type_name -> calendarGRPC.FieldChange
+	51, // 20: calendarGRPC.RestoreEventResponse.event:type_name -> calendarGRPC.Event
+	51, // 21: calendarGRPC.EventChange.event:type_name -> calendarGRPC.Event
+	52, // 22: calendarGRPC.Event.attendees:type_name -> calendarGRPC.Attendee
+	54, // 23: calendarGRPC.CalendarService.HealthCheck:input_type -> google.protobuf.Empty
+	1,  // 24: calendarGRPC.CalendarService.CreateEvent:input_type -> calendarGRPC.CreateEventRequest
+	3,  // 25: calendarGRPC.CalendarService.ListEvents:input_type -> calendarGRPC.ListEventsRequest
+	3,  // 26: calendarGRPC.CalendarService.ListEventsDay:input_type -> calendarGRPC.ListEventsRequest
+	3,  // 27: calendarGRPC.CalendarService.ListEventsWeek:input_type -> calendarGRPC.ListEventsRequest
+	3,  // 28: calendarGRPC.CalendarService.ListEventsMonth:input_type -> calendarGRPC.ListEventsRequest
+	5,  // 29: calendarGRPC.CalendarService.GetEvent:input_type -> calendarGRPC.GetEventRequest
+	7,  // 30: calendarGRPC.CalendarService.DeleteEvent:input_type -> calendarGRPC.DeleteEventRequest
+	9,  // 31: calendarGRPC.CalendarService.UpdateEvent:input_type -> calendarGRPC.UpdateEventRequest
+	23, // 32: calendarGRPC.CalendarService.InviteAttendee:input_type -> calendarGRPC.InviteAttendeeRequest
+	25, // 33: calendarGRPC.CalendarService.RespondInvitation:input_type -> calendarGRPC.RespondInvitationRequest
+	27, // 34: calendarGRPC.CalendarService.RemoveAttendee:input_type -> calendarGRPC.RemoveAttendeeRequest
+	11, // 35: calendarGRPC.CalendarService.SearchEvents:input_type -> calendarGRPC.SearchEventsRequest
+	15, // 36: calendarGRPC.CalendarService.CreateCategory:input_type -> calendarGRPC.CreateCategoryRequest
+	16, // 37: calendarGRPC.CalendarService.ListCategories:input_type -> calendarGRPC.ListCategoriesRequest
+	18, // 38: calendarGRPC.CalendarService.UpdateCategory:input_type -> calendarGRPC.UpdateCategoryRequest
+	19, // 39: calendarGRPC.CalendarService.DeleteCategory:input_type -> calendarGRPC.DeleteCategoryRequest
+	21, // 40: calendarGRPC.CalendarService.SetEventTags:input_type -> calendarGRPC.SetEventTagsRequest
+	29, // 41: calendarGRPC.CalendarService.ExportICS:input_type -> calendarGRPC.ExportICSRequest
+	30, // 42: calendarGRPC.CalendarService.ImportICS:input_type -> calendarGRPC.ImportICSRequest
+	33, // 43: calendarGRPC.CalendarService.FindFreeSlots:input_type -> calendarGRPC.FindFreeSlotsRequest
+	35, // 44: calendarGRPC.CalendarService.FreeBusy:input_type -> calendarGRPC.FreeBusyRequest
+	38, // 45: calendarGRPC.CalendarService.BatchCreateEvents:input_type -> calendarGRPC.BatchCreateEventsRequest
+	39, // 46: calendarGRPC.CalendarService.BatchDeleteEvents:input_type -> calendarGRPC.BatchDeleteEventsRequest
+	41, // 47: calendarGRPC.CalendarService.GetEventHistory:input_type -> calendarGRPC.GetEventHistoryRequest
+	45, // 48: calendarGRPC.CalendarService.ListDeletedEvents:input_type -> calendarGRPC.ListDeletedEventsRequest
+	46, // 49: calendarGRPC.CalendarService.RestoreEvent:input_type -> calendarGRPC.RestoreEventRequest
+	48, // 50: calendarGRPC.CalendarService.WatchEvents:input_type -> calendarGRPC.WatchEventsRequest
+	0,  // 51: calendarGRPC.CalendarService.HealthCheck:output_type -> calendarGRPC.HealthResponse
+	2,  // 52: calendarGRPC.CalendarService.CreateEvent:output_type -> calendarGRPC.CreateEventResponse
+	4,  // 53: calendarGRPC.CalendarService.ListEvents:output_type -> calendarGRPC.ListEventsResponse
+	4,  // 54: calendarGRPC.CalendarService.ListEventsDay:output_type -> calendarGRPC.ListEventsResponse
+	4,  // 55: calendarGRPC.CalendarService.ListEventsWeek:output_type -> calendarGRPC.ListEventsResponse
+	4,  // 56: calendarGRPC.CalendarService.ListEventsMonth:output_type -> calendarGRPC.ListEventsResponse
+	6,  // 57: calendarGRPC.CalendarService.GetEvent:output_type -> calendarGRPC.GetEventResponse
+	8,  // 58: calendarGRPC.CalendarService.DeleteEvent:output_type -> calendarGRPC.DeleteEventResponse
+	10, // 59: calendarGRPC.CalendarService.UpdateEvent:output_type -> calendarGRPC.UpdateEventResponse
+	24, // 60: calendarGRPC.CalendarService.InviteAttendee:output_type -> calendarGRPC.InviteAttendeeResponse
+	26, // 61: calendarGRPC.CalendarService.RespondInvitation:output_type -> calendarGRPC.RespondInvitationResponse
+	28, // 62: calendarGRPC.CalendarService.RemoveAttendee:output_type -> calendarGRPC.RemoveAttendeeResponse
+	12, // 63: calendarGRPC.CalendarService.SearchEvents:output_type -> calendarGRPC.SearchEventsResponse
+	14, // 64: calendarGRPC.CalendarService.CreateCategory:output_type -> calendarGRPC.Category
+	17, // 65: calendarGRPC.CalendarService.ListCategories:output_type -> calendarGRPC.ListCategoriesResponse
+	14, // 66: calendarGRPC.CalendarService.UpdateCategory:output_type -> calendarGRPC.Category
+	20, // 67: calendarGRPC.CalendarService.DeleteCategory:output_type -> calendarGRPC.DeleteCategoryResponse
+	22, // 68: calendarGRPC.CalendarService.SetEventTags:output_type -> calendarGRPC.SetEventTagsResponse
+	53, // 69: calendarGRPC.CalendarService.ExportICS:output_type -> google.api.HttpBody
+	31, // 70: calendarGRPC.CalendarService.ImportICS:output_type -> calendarGRPC.ImportICSResponse
+	34, // 71: calendarGRPC.CalendarService.FindFreeSlots:output_type -> calendarGRPC.FindFreeSlotsResponse
+	36, // 72: calendarGRPC.CalendarService.FreeBusy:output_type -> calendarGRPC.FreeBusyResponse
+	40, // 73: calendarGRPC.CalendarService.BatchCreateEvents:output_type -> calendarGRPC.BatchResponse
+	40, // 74: calendarGRPC.CalendarService.BatchDeleteEvents:output_type -> calendarGRPC.BatchResponse
+	42, // 75: calendarGRPC.CalendarService.GetEventHistory:output_type -> calendarGRPC.GetEventHistoryResponse
+	4,  // 76: calendarGRPC.CalendarService.ListDeletedEvents:output_type -> calendarGRPC.ListEventsResponse
+	47, // 77: calendarGRPC.CalendarService.RestoreEvent:output_type -> calendarGRPC.RestoreEventResponse
+	49, // 78: calendarGRPC.CalendarService.WatchEvents:output_type -> calendarGRPC.EventChange
+	51, // [51:79] is the sub-list for method output_type
+	23, // [23:51] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_EventService_proto_init() }
@@ -726,7 +3304,7 @@ func file_EventService_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_EventService_proto_rawDesc), len(file_EventService_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   53,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -9,6 +9,7 @@ package pb
 import (
 	context "context"
 
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -21,15 +22,34 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CalendarService_HealthCheck_FullMethodName     = "/calendarGRPC.CalendarService/HealthCheck"
-	CalendarService_CreateEvent_FullMethodName     = "/calendarGRPC.CalendarService/CreateEvent"
-	CalendarService_ListEvents_FullMethodName      = "/calendarGRPC.CalendarService/ListEvents"
-	CalendarService_ListEventsDay_FullMethodName   = "/calendarGRPC.CalendarService/ListEventsDay"
-	CalendarService_ListEventsWeek_FullMethodName  = "/calendarGRPC.CalendarService/ListEventsWeek"
-	CalendarService_ListEventsMonth_FullMethodName = "/calendarGRPC.CalendarService/ListEventsMonth"
-	CalendarService_GetEvent_FullMethodName        = "/calendarGRPC.CalendarService/GetEvent"
-	CalendarService_DeleteEvent_FullMethodName     = "/calendarGRPC.CalendarService/DeleteEvent"
-	CalendarService_UpdateEvent_FullMethodName     = "/calendarGRPC.CalendarService/UpdateEvent"
+	CalendarService_HealthCheck_FullMethodName       = "/calendarGRPC.CalendarService/HealthCheck"
+	CalendarService_CreateEvent_FullMethodName       = "/calendarGRPC.CalendarService/CreateEvent"
+	CalendarService_ListEvents_FullMethodName        = "/calendarGRPC.CalendarService/ListEvents"
+	CalendarService_ListEventsDay_FullMethodName     = "/calendarGRPC.CalendarService/ListEventsDay"
+	CalendarService_ListEventsWeek_FullMethodName    = "/calendarGRPC.CalendarService/ListEventsWeek"
+	CalendarService_ListEventsMonth_FullMethodName   = "/calendarGRPC.CalendarService/ListEventsMonth"
+	CalendarService_GetEvent_FullMethodName          = "/calendarGRPC.CalendarService/GetEvent"
+	CalendarService_DeleteEvent_FullMethodName       = "/calendarGRPC.CalendarService/DeleteEvent"
+	CalendarService_UpdateEvent_FullMethodName       = "/calendarGRPC.CalendarService/UpdateEvent"
+	CalendarService_InviteAttendee_FullMethodName    = "/calendarGRPC.CalendarService/InviteAttendee"
+	CalendarService_RespondInvitation_FullMethodName = "/calendarGRPC.CalendarService/RespondInvitation"
+	CalendarService_RemoveAttendee_FullMethodName    = "/calendarGRPC.CalendarService/RemoveAttendee"
+	CalendarService_SearchEvents_FullMethodName      = "/calendarGRPC.CalendarService/SearchEvents"
+	CalendarService_CreateCategory_FullMethodName    = "/calendarGRPC.CalendarService/CreateCategory"
+	CalendarService_ListCategories_FullMethodName    = "/calendarGRPC.CalendarService/ListCategories"
+	CalendarService_UpdateCategory_FullMethodName    = "/calendarGRPC.CalendarService/UpdateCategory"
+	CalendarService_DeleteCategory_FullMethodName    = "/calendarGRPC.CalendarService/DeleteCategory"
+	CalendarService_SetEventTags_FullMethodName      = "/calendarGRPC.CalendarService/SetEventTags"
+	CalendarService_ExportICS_FullMethodName         = "/calendarGRPC.CalendarService/ExportICS"
+	CalendarService_ImportICS_FullMethodName         = "/calendarGRPC.CalendarService/ImportICS"
+	CalendarService_FindFreeSlots_FullMethodName     = "/calendarGRPC.CalendarService/FindFreeSlots"
+	CalendarService_FreeBusy_FullMethodName          = "/calendarGRPC.CalendarService/FreeBusy"
+	CalendarService_BatchCreateEvents_FullMethodName = "/calendarGRPC.CalendarService/BatchCreateEvents"
+	CalendarService_BatchDeleteEvents_FullMethodName = "/calendarGRPC.CalendarService/BatchDeleteEvents"
+	CalendarService_GetEventHistory_FullMethodName   = "/calendarGRPC.CalendarService/GetEventHistory"
+	CalendarService_ListDeletedEvents_FullMethodName = "/calendarGRPC.CalendarService/ListDeletedEvents"
+	CalendarService_RestoreEvent_FullMethodName      = "/calendarGRPC.CalendarService/RestoreEvent"
+	CalendarService_WatchEvents_FullMethodName       = "/calendarGRPC.CalendarService/WatchEvents"
 )
 
 // CalendarServiceClient is the client API for CalendarService service.
@@ -40,13 +60,49 @@ const (
 type CalendarServiceClient interface {
 	HealthCheck(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*HealthResponse, error)
 	CreateEvent(ctx context.Context, in *CreateEventRequest, opts ...grpc.CallOption) (*CreateEventResponse, error)
-	ListEvents(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEventsResponse, error)
-	ListEventsDay(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEventsResponse, error)
-	ListEventsWeek(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEventsResponse, error)
-	ListEventsMonth(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	ListEventsDay(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	ListEventsWeek(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	ListEventsMonth(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
 	GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*GetEventResponse, error)
 	DeleteEvent(ctx context.Context, in *DeleteEventRequest, opts ...grpc.CallOption) (*DeleteEventResponse, error)
 	UpdateEvent(ctx context.Context, in *UpdateEventRequest, opts ...grpc.CallOption) (*UpdateEventResponse, error)
+	InviteAttendee(ctx context.Context, in *InviteAttendeeRequest, opts ...grpc.CallOption) (*InviteAttendeeResponse, error)
+	RespondInvitation(ctx context.Context, in *RespondInvitationRequest, opts ...grpc.CallOption) (*RespondInvitationResponse, error)
+	RemoveAttendee(ctx context.Context, in *RemoveAttendeeRequest, opts ...grpc.CallOption) (*RemoveAttendeeResponse, error)
+	// Finds events by the words and "quoted phrases" of their titles and descriptions, best
+	// matches first, with the matched words highlighted.
+	SearchEvents(ctx context.Context, in *SearchEventsRequest, opts ...grpc.CallOption) (*SearchEventsResponse, error)
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error)
+	// Renames or recolors a category; its events carry the new name.
+	UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	// Deletes a category and removes its tag from all events.
+	DeleteCategory(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*DeleteCategoryResponse, error)
+	// Replaces the tags of an event; every tag must name a category.
+	SetEventTags(ctx context.Context, in *SetEventTagsRequest, opts ...grpc.CallOption) (*SetEventTagsResponse, error)
+	// Returns the events of a user and time range as a text/calendar document.
+	ExportICS(ctx context.Context, in *ExportICSRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	// Creates an event for every valid VEVENT of a text/calendar document.
+	ImportICS(ctx context.Context, in *ImportICSRequest, opts ...grpc.CallOption) (*ImportICSResponse, error)
+	// Returns the free intervals, within working hours, long enough for a slot.
+	FindFreeSlots(ctx context.Context, in *FindFreeSlotsRequest, opts ...grpc.CallOption) (*FindFreeSlotsResponse, error)
+	// Returns when the users or the clinic are busy, without event details.
+	FreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error)
+	// Creates all events or, if any of them is invalid, none.
+	BatchCreateEvents(ctx context.Context, in *BatchCreateEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	// Deletes all events or, if any of them does not exist, none.
+	BatchDeleteEvents(ctx context.Context, in *BatchDeleteEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	// Returns who created, changed and deleted an event, and when, oldest first.
+	GetEventHistory(ctx context.Context, in *GetEventHistoryRequest, opts ...grpc.CallOption) (*GetEventHistoryResponse, error)
+	// Lists the deleted events that have not been purged yet, most recently deleted first.
+	ListDeletedEvents(ctx context.Context, in *ListDeletedEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	// Takes a deleted event out of the trash or, once it has been purged, recreates it
+	// under its ID, with its attendees, from its history.
+	RestoreEvent(ctx context.Context, in *RestoreEventRequest, opts ...grpc.CallOption) (*RestoreEventResponse, error)
+	// Streams the changes to the events of a user, or of all users, as they happen.
+	// Over HTTP the stream is served as server-sent events at GET /api/events/watch.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventChange], error)
 }
 
 type calendarServiceClient struct {
@@ -77,7 +133,7 @@ func (c *calendarServiceClient) CreateEvent(ctx context.Context, in *CreateEvent
 	return out, nil
 }
 
-func (c *calendarServiceClient) ListEvents(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+func (c *calendarServiceClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListEventsResponse)
 	err := c.cc.Invoke(ctx, CalendarService_ListEvents_FullMethodName, in, out, cOpts...)
@@ -87,7 +143,7 @@ func (c *calendarServiceClient) ListEvents(ctx context.Context, in *emptypb.Empt
 	return out, nil
 }
 
-func (c *calendarServiceClient) ListEventsDay(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+func (c *calendarServiceClient) ListEventsDay(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListEventsResponse)
 	err := c.cc.Invoke(ctx, CalendarService_ListEventsDay_FullMethodName, in, out, cOpts...)
@@ -97,7 +153,7 @@ func (c *calendarServiceClient) ListEventsDay(ctx context.Context, in *emptypb.E
 	return out, nil
 }
 
-func (c *calendarServiceClient) ListEventsWeek(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+func (c *calendarServiceClient) ListEventsWeek(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListEventsResponse)
 	err := c.cc.Invoke(ctx, CalendarService_ListEventsWeek_FullMethodName, in, out, cOpts...)
@@ -107,7 +163,7 @@ func (c *calendarServiceClient) ListEventsWeek(ctx context.Context, in *emptypb.
 	return out, nil
 }
 
-func (c *calendarServiceClient) ListEventsMonth(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+func (c *calendarServiceClient) ListEventsMonth(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListEventsResponse)
 	err := c.cc.Invoke(ctx, CalendarService_ListEventsMonth_FullMethodName, in, out, cOpts...)
@@ -147,6 +203,205 @@ func (c *calendarServiceClient) UpdateEvent(ctx context.Context, in *UpdateEvent
 	return out, nil
 }
 
+func (c *calendarServiceClient) InviteAttendee(ctx context.Context, in *InviteAttendeeRequest, opts ...grpc.CallOption) (*InviteAttendeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InviteAttendeeResponse)
+	err := c.cc.Invoke(ctx, CalendarService_InviteAttendee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) RespondInvitation(ctx context.Context, in *RespondInvitationRequest, opts ...grpc.CallOption) (*RespondInvitationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RespondInvitationResponse)
+	err := c.cc.Invoke(ctx, CalendarService_RespondInvitation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) RemoveAttendee(ctx context.Context, in *RemoveAttendeeRequest, opts ...grpc.CallOption) (*RemoveAttendeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveAttendeeResponse)
+	err := c.cc.Invoke(ctx, CalendarService_RemoveAttendee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) SearchEvents(ctx context.Context, in *SearchEventsRequest, opts ...grpc.CallOption) (*SearchEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchEventsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_SearchEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, CalendarService_CreateCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCategoriesResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, CalendarService_UpdateCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) DeleteCategory(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*DeleteCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteCategoryResponse)
+	err := c.cc.Invoke(ctx, CalendarService_DeleteCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) SetEventTags(ctx context.Context, in *SetEventTagsRequest, opts ...grpc.CallOption) (*SetEventTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEventTagsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_SetEventTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ExportICS(ctx context.Context, in *ExportICSRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, CalendarService_ExportICS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ImportICS(ctx context.Context, in *ImportICSRequest, opts ...grpc.CallOption) (*ImportICSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportICSResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ImportICS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) FindFreeSlots(ctx context.Context, in *FindFreeSlotsRequest, opts ...grpc.CallOption) (*FindFreeSlotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindFreeSlotsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_FindFreeSlots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) FreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreeBusyResponse)
+	err := c.cc.Invoke(ctx, CalendarService_FreeBusy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) BatchCreateEvents(ctx context.Context, in *BatchCreateEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, CalendarService_BatchCreateEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) BatchDeleteEvents(ctx context.Context, in *BatchDeleteEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, CalendarService_BatchDeleteEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) GetEventHistory(ctx context.Context, in *GetEventHistoryRequest, opts ...grpc.CallOption) (*GetEventHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEventHistoryResponse)
+	err := c.cc.Invoke(ctx, CalendarService_GetEventHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListDeletedEvents(ctx context.Context, in *ListDeletedEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListDeletedEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) RestoreEvent(ctx context.Context, in *RestoreEventRequest, opts ...grpc.CallOption) (*RestoreEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreEventResponse)
+	err := c.cc.Invoke(ctx, CalendarService_RestoreEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalendarService_ServiceDesc.Streams[0], CalendarService_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, EventChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalendarService_WatchEventsClient = grpc.ServerStreamingClient[EventChange]
+
 // CalendarServiceServer is the server API for CalendarService service.
 // All implementations must embed UnimplementedCalendarServiceServer
 // for forward compatibility.
@@ -155,13 +410,49 @@ func (c *calendarServiceClient) UpdateEvent(ctx context.Context, in *UpdateEvent
 type CalendarServiceServer interface {
 	HealthCheck(context.Context, *emptypb.Empty) (*HealthResponse, error)
 	CreateEvent(context.Context, *CreateEventRequest) (*CreateEventResponse, error)
-	ListEvents(context.Context, *emptypb.Empty) (*ListEventsResponse, error)
-	ListEventsDay(context.Context, *emptypb.Empty) (*ListEventsResponse, error)
-	ListEventsWeek(context.Context, *emptypb.Empty) (*ListEventsResponse, error)
-	ListEventsMonth(context.Context, *emptypb.Empty) (*ListEventsResponse, error)
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	ListEventsDay(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	ListEventsWeek(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	ListEventsMonth(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
 	GetEvent(context.Context, *GetEventRequest) (*GetEventResponse, error)
 	DeleteEvent(context.Context, *DeleteEventRequest) (*DeleteEventResponse, error)
 	UpdateEvent(context.Context, *UpdateEventRequest) (*UpdateEventResponse, error)
+	InviteAttendee(context.Context, *InviteAttendeeRequest) (*InviteAttendeeResponse, error)
+	RespondInvitation(context.Context, *RespondInvitationRequest) (*RespondInvitationResponse, error)
+	RemoveAttendee(context.Context, *RemoveAttendeeRequest) (*RemoveAttendeeResponse, error)
+	// Finds events by the words and "quoted phrases" of their titles and descriptions, best
+	// matches first, with the matched words highlighted.
+	SearchEvents(context.Context, *SearchEventsRequest) (*SearchEventsResponse, error)
+	CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error)
+	ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error)
+	// Renames or recolors a category; its events carry the new name.
+	UpdateCategory(context.Context, *UpdateCategoryRequest) (*Category, error)
+	// Deletes a category and removes its tag from all events.
+	DeleteCategory(context.Context, *DeleteCategoryRequest) (*DeleteCategoryResponse, error)
+	// Replaces the tags of an event; every tag must name a category.
+	SetEventTags(context.Context, *SetEventTagsRequest) (*SetEventTagsResponse, error)
+	// Returns the events of a user and time range as a text/calendar document.
+	ExportICS(context.Context, *ExportICSRequest) (*httpbody.HttpBody, error)
+	// Creates an event for every valid VEVENT of a text/calendar document.
+	ImportICS(context.Context, *ImportICSRequest) (*ImportICSResponse, error)
+	// Returns the free intervals, within working hours, long enough for a slot.
+	FindFreeSlots(context.Context, *FindFreeSlotsRequest) (*FindFreeSlotsResponse, error)
+	// Returns when the users or the clinic are busy, without event details.
+	FreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error)
+	// Creates all events or, if any of them is invalid, none.
+	BatchCreateEvents(context.Context, *BatchCreateEventsRequest) (*BatchResponse, error)
+	// Deletes all events or, if any of them does not exist, none.
+	BatchDeleteEvents(context.Context, *BatchDeleteEventsRequest) (*BatchResponse, error)
+	// Returns who created, changed and deleted an event, and when, oldest first.
+	GetEventHistory(context.Context, *GetEventHistoryRequest) (*GetEventHistoryResponse, error)
+	// Lists the deleted events that have not been purged yet, most recently deleted first.
+	ListDeletedEvents(context.Context, *ListDeletedEventsRequest) (*ListEventsResponse, error)
+	// Takes a deleted event out of the trash or, once it has been purged, recreates it
+	// under its ID, with its attendees, from its history.
+	RestoreEvent(context.Context, *RestoreEventRequest) (*RestoreEventResponse, error)
+	// Streams the changes to the events of a user, or of all users, as they happen.
+	// Over HTTP the stream is served as server-sent events at GET /api/events/watch.
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[EventChange]) error
 	mustEmbedUnimplementedCalendarServiceServer()
 }
 
@@ -178,16 +469,16 @@ func (UnimplementedCalendarServiceServer) HealthCheck(context.Context, *emptypb.
 func (UnimplementedCalendarServiceServer) CreateEvent(context.Context, *CreateEventRequest) (*CreateEventResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateEvent not implemented")
 }
-func (UnimplementedCalendarServiceServer) ListEvents(context.Context, *emptypb.Empty) (*ListEventsResponse, error) {
+func (UnimplementedCalendarServiceServer) ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
 }
-func (UnimplementedCalendarServiceServer) ListEventsDay(context.Context, *emptypb.Empty) (*ListEventsResponse, error) {
+func (UnimplementedCalendarServiceServer) ListEventsDay(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListEventsDay not implemented")
 }
-func (UnimplementedCalendarServiceServer) ListEventsWeek(context.Context, *emptypb.Empty) (*ListEventsResponse, error) {
+func (UnimplementedCalendarServiceServer) ListEventsWeek(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListEventsWeek not implemented")
 }
-func (UnimplementedCalendarServiceServer) ListEventsMonth(context.Context, *emptypb.Empty) (*ListEventsResponse, error) {
+func (UnimplementedCalendarServiceServer) ListEventsMonth(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListEventsMonth not implemented")
 }
 func (UnimplementedCalendarServiceServer) GetEvent(context.Context, *GetEventRequest) (*GetEventResponse, error) {
@@ -199,6 +490,63 @@ func (UnimplementedCalendarServiceServer) DeleteEvent(context.Context, *DeleteEv
 func (UnimplementedCalendarServiceServer) UpdateEvent(context.Context, *UpdateEventRequest) (*UpdateEventResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateEvent not implemented")
 }
+func (UnimplementedCalendarServiceServer) InviteAttendee(context.Context, *InviteAttendeeRequest) (*InviteAttendeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteAttendee not implemented")
+}
+func (UnimplementedCalendarServiceServer) RespondInvitation(context.Context, *RespondInvitationRequest) (*RespondInvitationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondInvitation not implemented")
+}
+func (UnimplementedCalendarServiceServer) RemoveAttendee(context.Context, *RemoveAttendeeRequest) (*RemoveAttendeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveAttendee not implemented")
+}
+func (UnimplementedCalendarServiceServer) SearchEvents(context.Context, *SearchEventsRequest) (*SearchEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
+}
+func (UnimplementedCalendarServiceServer) UpdateCategory(context.Context, *UpdateCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCategory not implemented")
+}
+func (UnimplementedCalendarServiceServer) DeleteCategory(context.Context, *DeleteCategoryRequest) (*DeleteCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCategory not implemented")
+}
+func (UnimplementedCalendarServiceServer) SetEventTags(context.Context, *SetEventTagsRequest) (*SetEventTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEventTags not implemented")
+}
+func (UnimplementedCalendarServiceServer) ExportICS(context.Context, *ExportICSRequest) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportICS not implemented")
+}
+func (UnimplementedCalendarServiceServer) ImportICS(context.Context, *ImportICSRequest) (*ImportICSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportICS not implemented")
+}
+func (UnimplementedCalendarServiceServer) FindFreeSlots(context.Context, *FindFreeSlotsRequest) (*FindFreeSlotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindFreeSlots not implemented")
+}
+func (UnimplementedCalendarServiceServer) FreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreeBusy not implemented")
+}
+func (UnimplementedCalendarServiceServer) BatchCreateEvents(context.Context, *BatchCreateEventsRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) BatchDeleteEvents(context.Context, *BatchDeleteEventsRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) GetEventHistory(context.Context, *GetEventHistoryRequest) (*GetEventHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventHistory not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListDeletedEvents(context.Context, *ListDeletedEventsRequest) (*ListEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) RestoreEvent(context.Context, *RestoreEventRequest) (*RestoreEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEvent not implemented")
+}
+func (UnimplementedCalendarServiceServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[EventChange]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
 func (UnimplementedCalendarServiceServer) mustEmbedUnimplementedCalendarServiceServer() {}
 func (UnimplementedCalendarServiceServer) testEmbeddedByValue()                         {}
 
@@ -257,7 +605,7 @@ func _CalendarService_CreateEvent_Handler(srv interface{}, ctx context.Context,
 }
 
 func _CalendarService_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -269,13 +617,13 @@ func _CalendarService_ListEvents_Handler(srv interface{}, ctx context.Context, d
 		FullMethod: CalendarService_ListEvents_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalendarServiceServer).ListEvents(ctx, req.(*emptypb.Empty))
+		return srv.(CalendarServiceServer).ListEvents(ctx, req.(*ListEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _CalendarService_ListEventsDay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -287,13 +635,13 @@ func _CalendarService_ListEventsDay_Handler(srv interface{}, ctx context.Context
 		FullMethod: CalendarService_ListEventsDay_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalendarServiceServer).ListEventsDay(ctx, req.(*emptypb.Empty))
+		return srv.(CalendarServiceServer).ListEventsDay(ctx, req.(*ListEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _CalendarService_ListEventsWeek_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -305,13 +653,13 @@ func _CalendarService_ListEventsWeek_Handler(srv interface{}, ctx context.Contex
 		FullMethod: CalendarService_ListEventsWeek_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalendarServiceServer).ListEventsWeek(ctx, req.(*emptypb.Empty))
+		return srv.(CalendarServiceServer).ListEventsWeek(ctx, req.(*ListEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _CalendarService_ListEventsMonth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -323,7 +671,7 @@ func _CalendarService_ListEventsMonth_Handler(srv interface{}, ctx context.Conte
 		FullMethod: CalendarService_ListEventsMonth_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalendarServiceServer).ListEventsMonth(ctx, req.(*emptypb.Empty))
+		return srv.(CalendarServiceServer).ListEventsMonth(ctx, req.(*ListEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -382,6 +730,341 @@ func _CalendarService_UpdateEvent_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CalendarService_InviteAttendee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteAttendeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).InviteAttendee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_InviteAttendee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).InviteAttendee(ctx, req.(*InviteAttendeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_RespondInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).RespondInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_RespondInvitation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).RespondInvitation(ctx, req.(*RespondInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_RemoveAttendee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveAttendeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).RemoveAttendee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_RemoveAttendee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).RemoveAttendee(ctx, req.(*RemoveAttendeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_SearchEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).SearchEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_SearchEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).SearchEvents(ctx, req.(*SearchEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_CreateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListCategories(ctx, req.(*ListCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_UpdateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).UpdateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_UpdateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).UpdateCategory(ctx, req.(*UpdateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_DeleteCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).DeleteCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_DeleteCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).DeleteCategory(ctx, req.(*DeleteCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_SetEventTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEventTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).SetEventTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_SetEventTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).SetEventTags(ctx, req.(*SetEventTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ExportICS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportICSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ExportICS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ExportICS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ExportICS(ctx, req.(*ExportICSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ImportICS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportICSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ImportICS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ImportICS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ImportICS(ctx, req.(*ImportICSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_FindFreeSlots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindFreeSlotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).FindFreeSlots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_FindFreeSlots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).FindFreeSlots(ctx, req.(*FindFreeSlotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_FreeBusy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreeBusyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).FreeBusy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_FreeBusy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).FreeBusy(ctx, req.(*FreeBusyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_BatchCreateEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).BatchCreateEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_BatchCreateEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).BatchCreateEvents(ctx, req.(*BatchCreateEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_BatchDeleteEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).BatchDeleteEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_BatchDeleteEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).BatchDeleteEvents(ctx, req.(*BatchDeleteEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_GetEventHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).GetEventHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_GetEventHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).GetEventHistory(ctx, req.(*GetEventHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListDeletedEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListDeletedEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListDeletedEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListDeletedEvents(ctx, req.(*ListDeletedEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_RestoreEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).RestoreEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_RestoreEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).RestoreEvent(ctx, req.(*RestoreEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalendarServiceServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, EventChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalendarService_WatchEventsServer = grpc.ServerStreamingServer[EventChange]
+
 // CalendarService_ServiceDesc is the grpc.ServiceDesc for CalendarService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -425,7 +1108,85 @@ var CalendarService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateEvent",
 			Handler:    _CalendarService_UpdateEvent_Handler,
 		},
+		{
+			MethodName: "InviteAttendee",
+			Handler:    _CalendarService_InviteAttendee_Handler,
+		},
+		{
+			MethodName: "RespondInvitation",
+			Handler:    _CalendarService_RespondInvitation_Handler,
+		},
+		{
+			MethodName: "RemoveAttendee",
+			Handler:    _CalendarService_RemoveAttendee_Handler,
+		},
+		{
+			MethodName: "SearchEvents",
+			Handler:    _CalendarService_SearchEvents_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _CalendarService_CreateCategory_Handler,
+		},
+		{
+			MethodName: "ListCategories",
+			Handler:    _CalendarService_ListCategories_Handler,
+		},
+		{
+			MethodName: "UpdateCategory",
+			Handler:    _CalendarService_UpdateCategory_Handler,
+		},
+		{
+			MethodName: "DeleteCategory",
+			Handler:    _CalendarService_DeleteCategory_Handler,
+		},
+		{
+			MethodName: "SetEventTags",
+			Handler:    _CalendarService_SetEventTags_Handler,
+		},
+		{
+			MethodName: "ExportICS",
+			Handler:    _CalendarService_ExportICS_Handler,
+		},
+		{
+			MethodName: "ImportICS",
+			Handler:    _CalendarService_ImportICS_Handler,
+		},
+		{
+			MethodName: "FindFreeSlots",
+			Handler:    _CalendarService_FindFreeSlots_Handler,
+		},
+		{
+			MethodName: "FreeBusy",
+			Handler:    _CalendarService_FreeBusy_Handler,
+		},
+		{
+			MethodName: "BatchCreateEvents",
+			Handler:    _CalendarService_BatchCreateEvents_Handler,
+		},
+		{
+			MethodName: "BatchDeleteEvents",
+			Handler:    _CalendarService_BatchDeleteEvents_Handler,
+		},
+		{
+			MethodName: "GetEventHistory",
+			Handler:    _CalendarService_GetEventHistory_Handler,
+		},
+		{
+			MethodName: "ListDeletedEvents",
+			Handler:    _CalendarService_ListDeletedEvents_Handler,
+		},
+		{
+			MethodName: "RestoreEvent",
+			Handler:    _CalendarService_RestoreEvent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _CalendarService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "EventService.proto",
 }
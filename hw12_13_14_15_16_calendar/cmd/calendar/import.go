@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	calendarGRPC "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/server/grpc"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// maxLineBytes bounds a single JSON line of the import file.
+const maxLineBytes = 1 << 20
+
+// runImport implements "calendar import --file events.jsonl": every non-empty line is an
+// event in the JSON form of the API. The file is validated as a whole first and then
+// stored in batches; each batch is atomic, so a failure leaves the earlier batches stored.
+func runImport(ctx context.Context, application *app.App, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := fs.String("file", "", "JSON Lines file with one event per line (- for stdin)")
+	batchSize := fs.Int("batch", 1000, "events per transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("import: --file is required")
+	}
+	if *batchSize <= 0 || *batchSize > app.MaxBatchSize {
+		return fmt.Errorf("import: --batch must be between 1 and %d", app.MaxBatchSize)
+	}
+
+	in := os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return fmt.Errorf("import: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	events, lines, err := readEvents(in)
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	imported := 0
+	for start := 0; start < len(events); start += *batchSize {
+		end := min(start+*batchSize, len(events))
+		results, err := application.BatchCreateEvents(ctx, events[start:end])
+		if err != nil {
+			return fmt.Errorf("import: %d of %d events stored: %w", imported, len(events), err)
+		}
+		if !app.BatchApplied(results) {
+			for i, r := range results {
+				if r.Err != nil && !errors.Is(r.Err, app.ErrBatchAborted) {
+					fmt.Fprintf(os.Stderr, "line %d: %v\n", lines[start+i], r.Err)
+				}
+			}
+			return fmt.Errorf("import: %d of %d events stored, batch from line %d rejected",
+				imported, len(events), lines[start])
+		}
+		imported += len(results)
+	}
+	fmt.Printf("imported %d events\n", imported)
+	return nil
+}
+
+// readEvents decodes the events of a JSON Lines document with the line number of each.
+// All malformed lines are reported in the error.
+func readEvents(r io.Reader) ([]storage.Event, []int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+
+	var (
+		events []storage.Event
+		lines  []int
+		errs   []error
+	)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var pe calendarpb.Event
+		if err := protojson.Unmarshal([]byte(line), &pe); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", n, err))
+			continue
+		}
+		event, err := calendarGRPC.FromProtoEvent(&pe)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", n, err))
+			continue
+		}
+		events = append(events, event)
+		lines = append(lines, n)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return events, lines, errors.Join(errs...)
+}
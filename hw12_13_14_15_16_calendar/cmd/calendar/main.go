@@ -10,18 +10,29 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // event and request time zones must resolve in minimal images
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/metrics"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/server/caldav"
 	calendarGRPC "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/server/grpc"
+	internalhttp "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/server/http"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 )
 
-var configFile string
+var (
+	configFile string
+	loader     = config.NewLoader[config.Config](flag.CommandLine)
+)
 
 func init() {
 	flag.StringVar(&configFile, "config", "/etc/calendar/config.yaml", "Path to config file")
@@ -34,7 +45,7 @@ func main() {
 		return
 	}
 
-	cfg, err := LoadConfig(configFile)
+	cfg, err := loader.Load(configFile)
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
@@ -44,12 +55,56 @@ func main() {
 		logg.Error("application is not initialized")
 		return
 	}
+	defer appInstance.Close()
+	if flag.Arg(0) == "import" {
+		if err := runImport(context.Background(), appInstance, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	grpcAddr := cfg.GRPC.ListenGrpc
 	httpAddr := cfg.HTTP.Listen
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing, "calendar")
+	if err != nil {
+		logg.Error("failed to init tracing: " + err.Error())
+		return
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logg.Error("failed to flush traces: " + err.Error())
+		}
+	}()
+
+	grpcOpts, err := grpcCredentials(cfg.GRPC)
+	if err != nil {
+		logg.Error("failed to configure gRPC TLS: " + err.Error())
+		return
+	}
+	gatewayCreds, err := gatewayCredentials(cfg.HTTP)
+	if err != nil {
+		logg.Error("failed to configure gateway TLS: " + err.Error())
+		return
+	}
+	httpTLS, err := httpTLSConfig(cfg.HTTP)
+	if err != nil {
+		logg.Error("failed to configure HTTP TLS: " + err.Error())
+		return
+	}
+
+	limits := calendarGRPC.NewLimits(cfg.GRPC)
+
+	var authn *auth.Authenticator
+	if cfg.Auth.Enabled {
+		if authn, err = auth.New(cfg.Auth); err != nil {
+			logg.Error("failed to configure authentication: " + err.Error())
+			return
+		}
+	}
+
 	go func() { // Start gRPC server
 		lis, err := net.Listen("tcp", grpcAddr)
 		if err != nil {
@@ -58,7 +113,7 @@ func main() {
 			return
 		}
 
-		grpcServer := calendarGRPC.NewGRPCServer(appInstance, logg)
+		grpcServer := calendarGRPC.NewGRPCServer(appInstance, logg, cfg.GRPC, limits, authn, grpcOpts...)
 		reflection.Register(grpcServer)
 
 		logg.Info("gRPC server listening on " + grpcAddr)
@@ -68,8 +123,15 @@ func main() {
 	}()
 
 	go func() { // Start HTTP gateway server
-		mux := runtime.NewServeMux()
-		opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		mux := runtime.NewServeMux(
+			runtime.WithIncomingHeaderMatcher(internalhttp.IncomingHeaderMatcher),
+			runtime.WithOutgoingHeaderMatcher(internalhttp.OutgoingHeaderMatcher),
+			runtime.WithMarshalerOption(internalhttp.CalendarMIME, internalhttp.NewCalendarMarshaler()),
+		)
+		opts := []grpc.DialOption{
+			grpc.WithTransportCredentials(gatewayCreds),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		}
 
 		if err := calendarpb.RegisterCalendarServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
 			logg.Error("failed to start HTTP gateway: " + err.Error())
@@ -77,26 +139,70 @@ func main() {
 			return
 		}
 
+		root := http.NewServeMux()
+		root.Handle(metrics.Path, metrics.Handler())
+		root.Handle(caldav.Prefix, internalhttp.AccessLogMiddleware(logg, otelhttp.NewHandler(
+			caldav.NewHandler(appInstance, logg, authn), "caldav",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return "CalDAV " + r.Method
+			}),
+		)))
+		root.Handle(caldav.WellKnownPath, http.RedirectHandler(caldav.Prefix, http.StatusMovedPermanently))
+		root.Handle(internalhttp.WatchPath, internalhttp.AccessLogMiddleware(logg, otelhttp.NewHandler(
+			internalhttp.NewWatchHandler(appInstance, logg, authn), "watch",
+		)))
+		root.Handle("/", internalhttp.AccessLogMiddleware(logg, otelhttp.NewHandler(mux, "gateway",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + r.URL.Path
+			}),
+		)))
+
 		logg.Info("HTTP gateway listening on " + httpAddr)
 		srv := &http.Server{
 			Addr:         httpAddr,
-			Handler:      mux,
+			Handler:      root,
 			ReadTimeout:  10 * time.Second,
 			WriteTimeout: 10 * time.Second,
+			TLSConfig:    httpTLS,
 		}
-		if err := srv.ListenAndServe(); err != nil {
-			logg.Error("failed to serve HTTP: " + err.Error())
+		var serveErr error
+		if httpTLS != nil {
+			serveErr = srv.ListenAndServeTLS("", "")
+		} else {
+			serveErr = srv.ListenAndServe()
+		}
+		if serveErr != nil {
+			logg.Error("failed to serve HTTP: " + serveErr.Error())
 		}
 	}()
 
-	waitForShutdown(logg, cancel) // Gracefully handle termination signals.
+	// Gracefully handle termination signals; SIGHUP reloads the log level and gRPC limits.
+	waitForShutdown(logg, cancel, func() { reloadConfig(logg, limits) })
+}
+
+// reloadConfig re-reads the configuration and applies the settings that can change
+// at runtime. An invalid configuration is reported and the current settings are kept.
+func reloadConfig(logg *logger.Logger, limits *calendarGRPC.Limits) {
+	cfg, err := loader.Load(configFile)
+	if err != nil {
+		logg.Error("config reload failed, keeping current settings: " + err.Error())
+		return
+	}
+	logg.SetLevel(cfg.Logger.Level)
+	limits.Update(cfg.GRPC)
+	logg.Info("config reloaded")
 }
 
-func waitForShutdown(logg *logger.Logger, cancel context.CancelFunc) {
+func waitForShutdown(logg *logger.Logger, cancel context.CancelFunc, reload func()) {
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
-	<-sigCh
+	for sig := range sigCh {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		reload()
+	}
 	logg.Info("Shutdown signal received, exiting")
 	cancel()
 	time.Sleep(time.Second) // Wait briefly for cleanup if needed
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"crypto/tls"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// grpcCredentials returns the server option enabling TLS on the gRPC listener, if configured.
+func grpcCredentials(cfg config.GRPCConfig) ([]grpc.ServerOption, error) {
+	if !tlsconfig.Enabled(cfg.TLS) {
+		return nil, nil
+	}
+	tlsCfg, err := tlsconfig.ServerConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}, nil
+}
+
+// gatewayCredentials returns the transport credentials the HTTP gateway dials gRPC with.
+func gatewayCredentials(cfg config.HTTPConf) (credentials.TransportCredentials, error) {
+	if !tlsconfig.Enabled(cfg.GatewayTLS) {
+		return insecure.NewCredentials(), nil
+	}
+	tlsCfg, err := tlsconfig.ClientConfig(cfg.GatewayTLS)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// httpTLSConfig returns the TLS config of the HTTP listener, or nil for plaintext.
+func httpTLSConfig(cfg config.HTTPConf) (*tls.Config, error) {
+	if !tlsconfig.Enabled(cfg.TLS) {
+		return nil, nil
+	}
+	return tlsconfig.ServerConfig(cfg.TLS)
+}
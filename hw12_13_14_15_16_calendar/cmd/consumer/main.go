@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -8,23 +9,35 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/metrics"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/rabbit"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tracing"
 )
 
-var configFile string
+var (
+	configFile string
+	loader     = config.NewLoader[ConsumerConfig](flag.CommandLine)
+)
 
 func init() {
-	flag.StringVar(&configFile, "config", "/etc/calendar/config.toml", "Path to configuration file")
+	flag.StringVar(&configFile, "config", "/etc/calendar/consumer_config.yaml", "Path to configuration file")
 }
 
 func main() {
 	flag.Parse()
 
-	cfg, err := LoadConfig(configFile)
+	cfg, err := loader.Load(configFile)
 	if err != nil {
 		log.Fatalf("%s", err)
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, "consumer")
+	if err != nil {
+		log.Fatalf("failed to init tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background()) //nolint:errcheck
+
 	// Build AMQP URI from config
 	amqpURI := fmt.Sprintf("amqp://%s:%s@%s:%s/",
 		cfg.User,
@@ -33,6 +46,15 @@ func main() {
 		cfg.Port,
 	)
 
+	if cfg.MetricsListen != "" {
+		go func() {
+			log.Printf("metrics listening on %s", cfg.MetricsListen)
+			if err := metrics.ListenAndServe(cfg.MetricsListen); err != nil {
+				log.Printf("failed to serve metrics: %v", err)
+			}
+		}()
+	}
+
 	consumer, err := rabbit.NewConsumer(amqpURI, cfg.Queue, cfg.ConsumerTag)
 	if err != nil {
 		log.Fatalf("failed to create consumer: %v", err)
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -8,27 +9,39 @@ import (
 	"syscall"
 
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/metrics"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/rabbit"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tracing"
 )
 
-var configFile string
+var (
+	configFile string
+	loader     = config.NewLoader[ProducerConfig](flag.CommandLine)
+)
 
 func init() {
-	flag.StringVar(&configFile, "config", "/etc/calendar/config.toml", "Path to configuration file")
+	flag.StringVar(&configFile, "config", "/etc/calendar/producer_config.yaml", "Path to configuration file")
 }
 
 func main() {
 	flag.Parse()
-	logg := logger.New("producer")
-
-	cfg, err := LoadConfig(configFile)
+	cfg, err := loader.Load(configFile)
 	if err != nil {
-		logg.Error("failed to load config: " + err.Error())
+		fmt.Fprintln(os.Stderr, "failed to load config: "+err.Error())
 		os.Exit(1)
 	}
+	logg := logger.New(cfg.Logger.Level)
 	logg.Info(fmt.Sprintf("✅ Config loaded: %+v", cfg))
 
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, "producer")
+	if err != nil {
+		logg.Error("failed to init tracing: " + err.Error())
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background()) //nolint:errcheck
+
 	appInstance := app.NewWithConfig(cfg.Config, logg)
 	if appInstance == nil {
 		logg.Error("application is not initialized")
@@ -43,6 +56,15 @@ func main() {
 		cfg.Rabbit.Port,
 	)
 
+	if cfg.Metrics.Listen != "" {
+		go func() {
+			logg.Info("metrics listening on " + cfg.Metrics.Listen)
+			if err := metrics.ListenAndServe(cfg.Metrics.Listen); err != nil {
+				logg.Error("failed to serve metrics: " + err.Error())
+			}
+		}()
+	}
+
 	producer, err := rabbit.NewProducer(appInstance, amqpURI, "", cfg.Rabbit.Queue)
 	if err != nil {
 		logg.Error("failed to load config: " + err.Error())
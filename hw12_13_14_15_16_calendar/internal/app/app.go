@@ -2,20 +2,33 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	filestorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/file"
 	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
 	postgresstorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/sql"
 )
 
+var (
+	ErrInvalidAttendee = errors.New("invalid attendee")
+	ErrNotInvitee      = errors.New("only the invited user can respond to an invitation")
+)
+
 // App is the main application structure.
 type App struct {
-	log   *logger.Logger
-	store storageInterface
+	log        *logger.Logger
+	store      storageInterface
+	changes    changeBus
+	changeFeed bool               // the backend announces changes itself
+	stop       context.CancelFunc // stops the change listener
+	closeStore func() error       // releases the storage backend, if it holds resources
 }
 
 // NewWithConfig creates and returns a new App instance based on the config.
@@ -26,30 +39,95 @@ func NewWithConfig(cfg config.Config, log *logger.Logger) *App {
 	case "memory":
 		store = memorystorage.New()
 	case "postgres":
-		store = postgresstorage.New(cfg.Storage.Postgres)
+		if cfg.Storage.Postgres.Driver == "stdlib" {
+			store = postgresstorage.New(cfg.Storage.Postgres)
+			break
+		}
+		poolStore, err := postgresstorage.NewPool(cfg.Storage.Postgres)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to open postgres pool: %v", err))
+			os.Exit(1)
+		}
+		store = poolStore
+	case "file":
+		fileStore, err := filestorage.Open(cfg.Storage.File.Dir, cfg.Storage.File.CompactEvery)
+		if err != nil {
+			log.Error(fmt.Sprintf("failed to open file storage in %s: %v", cfg.Storage.File.Dir, err))
+			os.Exit(1)
+		}
+		store = fileStore
 	default:
 		log.Error(fmt.Sprintf("unknown storage type: %s", cfg.Storage.Type))
 		os.Exit(1)
 	}
 
-	return &App{
+	a := &App{
 		log:   log,
-		store: store,
+		store: cacheStore(cacheConfig(cfg.Storage), instrumentStore(cfg.Storage.Type, traceStore(cfg.Storage.Type, store))),
+	}
+	if closer, ok := store.(io.Closer); ok {
+		a.closeStore = closer.Close
+	}
+	if source, ok := store.(changeSource); ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		a.changeFeed, a.stop = true, cancel
+		go a.followChanges(ctx, source)
 	}
+	return a
 }
 
-// storageInterface defines the expected behavior for all storage backends.
-type storageInterface interface {
-	CreateEvent(ctx context.Context, event storage.Event) error
-	GetEvent(ctx context.Context, id int) (storage.Event, error)
-	ListEvents(ctx context.Context, period storage.Period) ([]storage.Event, error)
-	UpdateEvent(ctx context.Context, event storage.Event) error
-	DeleteEvent(ctx context.Context, id int) error
+// Close stops the background work of the App.
+func (a *App) Close() {
+	if a.stop != nil {
+		a.stop()
+	}
+	if a.closeStore != nil {
+		if err := a.closeStore(); err != nil {
+			a.log.Error(fmt.Sprintf("failed to close storage: %v", err))
+		}
+	}
 }
 
+// storageInterface defines the expected behavior for all storage backends.
+type storageInterface = storage.Store
+
 // CreateEvent adds a new event using the configured storage.
-func (a *App) CreateEvent(ctx context.Context, event storage.Event) error {
-	return a.store.CreateEvent(ctx, event)
+// An invalid event is refused with ErrInvalidEvent, which lists every violation.
+// An event without an owner is assigned to the authenticated caller, if known.
+// All-day events are aligned to midnights of the event's time zone.
+func (a *App) CreateEvent(ctx context.Context, event storage.Event) (int, error) {
+	if event.UserID == nil {
+		event.UserID = callerUserID(ctx)
+	}
+	if err := validateEvent(event); err != nil {
+		return 0, err
+	}
+	if err := event.Normalize(); err != nil {
+		return 0, err
+	}
+	err := withinTx(ctx, a.store, func(tx storageInterface) error {
+		id, err := tx.CreateEvent(ctx, event)
+		if err != nil {
+			return err
+		}
+		event.ID = id
+		return a.record(ctx, tx, storage.ActionCreated, id, nil, &event)
+	})
+	if err != nil {
+		return 0, err
+	}
+	a.announce(ctx, storage.ActionCreated, event.ID)
+	return event.ID, nil
+}
+
+// callerUserID returns the calendar user of the authenticated caller, or nil.
+func callerUserID(ctx context.Context) *int {
+	identity, ok := auth.FromContext(ctx)
+	if !ok || identity.UserID == nil {
+		return nil
+	}
+	uid := *identity.UserID
+	return &uid
 }
 
 // GetEvent retrieves a single event from the configured storage.
@@ -58,16 +136,104 @@ func (a *App) GetEvent(ctx context.Context, id int) (storage.Event, error) {
 }
 
 // ListEvents retrieves events from the configured storage.
-func (a *App) ListEvents(ctx context.Context, period storage.Period) ([]storage.Event, error) {
-	return a.store.ListEvents(ctx, period)
+func (a *App) ListEvents(ctx context.Context, filter storage.Filter) ([]storage.Event, error) {
+	return a.store.ListEvents(ctx, filter)
 }
 
-// DeleteEvent removes an event from the configured storage.
+// DeleteEvent moves an event to the trash of the configured storage, from which
+// RestoreEvent takes it back until it is purged.
 func (a *App) DeleteEvent(ctx context.Context, id int) error {
-	return a.store.DeleteEvent(ctx, id)
+	err := withinTx(ctx, a.store, func(tx storageInterface) error {
+		before, err := tx.GetEvent(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.DeleteEvent(ctx, id); err != nil {
+			return err
+		}
+		return a.record(ctx, tx, storage.ActionDeleted, id, &before, nil)
+	})
+	if err != nil {
+		return err
+	}
+	a.announce(ctx, storage.ActionDeleted, id)
+	return nil
 }
 
-// UpdateEvent updates an event from the configured storage.
+// UpdateEvent updates an event from the configured storage, validated like in CreateEvent.
 func (a *App) UpdateEvent(ctx context.Context, event storage.Event) error {
-	return a.store.UpdateEvent(ctx, event)
+	if err := validateEvent(event); err != nil {
+		return err
+	}
+	if err := event.Normalize(); err != nil {
+		return err
+	}
+	err := withinTx(ctx, a.store, func(tx storageInterface) error {
+		before, err := tx.GetEvent(ctx, event.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.UpdateEvent(ctx, event); err != nil {
+			return err
+		}
+		after := event
+		after.Attendees, after.Tags = before.Attendees, before.Tags
+		return a.record(ctx, tx, storage.ActionUpdated, event.ID, &before, &after)
+	})
+	if err != nil {
+		return err
+	}
+	a.announce(ctx, storage.ActionUpdated, event.ID)
+	return nil
+}
+
+// InviteAttendee adds a user to an event. The role defaults to required and the
+// invitation starts out pending.
+func (a *App) InviteAttendee(ctx context.Context, attendee storage.Attendee) error {
+	if attendee.Role == "" {
+		attendee.Role = storage.RoleRequired
+	}
+	if attendee.UserID <= 0 || !attendee.Role.Valid() {
+		return fmt.Errorf("%w: user %d, role %q", ErrInvalidAttendee, attendee.UserID, attendee.Role)
+	}
+	attendee.Status = storage.RSVPPending
+	return a.writeDetails(ctx, attendee.EventID, func(tx storageInterface) error {
+		return tx.AddAttendee(ctx, attendee)
+	})
+}
+
+// RespondInvitation records an attendee's RSVP. A userID of 0 means the authenticated
+// caller; an authenticated caller may only answer for themselves.
+func (a *App) RespondInvitation(ctx context.Context, eventID, userID int, status storage.RSVPStatus) error {
+	caller := callerUserID(ctx)
+	if userID == 0 && caller != nil {
+		userID = *caller
+	}
+	if caller != nil && *caller != userID {
+		return ErrNotInvitee
+	}
+	if !status.Valid() || status == storage.RSVPPending {
+		return fmt.Errorf("%w: status %q", ErrInvalidAttendee, status)
+	}
+
+	return a.writeDetails(ctx, eventID, func(tx storageInterface) error {
+		attendees, err := tx.ListAttendees(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		for _, attendee := range attendees {
+			if attendee.UserID == userID {
+				attendee.Status = status
+				return tx.UpdateAttendee(ctx, attendee)
+			}
+		}
+		return storage.ErrAttendeeNotFound
+	})
+}
+
+// RemoveAttendee withdraws a user's invitation to an event.
+func (a *App) RemoveAttendee(ctx context.Context, eventID, userID int) error {
+	return a.writeDetails(ctx, eventID, func(tx storageInterface) error {
+		return tx.RemoveAttendee(ctx, eventID, userID)
+	})
 }
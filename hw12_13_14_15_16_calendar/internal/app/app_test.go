@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
 )
@@ -17,27 +19,30 @@ var (
 
 // fakeStorage is a complete mock of storageInterface for testing.
 type fakeStorage struct {
-	events map[int]storage.Event
+	events    map[int]storage.Event
+	attendees map[int]map[int]storage.Attendee
+	history   []storage.HistoryEntry
 }
 
 func newFakeStorage() *fakeStorage {
 	return &fakeStorage{
-		events: make(map[int]storage.Event),
+		events:    make(map[int]storage.Event),
+		attendees: make(map[int]map[int]storage.Attendee),
 	}
 }
 
-func (f *fakeStorage) CreateEvent(ctx context.Context, event storage.Event) error {
+func (f *fakeStorage) CreateEvent(ctx context.Context, event storage.Event) (int, error) {
 	select {
 	case <-ctx.Done():
-		return ErrContextCancel
+		return 0, ErrContextCancel
 	default:
 	}
 	// Add this duplicate check ↓
 	if _, exists := f.events[event.ID]; exists {
-		return ErrDuplicate
+		return 0, ErrDuplicate
 	}
 	f.events[event.ID] = event
-	return nil
+	return event.ID, nil
 }
 
 func (f *fakeStorage) GetEvent(ctx context.Context, id int) (storage.Event, error) {
@@ -54,8 +59,8 @@ func (f *fakeStorage) GetEvent(ctx context.Context, id int) (storage.Event, erro
 	return event, nil
 }
 
-func (f *fakeStorage) ListEvents(ctx context.Context, period storage.Period) ([]storage.Event, error) {
-	_ = period
+func (f *fakeStorage) ListEvents(ctx context.Context, filter storage.Filter) ([]storage.Event, error) {
+	_ = filter
 	select {
 	case <-ctx.Done():
 		return nil, ErrContextCancel
@@ -102,6 +107,147 @@ func (f *fakeStorage) UpdateEvent(ctx context.Context, event storage.Event) erro
 	return nil
 }
 
+func (f *fakeStorage) AddAttendee(_ context.Context, attendee storage.Attendee) error {
+	if _, ok := f.events[attendee.EventID]; !ok {
+		return ErrNotFound
+	}
+	if f.attendees[attendee.EventID] == nil {
+		f.attendees[attendee.EventID] = make(map[int]storage.Attendee)
+	}
+	if _, ok := f.attendees[attendee.EventID][attendee.UserID]; ok {
+		return storage.ErrAttendeeExists
+	}
+	f.attendees[attendee.EventID][attendee.UserID] = attendee
+	return nil
+}
+
+func (f *fakeStorage) ListAttendees(_ context.Context, eventID int) ([]storage.Attendee, error) {
+	if _, ok := f.events[eventID]; !ok {
+		return nil, ErrNotFound
+	}
+	list := make([]storage.Attendee, 0, len(f.attendees[eventID]))
+	for _, a := range f.attendees[eventID] {
+		list = append(list, a)
+	}
+	return list, nil
+}
+
+func (f *fakeStorage) UpdateAttendee(_ context.Context, attendee storage.Attendee) error {
+	if _, ok := f.attendees[attendee.EventID][attendee.UserID]; !ok {
+		return storage.ErrAttendeeNotFound
+	}
+	f.attendees[attendee.EventID][attendee.UserID] = attendee
+	return nil
+}
+
+func (f *fakeStorage) RemoveAttendee(_ context.Context, eventID, userID int) error {
+	if _, ok := f.attendees[eventID][userID]; !ok {
+		return storage.ErrAttendeeNotFound
+	}
+	delete(f.attendees[eventID], userID)
+	return nil
+}
+
+func (f *fakeStorage) CreateEvents(ctx context.Context, events []storage.Event) ([]int, error) {
+	if ctx.Err() != nil {
+		return nil, ErrContextCancel
+	}
+	ids := make([]int, len(events))
+	for i, event := range events {
+		if _, exists := f.events[event.ID]; exists {
+			return nil, &storage.BatchError{Items: map[int]error{i: ErrDuplicate}}
+		}
+	}
+	for i, event := range events {
+		f.events[event.ID] = event
+		ids[i] = event.ID
+	}
+	return ids, nil
+}
+
+func (f *fakeStorage) AddHistory(_ context.Context, entry storage.HistoryEntry) error {
+	f.history = append(f.history, entry)
+	return nil
+}
+
+func (f *fakeStorage) ListHistory(_ context.Context, eventID int) ([]storage.HistoryEntry, error) {
+	var entries []storage.HistoryEntry
+	for _, entry := range f.history {
+		if entry.EventID == eventID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (f *fakeStorage) RecreateEvent(_ context.Context, event storage.Event) error {
+	if _, exists := f.events[event.ID]; exists {
+		return storage.ErrEventExists
+	}
+	f.events[event.ID] = event
+	return nil
+}
+
+// The fake deletes events for good, so its trash is always empty.
+func (f *fakeStorage) ListDeletedEvents(context.Context) ([]storage.Event, error) {
+	return nil, nil
+}
+
+func (f *fakeStorage) UndeleteEvent(context.Context, int) error {
+	return storage.ErrEventNotFound
+}
+
+func (f *fakeStorage) PurgeDeletedEvents(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+// The fake has no categories; tagging an event with any name fails.
+func (f *fakeStorage) CreateCategory(context.Context, storage.Category) (int, error) {
+	return 0, ErrDuplicate
+}
+
+func (f *fakeStorage) ListCategories(context.Context) ([]storage.Category, error) {
+	return nil, nil
+}
+
+func (f *fakeStorage) UpdateCategory(context.Context, storage.Category) error {
+	return storage.ErrCategoryNotFound
+}
+
+func (f *fakeStorage) DeleteCategory(context.Context, int) error {
+	return storage.ErrCategoryNotFound
+}
+
+func (f *fakeStorage) SetEventTags(_ context.Context, eventID int, tags []string) error {
+	if _, exists := f.events[eventID]; !exists {
+		return ErrNotFound
+	}
+	if len(tags) > 0 {
+		return storage.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (f *fakeStorage) SearchEvents(context.Context, storage.SearchQuery) ([]storage.SearchResult, error) {
+	return nil, nil
+}
+
+func (f *fakeStorage) DeleteEvents(_ context.Context, ids []int) error {
+	failed := make(map[int]error)
+	for i, id := range ids {
+		if _, exists := f.events[id]; !exists {
+			failed[i] = ErrNotFound
+		}
+	}
+	if len(failed) > 0 {
+		return &storage.BatchError{Items: failed}
+	}
+	for _, id := range ids {
+		delete(f.events, id)
+	}
+	return nil
+}
+
 func TestApp_CreateEvent(t *testing.T) {
 	t.Parallel()
 
@@ -126,12 +272,12 @@ func TestApp_CreateEvent(t *testing.T) {
 
 			if tc.wantErr {
 				// First create the event to force duplicate
-				_ = fakeStore.CreateEvent(ctx, storage.Event{ID: tc.id, Title: "Existing"})
+				_, _ = fakeStore.CreateEvent(ctx, storage.Event{ID: tc.id, Title: "Existing"})
 			}
 
 			// Exercise
 			event := storage.Event{ID: tc.id, Title: tc.title}
-			err := app.CreateEvent(ctx, event)
+			_, err := app.CreateEvent(ctx, event)
 
 			// Verify
 			if tc.wantErr {
@@ -179,7 +325,7 @@ func TestApp_DeleteEvent(t *testing.T) {
 			ctx := context.Background()
 
 			if tc.preCreate {
-				_ = fakeStore.CreateEvent(ctx, storage.Event{ID: tc.id, Title: "Test Event"})
+				_, _ = fakeStore.CreateEvent(ctx, storage.Event{ID: tc.id, Title: "Test Event"})
 			}
 
 			// Exercise
@@ -205,3 +351,56 @@ func TestApp_DeleteEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestApp_CreateEventDefaultsOwnerToCaller(t *testing.T) {
+	fakeStore := newFakeStorage()
+	app := &App{log: logger.New(""), store: fakeStore}
+
+	uid := 17
+	ctx := auth.NewContext(context.Background(), auth.Identity{Subject: "17", UserID: &uid, Method: "jwt"})
+	if _, err := app.CreateEvent(ctx, storage.Event{ID: 1, Title: "Own event"}); err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+
+	stored := fakeStore.events[1]
+	if stored.UserID == nil || *stored.UserID != uid {
+		t.Errorf("expected owner %d, got %v", uid, stored.UserID)
+	}
+}
+
+func TestApp_InviteAndRespond(t *testing.T) {
+	fakeStore := newFakeStorage()
+	fakeStore.events[1] = storage.Event{ID: 1, Title: "Consultation"}
+	app := &App{log: logger.New(""), store: fakeStore}
+	ctx := context.Background()
+
+	if err := app.InviteAttendee(ctx, storage.Attendee{EventID: 1, UserID: 5}); err != nil {
+		t.Fatalf("InviteAttendee returned error: %v", err)
+	}
+	invited := fakeStore.attendees[1][5]
+	if invited.Role != storage.RoleRequired || invited.Status != storage.RSVPPending {
+		t.Errorf("expected pending required attendee, got %+v", invited)
+	}
+	if err := app.InviteAttendee(ctx, storage.Attendee{EventID: 1, UserID: 5}); !errors.Is(err, storage.ErrAttendeeExists) {
+		t.Errorf("expected ErrAttendeeExists on second invite, got: %v", err)
+	}
+	if err := app.InviteAttendee(ctx, storage.Attendee{EventID: 1, UserID: 6, Role: "guest"}); !errors.Is(err, ErrInvalidAttendee) {
+		t.Errorf("expected ErrInvalidAttendee for unknown role, got: %v", err)
+	}
+
+	uid := 5
+	asInvitee := auth.NewContext(ctx, auth.Identity{Subject: "5", UserID: &uid, Method: "jwt"})
+	if err := app.RespondInvitation(asInvitee, 1, 0, storage.RSVPAccepted); err != nil {
+		t.Fatalf("RespondInvitation returned error: %v", err)
+	}
+	if got := fakeStore.attendees[1][5].Status; got != storage.RSVPAccepted {
+		t.Errorf("expected accepted, got %q", got)
+	}
+
+	if err := app.RespondInvitation(asInvitee, 1, 6, storage.RSVPDeclined); !errors.Is(err, ErrNotInvitee) {
+		t.Errorf("expected ErrNotInvitee when answering for someone else, got: %v", err)
+	}
+	if err := app.RespondInvitation(ctx, 1, 6, storage.RSVPDeclined); !errors.Is(err, storage.ErrAttendeeNotFound) {
+		t.Errorf("expected ErrAttendeeNotFound for a user who was not invited, got: %v", err)
+	}
+}
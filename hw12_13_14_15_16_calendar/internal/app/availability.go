@@ -0,0 +1,214 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+var ErrInvalidAvailability = errors.New("invalid availability query")
+
+const (
+	// untimedEventDuration is how long an event without an end keeps its participants busy.
+	untimedEventDuration = time.Hour
+	// maxAvailabilityRange bounds the range of a single query.
+	maxAvailabilityRange = 93 * 24 * time.Hour
+)
+
+// Interval is the half-open time range [Start, End).
+type Interval struct {
+	Start, End time.Time
+}
+
+// AvailabilityQuery selects the events that make time busy: the events owned or attended
+// by any of the users and the events at the clinic, within [From, To).
+type AvailabilityQuery struct {
+	UserIDs  []int
+	Clinic   string
+	From, To time.Time
+}
+
+// WorkingHours limits free slots to a daily window of local time in Location, given as
+// offsets from midnight. The zero value means around the clock.
+type WorkingHours struct {
+	Start, End time.Duration
+	Location   *time.Location
+}
+
+func (q AvailabilityQuery) validate() error {
+	switch {
+	case len(q.UserIDs) == 0 && q.Clinic == "":
+		return fmt.Errorf("%w: users or a clinic are required", ErrInvalidAvailability)
+	case q.From.IsZero() || q.To.IsZero() || !q.From.Before(q.To):
+		return fmt.Errorf("%w: a range with from before to is required", ErrInvalidAvailability)
+	case q.To.Sub(q.From) > maxAvailabilityRange:
+		return fmt.Errorf("%w: range is longer than %s", ErrInvalidAvailability, maxAvailabilityRange)
+	}
+	return nil
+}
+
+// FreeBusy returns the busy intervals of the query range, merged and sorted, without
+// anything about the events behind them.
+func (a *App) FreeBusy(ctx context.Context, q AvailabilityQuery) ([]Interval, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return a.busy(ctx, q)
+}
+
+// FindFreeSlots returns the free intervals of the query range, within working hours,
+// that are at least duration long.
+func (a *App) FindFreeSlots(
+	ctx context.Context,
+	q AvailabilityQuery,
+	duration time.Duration,
+	hours WorkingHours,
+) ([]Interval, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("%w: slot duration must be positive", ErrInvalidAvailability)
+	}
+	if hours.Start < 0 || hours.End > 24*time.Hour || hours.Start > hours.End {
+		return nil, fmt.Errorf("%w: working hours must be a window within a day", ErrInvalidAvailability)
+	}
+
+	busy, err := a.busy(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	var free []Interval
+	for _, window := range workingWindows(q.From, q.To, hours) {
+		for _, gap := range subtract(window, busy) {
+			if gap.End.Sub(gap.Start) >= duration {
+				free = append(free, gap)
+			}
+		}
+	}
+	return free, nil
+}
+
+// busy loads the events of the users and of the clinic (two queries, since either one
+// makes time busy) and merges their intervals.
+func (a *App) busy(ctx context.Context, q AvailabilityQuery) ([]Interval, error) {
+	// Start earlier so that events without an end that began before From are found.
+	base := storage.Filter{Period: storage.PeriodAll, From: q.From.Add(-untimedEventDuration), To: q.To}
+	var filters []storage.Filter
+	if len(q.UserIDs) > 0 {
+		f := base
+		f.UserIDs = q.UserIDs
+		filters = append(filters, f)
+	}
+	if q.Clinic != "" {
+		f := base
+		f.Clinic = q.Clinic
+		filters = append(filters, f)
+	}
+
+	seen := make(map[int]bool)
+	var intervals []Interval
+	for _, filter := range filters {
+		events, err := a.store.ListEvents(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			if seen[ev.ID] || ev.Start == nil {
+				continue
+			}
+			seen[ev.ID] = true
+
+			end := ev.Start.Add(untimedEventDuration)
+			if ev.End != nil && ev.End.After(*ev.Start) {
+				end = *ev.End
+			}
+			in := Interval{Start: maxTime(*ev.Start, q.From), End: minTime(end, q.To)}
+			if in.Start.Before(in.End) {
+				intervals = append(intervals, in)
+			}
+		}
+	}
+	return merge(intervals), nil
+}
+
+// merge sorts intervals and joins the overlapping and adjacent ones.
+func merge(intervals []Interval) []Interval {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	var merged []Interval
+	for _, in := range intervals {
+		if n := len(merged); n > 0 && !in.Start.After(merged[n-1].End) {
+			merged[n-1].End = maxTime(merged[n-1].End, in.End)
+			continue
+		}
+		merged = append(merged, in)
+	}
+	return merged
+}
+
+// subtract returns the parts of window not covered by the sorted, merged busy intervals.
+func subtract(window Interval, busy []Interval) []Interval {
+	var free []Interval
+	start := window.Start
+	for _, b := range busy {
+		if !b.End.After(start) {
+			continue
+		}
+		if !b.Start.Before(window.End) {
+			break
+		}
+		if b.Start.After(start) {
+			free = append(free, Interval{Start: start, End: b.Start})
+		}
+		start = b.End
+	}
+	if start.Before(window.End) {
+		free = append(free, Interval{Start: start, End: window.End})
+	}
+	return free
+}
+
+// workingWindows splits [from, to) into the daily working-hour windows. Offsets are wall
+// clock times, so a window keeps its local hours across DST changes.
+func workingWindows(from, to time.Time, hours WorkingHours) []Interval {
+	if hours.Start == 0 && hours.End == 0 {
+		return []Interval{{Start: from, End: to}}
+	}
+	loc := hours.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var windows []Interval
+	y, m, d := from.In(loc).Date()
+	for day := time.Date(y, m, d, 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		y, m, d := day.Date()
+		w := Interval{
+			Start: maxTime(time.Date(y, m, d, 0, int(hours.Start/time.Minute), 0, 0, loc), from),
+			End:   minTime(time.Date(y, m, d, 0, int(hours.End/time.Minute), 0, 0, loc), to),
+		}
+		if w.Start.Before(w.End) {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+func availabilityApp(t *testing.T) *App {
+	t.Helper()
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+
+	doctor, patient, other := 1, 2, 3
+	clinic := "Main street 1"
+	at := func(day, hour, minute int) *time.Time {
+		v := time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+		return &v
+	}
+	for _, ev := range []storage.Event{
+		{Title: "Surgery", Start: at(11, 9, 0), End: at(11, 11, 0), UserID: &doctor},
+		{Title: "Overlapping", Start: at(11, 10, 30), End: at(11, 12, 0), UserID: &patient},
+		{Title: "Room booked", Start: at(11, 14, 0), End: at(11, 15, 0), UserID: &other, Clinic: &clinic},
+		{Title: "No end", Start: at(11, 16, 0), UserID: &doctor},
+		{Title: "Someone else", Start: at(11, 12, 0), End: at(11, 13, 0), UserID: &other},
+		{Title: "Day off", Start: at(12, 0, 0), AllDay: true, UserID: &doctor},
+	} {
+		if _, err := a.CreateEvent(ctx, ev); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+	}
+	return a
+}
+
+func TestFreeBusy(t *testing.T) {
+	a := availabilityApp(t)
+	q := AvailabilityQuery{
+		UserIDs: []int{1, 2},
+		Clinic:  "Main street 1",
+		From:    time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC),
+		To:      time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC),
+	}
+
+	busy, err := a.FreeBusy(context.Background(), q)
+	if err != nil {
+		t.Fatalf("FreeBusy: %v", err)
+	}
+	want := []Interval{
+		{Start: q.From, End: time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2024, 3, 11, 14, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 11, 15, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2024, 3, 11, 16, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 11, 17, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC), End: q.To},
+	}
+	assertIntervals(t, busy, want)
+}
+
+func TestFindFreeSlots(t *testing.T) {
+	a := availabilityApp(t)
+	q := AvailabilityQuery{
+		UserIDs: []int{1},
+		Clinic:  "Main street 1",
+		From:    time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC),
+	}
+	hours := WorkingHours{Start: 8 * time.Hour, End: 17 * time.Hour}
+
+	free, err := a.FindFreeSlots(context.Background(), q, time.Hour, hours)
+	if err != nil {
+		t.Fatalf("FindFreeSlots: %v", err)
+	}
+	day := func(d, h int) time.Time { return time.Date(2024, 3, d, h, 0, 0, 0, time.UTC) }
+	assertIntervals(t, free, []Interval{
+		{Start: day(11, 8), End: day(11, 9)},
+		{Start: day(11, 11), End: day(11, 14)},
+		{Start: day(11, 15), End: day(11, 16)},
+		{Start: day(13, 8), End: day(13, 17)},
+	})
+
+	free, err = a.FindFreeSlots(context.Background(), q, 2*time.Hour, hours)
+	if err != nil {
+		t.Fatalf("FindFreeSlots: %v", err)
+	}
+	assertIntervals(t, free, []Interval{
+		{Start: day(11, 11), End: day(11, 14)},
+		{Start: day(13, 8), End: day(13, 17)},
+	})
+}
+
+func TestWorkingWindowsAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2024, 3, 30, 0, 0, 0, 0, berlin)
+	windows := workingWindows(from, from.AddDate(0, 0, 2), WorkingHours{
+		Start: 9 * time.Hour, End: 17 * time.Hour, Location: berlin,
+	})
+	if len(windows) != 2 {
+		t.Fatalf("got %d windows, want 2", len(windows))
+	}
+	for _, w := range windows {
+		if h := w.Start.In(berlin).Hour(); h != 9 {
+			t.Errorf("window starts at %d:00 local time, want 9:00", h)
+		}
+	}
+}
+
+func TestAvailabilityValidation(t *testing.T) {
+	a := availabilityApp(t)
+	from := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		q        AvailabilityQuery
+		duration time.Duration
+		hours    WorkingHours
+	}{
+		{"no users or clinic", AvailabilityQuery{From: from, To: from.Add(time.Hour)}, time.Hour, WorkingHours{}},
+		{"empty range", AvailabilityQuery{UserIDs: []int{1}, From: from, To: from}, time.Hour, WorkingHours{}},
+		{"range too long", AvailabilityQuery{UserIDs: []int{1}, From: from, To: from.AddDate(1, 0, 0)}, time.Hour, WorkingHours{}},
+		{"no duration", AvailabilityQuery{UserIDs: []int{1}, From: from, To: from.Add(time.Hour)}, 0, WorkingHours{}},
+		{"hours reversed", AvailabilityQuery{UserIDs: []int{1}, From: from, To: from.Add(time.Hour)}, time.Hour,
+			WorkingHours{Start: 17 * time.Hour, End: 9 * time.Hour}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.FindFreeSlots(context.Background(), tc.q, tc.duration, tc.hours)
+			if !errors.Is(err, ErrInvalidAvailability) {
+				t.Errorf("got %v, want ErrInvalidAvailability", err)
+			}
+		})
+	}
+}
+
+func assertIntervals(t *testing.T, got, want []Interval) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d intervals %v, want %d %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("interval %d: got [%s, %s), want [%s, %s)",
+				i, got[i].Start, got[i].End, want[i].Start, want[i].End)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+var (
+	ErrInvalidBatch = errors.New("invalid batch")
+	// ErrBatchAborted is the result of a valid item in a batch that failed because of
+	// other items.
+	ErrBatchAborted = errors.New("not applied: another item of the batch failed")
+)
+
+// MaxBatchSize bounds the number of items of a single batch.
+const MaxBatchSize = 10000
+
+// BatchResult is the outcome of one item of a batch: the event ID or the reason it failed.
+type BatchResult struct {
+	ID  int
+	Err error
+}
+
+// BatchCreateEvents creates all events or none of them. Every event is validated like in
+// CreateEvent; the results are in the order of events. The error is only set when the
+// batch could not be attempted at all.
+func (a *App) BatchCreateEvents(ctx context.Context, events []storage.Event) ([]BatchResult, error) {
+	if err := checkBatchSize(len(events)); err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(events))
+	failed := false
+	owner := callerUserID(ctx)
+	for i := range events {
+		if events[i].UserID == nil {
+			events[i].UserID = owner
+		}
+		err := validateEvent(events[i])
+		if err == nil {
+			err = events[i].Normalize()
+		}
+		if err != nil {
+			results[i].Err = err
+			failed = true
+		}
+	}
+	if failed {
+		return abortBatch(results), nil
+	}
+
+	var ids []int
+	err := withinTx(ctx, a.store, func(tx storageInterface) (err error) {
+		if ids, err = tx.CreateEvents(ctx, events); err != nil {
+			return err
+		}
+		for i, id := range ids {
+			event := events[i]
+			event.ID = id
+			if err := a.record(ctx, tx, storage.ActionCreated, id, nil, &event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return batchFailure(results, err)
+	}
+	for i, id := range ids {
+		results[i].ID = id
+		events[i].ID = id
+		a.announce(ctx, storage.ActionCreated, id)
+	}
+	return results, nil
+}
+
+// BatchDeleteEvents deletes all events or, if any of them is missing, none.
+func (a *App) BatchDeleteEvents(ctx context.Context, ids []int) ([]BatchResult, error) {
+	if err := checkBatchSize(len(ids)); err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(ids))
+	for i, id := range ids {
+		results[i].ID = id
+	}
+	err := withinTx(ctx, a.store, func(tx storageInterface) error {
+		// Snapshots for the history; missing events fail the batch below.
+		before := make([]*storage.Event, len(ids))
+		for i, id := range ids {
+			if event, err := tx.GetEvent(ctx, id); err == nil {
+				before[i] = &event
+			}
+		}
+		if err := tx.DeleteEvents(ctx, ids); err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if err := a.record(ctx, tx, storage.ActionDeleted, id, before[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return batchFailure(results, err)
+	}
+	for _, id := range ids {
+		a.announce(ctx, storage.ActionDeleted, id)
+	}
+	return results, nil
+}
+
+// BatchApplied reports whether a batch with these results was applied.
+func BatchApplied(results []BatchResult) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func checkBatchSize(n int) error {
+	if n > MaxBatchSize {
+		return fmt.Errorf("%w: %d items, at most %d are allowed", ErrInvalidBatch, n, MaxBatchSize)
+	}
+	return nil
+}
+
+// batchFailure turns the item errors of a storage batch error into results; any other
+// error fails the whole call.
+func batchFailure(results []BatchResult, err error) ([]BatchResult, error) {
+	var batchErr *storage.BatchError
+	if !errors.As(err, &batchErr) {
+		return nil, err
+	}
+	for i, itemErr := range batchErr.Items {
+		if i >= 0 && i < len(results) {
+			results[i].Err = itemErr
+		}
+	}
+	return abortBatch(results), nil
+}
+
+// abortBatch marks the items without an error of a failed batch as aborted.
+func abortBatch(results []BatchResult) []BatchResult {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Err = ErrBatchAborted
+		}
+	}
+	return results
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+func TestBatchCreateEvents(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+
+	results, err := a.BatchCreateEvents(ctx, []storage.Event{
+		{Title: "Valid"},
+		{Title: "Bad zone", TimeZone: "Mars/Olympus"},
+	})
+	if err != nil {
+		t.Fatalf("BatchCreateEvents: %v", err)
+	}
+	if BatchApplied(results) || !errors.Is(results[0].Err, ErrBatchAborted) || results[1].Err == nil {
+		t.Errorf("expected the batch to be aborted by item 1, got %+v", results)
+	}
+	if events, _ := a.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll}); len(events) != 0 {
+		t.Errorf("aborted batch stored %d events", len(events))
+	}
+
+	results, err = a.BatchCreateEvents(ctx, []storage.Event{{Title: "First"}, {Title: "Second", TimeZone: "Europe/Berlin"}})
+	if err != nil || !BatchApplied(results) {
+		t.Fatalf("BatchCreateEvents: %+v, %v", results, err)
+	}
+	if results[0].ID != 1 || results[1].ID != 2 {
+		t.Errorf("got ids %d and %d, want 1 and 2", results[0].ID, results[1].ID)
+	}
+
+	if _, err := a.BatchCreateEvents(ctx, make([]storage.Event, MaxBatchSize+1)); !errors.Is(err, ErrInvalidBatch) {
+		t.Errorf("expected ErrInvalidBatch, got %v", err)
+	}
+}
+
+func TestBatchDeleteEvents(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+	if _, err := a.BatchCreateEvents(ctx, []storage.Event{{Title: "First"}, {Title: "Second"}}); err != nil {
+		t.Fatalf("BatchCreateEvents: %v", err)
+	}
+
+	results, err := a.BatchDeleteEvents(ctx, []int{1, 7})
+	if err != nil {
+		t.Fatalf("BatchDeleteEvents: %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrBatchAborted) || !errors.Is(results[1].Err, storage.ErrEventNotFound) {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	results, err = a.BatchDeleteEvents(ctx, []int{2, 1})
+	if err != nil || !BatchApplied(results) {
+		t.Fatalf("BatchDeleteEvents: %+v, %v", results, err)
+	}
+	if events, _ := a.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll}); len(events) != 0 {
+		t.Errorf("%d events left after delete", len(events))
+	}
+}
@@ -0,0 +1,275 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/lrucache"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/metrics"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/session"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+var cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "storage_cache",
+	Name:      "requests_total",
+	Help:      "Lookups in the storage cache by cache and result.",
+}, []string{"cache", "result"})
+
+// cachedStore wraps a storage backend and keeps the events read by ID and the results of
+// ListEvents. Writes drop the entries they may change: the events they touch and every
+// list, since any write may change what a filter matches.
+type cachedStore struct {
+	storageInterface // calls that are not cached go straight to the backend
+
+	events lrucache.Cache[int, storage.Event]
+	lists  lrucache.Cache[string, []storage.Event]
+
+	// A read stores what it fetched only if no write invalidated the cache meanwhile,
+	// so that a slow read cannot put back what a write has just dropped.
+	mu         sync.Mutex
+	generation uint64
+
+	// tx is set on the view of a transaction that WithinTx passes on: its reads skip the
+	// cache and its writes are collected, to be dropped once the transaction is over.
+	tx *txWrites
+}
+
+// txWrites are the cache entries the writes of a transaction may change.
+type txWrites struct {
+	touched bool // any write, which changes the lists
+	all     bool // a write that may change any event
+	ids     []int
+}
+
+// cacheConfig returns the cache settings for cfg, with the TTL capped at the replica lag
+// Postgres allows when reads may go to replicas; see config.CacheConfig.
+func cacheConfig(cfg config.StorageConfig) config.CacheConfig {
+	cache := cfg.Cache
+	if cfg.Type != "postgres" || len(cfg.Postgres.Replicas) == 0 {
+		return cache
+	}
+	if lag := cfg.Postgres.MaxReplicaLag; lag <= 0 {
+		cache.Size = 0
+	} else if cache.TTL <= 0 || cache.TTL > lag {
+		cache.TTL = lag
+	}
+	return cache
+}
+
+func cacheStore(cfg config.CacheConfig, next storageInterface) storageInterface {
+	if cfg.Size <= 0 {
+		return next
+	}
+	return &cachedStore{
+		storageInterface: next,
+		events:           lrucache.NewCache[int, storage.Event](cfg.Size, cfg.TTL),
+		lists:            lrucache.NewCache[string, []storage.Event](cfg.Size, cfg.TTL),
+	}
+}
+
+func (s *cachedStore) currentGeneration() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generation
+}
+
+// fill stores a fetched value unless the cache was invalidated since generation.
+func (s *cachedStore) fill(generation uint64, set func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation == generation {
+		set()
+	}
+}
+
+// invalidate drops the events with the IDs and all lists.
+func (s *cachedStore) invalidate(ids ...int) {
+	if s.tx != nil {
+		s.tx.touched = true
+		s.tx.ids = append(s.tx.ids, ids...)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	for _, id := range ids {
+		s.events.Remove(id)
+	}
+	s.lists.Clear()
+}
+
+// invalidateAll drops everything, for writes that change many events at once.
+func (s *cachedStore) invalidateAll() {
+	if s.tx != nil {
+		s.tx.touched, s.tx.all = true, true
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	s.events.Clear()
+	s.lists.Clear()
+}
+
+// listKey identifies the events a filter selects at this moment; periods are resolved to
+// their bounds, so that the key of "today" changes at midnight.
+func listKey(filter storage.Filter) string {
+	var b strings.Builder
+	if from, to, bounded := filter.Bounds(); bounded {
+		fmt.Fprintf(&b, "%d..%d", from.UnixNano(), to.UnixNano())
+	} else {
+		b.WriteString("all")
+	}
+	fmt.Fprintf(&b, "|%v|%q|%q|%q|%q", filter.UserIDs, filter.Clinic, filter.Service, filter.Tags, filter.ResourceName)
+	return b.String()
+}
+
+// cloneEvent copies the slices of an event, so that callers and the cache don't share them.
+func cloneEvent(event storage.Event) storage.Event {
+	event.Attendees = append([]storage.Attendee(nil), event.Attendees...)
+	event.Tags = append([]string(nil), event.Tags...)
+	return event
+}
+
+func cloneEvents(events []storage.Event) []storage.Event {
+	clone := make([]storage.Event, len(events))
+	for i, event := range events {
+		clone[i] = cloneEvent(event)
+	}
+	return clone
+}
+
+// mustSeeWrites reports whether the caller's session requires its earlier writes to be seen.
+// Such reads skip the cache: a read without that requirement may have filled it from a
+// replica that had not replayed them yet.
+func mustSeeWrites(ctx context.Context) bool {
+	return session.FromContext(ctx).Token() != ""
+}
+
+func (s *cachedStore) GetEvent(ctx context.Context, id int) (storage.Event, error) {
+	if s.tx != nil || mustSeeWrites(ctx) {
+		return s.storageInterface.GetEvent(ctx, id)
+	}
+	if event, ok := s.events.Get(id); ok {
+		cacheRequests.WithLabelValues("event", "hit").Inc()
+		return cloneEvent(event), nil
+	}
+	cacheRequests.WithLabelValues("event", "miss").Inc()
+
+	generation := s.currentGeneration()
+	event, err := s.storageInterface.GetEvent(ctx, id)
+	if err != nil {
+		return event, err
+	}
+	s.fill(generation, func() { s.events.Set(id, cloneEvent(event)) })
+	return event, nil
+}
+
+func (s *cachedStore) ListEvents(ctx context.Context, filter storage.Filter) ([]storage.Event, error) {
+	if s.tx != nil || mustSeeWrites(ctx) {
+		return s.storageInterface.ListEvents(ctx, filter)
+	}
+	key := listKey(filter)
+	if events, ok := s.lists.Get(key); ok {
+		cacheRequests.WithLabelValues("list", "hit").Inc()
+		return cloneEvents(events), nil
+	}
+	cacheRequests.WithLabelValues("list", "miss").Inc()
+
+	generation := s.currentGeneration()
+	events, err := s.storageInterface.ListEvents(ctx, filter)
+	if err != nil {
+		return events, err
+	}
+	s.fill(generation, func() { s.lists.Set(key, cloneEvents(events)) })
+	return events, nil
+}
+
+func (s *cachedStore) CreateEvent(ctx context.Context, event storage.Event) (int, error) {
+	defer s.invalidate()
+	return s.storageInterface.CreateEvent(ctx, event)
+}
+
+func (s *cachedStore) UpdateEvent(ctx context.Context, event storage.Event) error {
+	defer s.invalidate(event.ID)
+	return s.storageInterface.UpdateEvent(ctx, event)
+}
+
+func (s *cachedStore) DeleteEvent(ctx context.Context, id int) error {
+	defer s.invalidate(id)
+	return s.storageInterface.DeleteEvent(ctx, id)
+}
+
+func (s *cachedStore) CreateEvents(ctx context.Context, events []storage.Event) ([]int, error) {
+	defer s.invalidate()
+	return s.storageInterface.CreateEvents(ctx, events)
+}
+
+func (s *cachedStore) DeleteEvents(ctx context.Context, ids []int) error {
+	defer s.invalidate(ids...)
+	return s.storageInterface.DeleteEvents(ctx, ids)
+}
+
+func (s *cachedStore) AddAttendee(ctx context.Context, attendee storage.Attendee) error {
+	defer s.invalidate(attendee.EventID)
+	return s.storageInterface.AddAttendee(ctx, attendee)
+}
+
+func (s *cachedStore) UpdateAttendee(ctx context.Context, attendee storage.Attendee) error {
+	defer s.invalidate(attendee.EventID)
+	return s.storageInterface.UpdateAttendee(ctx, attendee)
+}
+
+func (s *cachedStore) RemoveAttendee(ctx context.Context, eventID, userID int) error {
+	defer s.invalidate(eventID)
+	return s.storageInterface.RemoveAttendee(ctx, eventID, userID)
+}
+
+func (s *cachedStore) RecreateEvent(ctx context.Context, event storage.Event) error {
+	defer s.invalidate(event.ID)
+	return s.storageInterface.RecreateEvent(ctx, event)
+}
+
+func (s *cachedStore) UndeleteEvent(ctx context.Context, id int) error {
+	defer s.invalidate(id)
+	return s.storageInterface.UndeleteEvent(ctx, id)
+}
+
+func (s *cachedStore) UpdateCategory(ctx context.Context, category storage.Category) error {
+	defer s.invalidateAll() // renames the tag on all its events
+	return s.storageInterface.UpdateCategory(ctx, category)
+}
+
+func (s *cachedStore) DeleteCategory(ctx context.Context, id int) error {
+	defer s.invalidateAll() // untags all its events
+	return s.storageInterface.DeleteCategory(ctx, id)
+}
+
+func (s *cachedStore) SetEventTags(ctx context.Context, eventID int, tags []string) error {
+	defer s.invalidate(eventID)
+	return s.storageInterface.SetEventTags(ctx, eventID, tags)
+}
+
+// WithinTx drops the entries that the writes of the transaction may have changed once it is
+// over, whether it committed or not: a failed commit may still have taken effect.
+func (s *cachedStore) WithinTx(ctx context.Context, fn func(tx storage.Store) error) error {
+	var writes txWrites
+	err := withinTx(ctx, s.storageInterface, func(tx storageInterface) error {
+		writes = txWrites{} // a retried transaction starts over
+		return fn(&cachedStore{storageInterface: tx, tx: &writes})
+	})
+	switch {
+	case writes.all:
+		s.invalidateAll()
+	case writes.touched:
+		s.invalidate(writes.ids...)
+	}
+	return err
+}
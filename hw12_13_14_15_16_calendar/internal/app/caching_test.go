@@ -0,0 +1,198 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/session"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+// countingStore counts the reads that reach the backend.
+type countingStore struct {
+	storageInterface
+	gets, lists int
+}
+
+func (s *countingStore) GetEvent(ctx context.Context, id int) (storage.Event, error) {
+	s.gets++
+	return s.storageInterface.GetEvent(ctx, id)
+}
+
+func (s *countingStore) ListEvents(ctx context.Context, filter storage.Filter) ([]storage.Event, error) {
+	s.lists++
+	return s.storageInterface.ListEvents(ctx, filter)
+}
+
+func newCachedStore(t *testing.T) (storageInterface, *countingStore) {
+	t.Helper()
+	backend := &countingStore{storageInterface: memorystorage.New()}
+	return cacheStore(config.CacheConfig{Size: 10, TTL: time.Minute}, backend), backend
+}
+
+func TestCachedStore_GetEvent(t *testing.T) {
+	store, backend := newCachedStore(t)
+	ctx := context.Background()
+	id, err := store.CreateEvent(ctx, storage.Event{Title: "Checkup"})
+	if err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := store.GetEvent(ctx, id); err != nil {
+			t.Fatalf("GetEvent returned error: %v", err)
+		}
+	}
+	if backend.gets != 1 {
+		t.Errorf("expected 1 backend read, got %d", backend.gets)
+	}
+
+	if err := store.UpdateEvent(ctx, storage.Event{ID: id, Title: "Surgery"}); err != nil {
+		t.Fatalf("UpdateEvent returned error: %v", err)
+	}
+	event, err := store.GetEvent(ctx, id)
+	if err != nil {
+		t.Fatalf("GetEvent returned error: %v", err)
+	}
+	if event.Title != "Surgery" {
+		t.Errorf("expected the updated title, got %q", event.Title)
+	}
+
+	if err := store.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent returned error: %v", err)
+	}
+	if _, err := store.GetEvent(ctx, id); err == nil {
+		t.Error("expected error for deleted event, got nil")
+	}
+}
+
+func TestCachedStore_ListEvents(t *testing.T) {
+	store, backend := newCachedStore(t)
+	ctx := context.Background()
+	all := storage.Filter{Period: storage.PeriodAll}
+
+	for i := 0; i < 2; i++ {
+		if _, err := store.ListEvents(ctx, all); err != nil {
+			t.Fatalf("ListEvents returned error: %v", err)
+		}
+	}
+	if backend.lists != 1 {
+		t.Errorf("expected 1 backend list, got %d", backend.lists)
+	}
+
+	if _, err := store.CreateEvent(ctx, storage.Event{Title: "Checkup"}); err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+	events, err := store.ListEvents(ctx, all)
+	if err != nil {
+		t.Fatalf("ListEvents returned error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("expected the new event to be listed, got %d events", len(events))
+	}
+
+	// Callers may change what they get without changing the cache.
+	events[0].Title = "Changed"
+	events, err = store.ListEvents(ctx, all)
+	if err != nil {
+		t.Fatalf("ListEvents returned error: %v", err)
+	}
+	if events[0].Title != "Checkup" {
+		t.Errorf("expected the cached title, got %q", events[0].Title)
+	}
+}
+
+func TestCachedStore_SessionWithWrites(t *testing.T) {
+	store, backend := newCachedStore(t)
+	ctx := context.Background()
+	id, err := store.CreateEvent(ctx, storage.Event{Title: "Checkup"})
+	if err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+	if _, err := store.GetEvent(ctx, id); err != nil {
+		t.Fatalf("GetEvent returned error: %v", err)
+	}
+
+	// A cached read may predate writes the session has made elsewhere.
+	ctx = session.NewContext(ctx, session.New("0/16B3748"))
+	if _, err := store.GetEvent(ctx, id); err != nil {
+		t.Fatalf("GetEvent returned error: %v", err)
+	}
+	if _, err := store.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll}); err != nil {
+		t.Fatalf("ListEvents returned error: %v", err)
+	}
+	if backend.gets != 2 || backend.lists != 1 {
+		t.Errorf("expected reads of a session with writes to skip the cache, got %d gets and %d lists",
+			backend.gets, backend.lists)
+	}
+}
+
+func TestCachedStore_StaleFill(t *testing.T) {
+	store, _ := newCachedStore(t)
+	cache := store.(*cachedStore)
+	ctx := context.Background()
+	id, err := store.CreateEvent(ctx, storage.Event{Title: "Checkup"})
+	if err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+
+	// A read that started before a write must not cache what it read.
+	generation := cache.currentGeneration()
+	cache.invalidate(id)
+	cache.fill(generation, func() { cache.events.Set(id, storage.Event{ID: id, Title: "Stale"}) })
+	if _, ok := cache.events.Get(id); ok {
+		t.Error("expected the stale read not to be cached")
+	}
+}
+
+func TestCacheStore_Disabled(t *testing.T) {
+	backend := memorystorage.New()
+	if store := cacheStore(config.CacheConfig{}, backend); store != storageInterface(backend) {
+		t.Error("expected a zero size to disable the cache")
+	}
+}
+
+func TestCacheConfig_ReplicaLag(t *testing.T) {
+	cache := config.CacheConfig{Size: 100, TTL: 30 * time.Second}
+	postgres := config.PostgresConfig{Replicas: []string{"host=replica"}, MaxReplicaLag: time.Second}
+
+	for _, tc := range []struct {
+		name    string
+		storage config.StorageConfig
+		want    config.CacheConfig
+	}{
+		{"no replicas", config.StorageConfig{Type: "postgres", Cache: cache}, cache},
+		{"memory", config.StorageConfig{Type: "memory", Postgres: postgres, Cache: cache}, cache},
+		{
+			"capped at the lag",
+			config.StorageConfig{Type: "postgres", Postgres: postgres, Cache: cache},
+			config.CacheConfig{Size: 100, TTL: time.Second},
+		},
+		{
+			"no expiry capped too",
+			config.StorageConfig{Type: "postgres", Postgres: postgres, Cache: config.CacheConfig{Size: 100}},
+			config.CacheConfig{Size: 100, TTL: time.Second},
+		},
+		{
+			"shorter ttl kept",
+			config.StorageConfig{
+				Type: "postgres", Postgres: postgres, Cache: config.CacheConfig{Size: 100, TTL: time.Millisecond},
+			},
+			config.CacheConfig{Size: 100, TTL: time.Millisecond},
+		},
+		{
+			"no lag allowed",
+			config.StorageConfig{
+				Type: "postgres", Postgres: config.PostgresConfig{Replicas: postgres.Replicas}, Cache: cache,
+			},
+			config.CacheConfig{TTL: cache.TTL},
+		},
+	} {
+		if got := cacheConfig(tc.storage); got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
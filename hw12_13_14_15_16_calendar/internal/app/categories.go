@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CreateCategory validates and stores a new category.
+func (a *App) CreateCategory(ctx context.Context, category storage.Category) (storage.Category, error) {
+	if err := category.Normalize(); err != nil {
+		return storage.Category{}, err
+	}
+	id, err := a.store.CreateCategory(ctx, category)
+	if err != nil {
+		return storage.Category{}, err
+	}
+	category.ID = id
+	return category, nil
+}
+
+// ListCategories returns all categories ordered by name.
+func (a *App) ListCategories(ctx context.Context) ([]storage.Category, error) {
+	return a.store.ListCategories(ctx)
+}
+
+// UpdateCategory renames or recolors a category; its events carry the new name.
+func (a *App) UpdateCategory(ctx context.Context, category storage.Category) (storage.Category, error) {
+	if err := category.Normalize(); err != nil {
+		return storage.Category{}, err
+	}
+	if err := a.store.UpdateCategory(ctx, category); err != nil {
+		return storage.Category{}, err
+	}
+	return category, nil
+}
+
+// DeleteCategory removes a category and untags its events.
+func (a *App) DeleteCategory(ctx context.Context, id int) error {
+	return a.store.DeleteCategory(ctx, id)
+}
+
+// SetEventTags replaces the tags of an event with the named categories and returns the
+// event. Names are trimmed and repetitions dropped; an empty list removes all tags.
+func (a *App) SetEventTags(ctx context.Context, eventID int, tags []string) (storage.Event, error) {
+	var names []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			names = append(names, tag)
+		}
+	}
+	if err := a.writeDetails(ctx, eventID, func(tx storageInterface) error {
+		return tx.SetEventTags(ctx, eventID, names)
+	}); err != nil {
+		return storage.Event{}, err
+	}
+	return a.store.GetEvent(ctx, eventID)
+}
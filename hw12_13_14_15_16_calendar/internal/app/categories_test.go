@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+func TestSetEventTags(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+
+	_, err := a.CreateCategory(ctx, storage.Category{Name: "Urgent", Color: "red"})
+	if !errors.Is(err, storage.ErrInvalidCategory) {
+		t.Errorf("expected ErrInvalidCategory for a named color, got %v", err)
+	}
+	urgent, err := a.CreateCategory(ctx, storage.Category{Name: " Urgent ", Color: "#FF0000"})
+	if err != nil || urgent.Name != "Urgent" || urgent.Color != "#ff0000" {
+		t.Fatalf("CreateCategory: %+v, %v", urgent, err)
+	}
+	if _, err := a.CreateCategory(ctx, storage.Category{Name: "Follow-up"}); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+
+	id, err := a.CreateEvent(ctx, storage.Event{Title: "Check-up"})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	event, err := a.SetEventTags(ctx, id, []string{"Urgent", " Follow-up", "Urgent", ""})
+	if err != nil {
+		t.Fatalf("SetEventTags: %v", err)
+	}
+	if len(event.Tags) != 2 || event.Tags[0] != "Follow-up" || event.Tags[1] != "Urgent" {
+		t.Errorf("unexpected tags %v", event.Tags)
+	}
+	if _, err := a.SetEventTags(ctx, id, []string{"Unknown"}); !errors.Is(err, storage.ErrCategoryNotFound) {
+		t.Errorf("expected ErrCategoryNotFound, got %v", err)
+	}
+	if _, err := a.SetEventTags(ctx, 99, nil); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("expected ErrEventNotFound, got %v", err)
+	}
+
+	// Tagging is an update in the event history.
+	entries, err := a.GetEventHistory(ctx, id)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("GetEventHistory: %d entries, %v", len(entries), err)
+	}
+	if changes := entries[1].Changes(); len(changes) != 1 || changes[0].Field != "tags" ||
+		changes[0].After != "Follow-up, Urgent" {
+		t.Errorf("unexpected tag diff %+v", changes)
+	}
+
+	// Updating the event keeps its tags.
+	if err := a.UpdateEvent(ctx, storage.Event{ID: id, Title: "Check-up (moved)"}); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	if got, _ := a.GetEvent(ctx, id); len(got.Tags) != 2 {
+		t.Errorf("expected UpdateEvent to keep the tags, got %v", got.Tags)
+	}
+}
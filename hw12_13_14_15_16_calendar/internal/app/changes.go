@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+var (
+	ErrWatchForbidden = errors.New("only the caller's own events can be watched")
+	// ErrWatcherTooSlow ends a watch whose client did not keep up with the changes; the
+	// client has to reload its events and watch again.
+	ErrWatcherTooSlow = errors.New("watcher fell behind, reload the events and watch again")
+)
+
+const (
+	// watcherBuffer is how many changes a watcher may fall behind before it is dropped.
+	watcherBuffer = 256
+	// listenRetryDelay is the pause before the change listener reconnects.
+	listenRetryDelay = 5 * time.Second
+)
+
+// changeSource is implemented by backends that announce changes made by any client,
+// e.g. other calendar replicas sharing the database.
+type changeSource interface {
+	ListenChanges(ctx context.Context, handle func(storage.Change)) error
+}
+
+// Watcher receives the changes to the events of a user, or of all users. Deletions are
+// sent to the users the event involved when it was deleted.
+type Watcher struct {
+	c      chan storage.Change
+	userID int
+	bus    *changeBus
+	err    error // why c was closed, set before closing
+}
+
+// Changes returns the channel of changes. It is closed by Close or when the watcher
+// falls behind; Err tells which.
+func (w *Watcher) Changes() <-chan storage.Change {
+	return w.c
+}
+
+// Err returns ErrWatcherTooSlow if the watcher was dropped, otherwise nil.
+func (w *Watcher) Err() error {
+	w.bus.mu.Lock()
+	defer w.bus.mu.Unlock()
+	return w.err
+}
+
+// Close stops the watcher and closes its channel.
+func (w *Watcher) Close() {
+	w.bus.remove(w, nil)
+}
+
+func (w *Watcher) wants(change storage.Change) bool {
+	if w.userID == 0 || (change.Event != nil && change.Event.Involves(w.userID)) {
+		return true
+	}
+	for _, userID := range change.Involved {
+		if userID == w.userID {
+			return true
+		}
+	}
+	return false
+}
+
+// changeBus fans the changes out to the watchers. The zero value is ready to use.
+type changeBus struct {
+	mu       sync.Mutex
+	watchers map[*Watcher]struct{}
+}
+
+func (b *changeBus) add(w *Watcher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.watchers == nil {
+		b.watchers = make(map[*Watcher]struct{})
+	}
+	b.watchers[w] = struct{}{}
+}
+
+func (b *changeBus) remove(w *Watcher, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(w, err)
+}
+
+func (b *changeBus) removeLocked(w *Watcher, err error) {
+	if _, ok := b.watchers[w]; !ok {
+		return
+	}
+	delete(b.watchers, w)
+	w.err = err
+	close(w.c)
+}
+
+func (b *changeBus) empty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.watchers) == 0
+}
+
+// publish never blocks: a watcher with a full buffer is dropped.
+func (b *changeBus) publish(change storage.Change) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for w := range b.watchers {
+		if !w.wants(change) {
+			continue
+		}
+		select {
+		case w.c <- change:
+		default:
+			b.removeLocked(w, ErrWatcherTooSlow)
+		}
+	}
+}
+
+// WatchEvents starts watching the events owned or attended by userID; 0 means the
+// authenticated caller's user or, for callers without one, all events. A caller bound to
+// a user can only watch that user. The caller must Close the watcher.
+func (a *App) WatchEvents(ctx context.Context, userID int) (*Watcher, error) {
+	if caller := callerUserID(ctx); caller != nil {
+		if userID == 0 {
+			userID = *caller
+		}
+		if userID != *caller {
+			return nil, ErrWatchForbidden
+		}
+	}
+	w := &Watcher{c: make(chan storage.Change, watcherBuffer), userID: userID, bus: &a.changes}
+	a.changes.add(w)
+	return w, nil
+}
+
+// changed announces a write made through the App. Backends with their own change feed
+// announce writes themselves, so nothing is done for them.
+func (a *App) changed(ctx context.Context, changeType storage.ChangeType, id int) {
+	if a.changeFeed {
+		return
+	}
+	// The write is done; announcing it must not fail with the caller's context.
+	a.publishChange(context.WithoutCancel(ctx), storage.Change{Type: changeType, EventID: id, At: time.Now()})
+}
+
+// publishChange loads the event of a create or update, or the users a deleted event
+// involved, and passes the change to the watchers. A change to an event that is already
+// gone is dropped; its deletion follows.
+func (a *App) publishChange(ctx context.Context, change storage.Change) {
+	if a.changes.empty() {
+		return
+	}
+	if change.Type == storage.ChangeDeleted {
+		change.Involved = a.deletedEventUsers(ctx, change.EventID)
+	} else {
+		event, err := a.store.GetEvent(ctx, change.EventID)
+		if err != nil {
+			if !errors.Is(err, storage.ErrEventNotFound) {
+				a.log.Error(fmt.Sprintf("failed to load changed event %d: %v", change.EventID, err))
+			}
+			return
+		}
+		change.Event = &event
+	}
+	a.changes.publish(change)
+}
+
+// deletedEventUsers returns the owner and attendees of a deleted event, taken from the
+// snapshot recorded in its history when it was deleted. Without one, only the watchers of
+// all events learn about the deletion.
+func (a *App) deletedEventUsers(ctx context.Context, eventID int) []int {
+	entries, err := a.store.ListHistory(ctx, eventID)
+	if err != nil {
+		a.log.Error(fmt.Sprintf("failed to load history of deleted event %d: %v", eventID, err))
+		return nil
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		before := entries[i].Before
+		if entries[i].Action != storage.ActionDeleted || before == nil {
+			continue
+		}
+		var users []int
+		if before.UserID != nil {
+			users = append(users, *before.UserID)
+		}
+		for _, attendee := range before.Attendees {
+			users = append(users, attendee.UserID)
+		}
+		return users
+	}
+	return nil
+}
+
+// followChanges feeds the watchers from the backend's change feed until ctx is done,
+// reconnecting after failures. Changes made by other clients are dropped from the cache.
+func (a *App) followChanges(ctx context.Context, source changeSource) {
+	cache, _ := a.store.(*cachedStore)
+	for {
+		err := source.ListenChanges(ctx, func(change storage.Change) {
+			if cache != nil {
+				cache.invalidate(change.EventID)
+			}
+			a.publishChange(ctx, change)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		a.log.Error(fmt.Sprintf("change listener failed, retrying in %s: %v", listenRetryDelay, err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
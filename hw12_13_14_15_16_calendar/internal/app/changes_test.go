@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+func nextChange(t *testing.T, w *Watcher) storage.Change {
+	t.Helper()
+	select {
+	case change, ok := <-w.Changes():
+		if !ok {
+			t.Fatalf("watcher closed: %v", w.Err())
+		}
+		return change
+	case <-time.After(time.Second):
+		t.Fatal("no change received")
+	}
+	return storage.Change{}
+}
+
+func TestWatchEvents(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+
+	all, err := a.WatchEvents(ctx, 0)
+	if err != nil {
+		t.Fatalf("WatchEvents: %v", err)
+	}
+	defer all.Close()
+	mine, err := a.WatchEvents(ctx, 7)
+	if err != nil {
+		t.Fatalf("WatchEvents: %v", err)
+	}
+	defer mine.Close()
+
+	other, owner := 8, 7
+	if _, err := a.CreateEvent(ctx, storage.Event{Title: "Not mine", UserID: &other}); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if _, err := a.CreateEvent(ctx, storage.Event{Title: "Mine", UserID: &owner}); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if err := a.InviteAttendee(ctx, storage.Attendee{EventID: 1, UserID: owner}); err != nil {
+		t.Fatalf("InviteAttendee: %v", err)
+	}
+	if _, err := a.CreateEvent(ctx, storage.Event{Title: "Not mine either", UserID: &other}); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	for _, id := range []int{3, 2, 1} {
+		if err := a.DeleteEvent(ctx, id); err != nil {
+			t.Fatalf("DeleteEvent: %v", err)
+		}
+	}
+
+	for _, want := range []struct {
+		typ storage.ChangeType
+		id  int
+	}{
+		{storage.ChangeCreated, 1}, {storage.ChangeCreated, 2}, {storage.ChangeUpdated, 1}, {storage.ChangeCreated, 3},
+		{storage.ChangeDeleted, 3}, {storage.ChangeDeleted, 2}, {storage.ChangeDeleted, 1},
+	} {
+		if change := nextChange(t, all); change.Type != want.typ || change.EventID != want.id {
+			t.Errorf("got %s %d, want %s %d", change.Type, change.EventID, want.typ, want.id)
+		}
+	}
+
+	change := nextChange(t, mine)
+	if change.Type != storage.ChangeCreated || change.Event == nil || change.Event.Title != "Mine" {
+		t.Errorf("unexpected first change %+v", change)
+	}
+	// The invitation makes event 1 one of the user's events.
+	change = nextChange(t, mine)
+	if change.Type != storage.ChangeUpdated || change.EventID != 1 || len(change.Event.Attendees) != 1 {
+		t.Errorf("unexpected second change %+v", change)
+	}
+	// The deletion of another user's event is not sent; those of the user's events are.
+	for _, id := range []int{2, 1} {
+		if change = nextChange(t, mine); change.Type != storage.ChangeDeleted || change.EventID != id || change.Event != nil {
+			t.Errorf("unexpected deletion %+v, want event %d", change, id)
+		}
+	}
+
+	mine.Close()
+	if _, ok := <-mine.Changes(); ok || mine.Err() != nil {
+		t.Errorf("closed watcher: err %v", mine.Err())
+	}
+}
+
+func TestWatchEvents_SlowWatcher(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+	w, err := a.WatchEvents(ctx, 0)
+	if err != nil {
+		t.Fatalf("WatchEvents: %v", err)
+	}
+
+	for i := 0; i <= watcherBuffer; i++ {
+		if _, err := a.CreateEvent(ctx, storage.Event{Title: "Flood"}); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+	}
+	received := 0
+	for range w.Changes() {
+		received++
+	}
+	if received != watcherBuffer || !errors.Is(w.Err(), ErrWatcherTooSlow) {
+		t.Errorf("received %d changes, err %v", received, w.Err())
+	}
+}
+
+func TestWatchEvents_Forbidden(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	uid := 7
+	ctx := auth.NewContext(context.Background(), auth.Identity{Subject: "staff", UserID: &uid})
+
+	if _, err := a.WatchEvents(ctx, 8); !errors.Is(err, ErrWatchForbidden) {
+		t.Errorf("expected ErrWatchForbidden, got %v", err)
+	}
+	w, err := a.WatchEvents(ctx, 0)
+	if err != nil {
+		t.Fatalf("WatchEvents: %v", err)
+	}
+	defer w.Close()
+	if w.userID != uid {
+		t.Errorf("watching user %d, want the caller %d", w.userID, uid)
+	}
+}
+
+// feed is a changeSource that delivers its changes and then fails.
+type feed []storage.Change
+
+func (f feed) ListenChanges(_ context.Context, handle func(storage.Change)) error {
+	for _, change := range f {
+		handle(change)
+	}
+	return errors.New("connection lost")
+}
+
+func TestFollowChanges(t *testing.T) {
+	store := memorystorage.New()
+	a := &App{log: logger.New(""), store: store, changeFeed: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := a.WatchEvents(ctx, 0)
+	if err != nil {
+		t.Fatalf("WatchEvents: %v", err)
+	}
+	defer w.Close()
+
+	// With a change feed, writes through the App are not announced twice.
+	if _, err := a.CreateEvent(ctx, storage.Event{Title: "From this replica"}); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	go a.followChanges(ctx, feed{
+		{Type: storage.ChangeUpdated, EventID: 9}, // already deleted, dropped
+		{Type: storage.ChangeCreated, EventID: 1},
+		{Type: storage.ChangeDeleted, EventID: 9},
+	})
+
+	if change := nextChange(t, w); change.Type != storage.ChangeCreated || change.Event.Title != "From this replica" {
+		t.Errorf("unexpected change %+v", change)
+	}
+	if change := nextChange(t, w); change.Type != storage.ChangeDeleted || change.EventID != 9 {
+		t.Errorf("unexpected change %+v", change)
+	}
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+var ErrNotRestorable = errors.New("event is not deleted")
+
+// anonymousActor is recorded for writes made without authentication.
+const anonymousActor = "anonymous"
+
+// GetEventHistory returns the recorded writes to an event, oldest first. Deleted events
+// keep their history; an event that never existed has none.
+func (a *App) GetEventHistory(ctx context.Context, eventID int) ([]storage.HistoryEntry, error) {
+	return eventHistory(ctx, a.store, eventID)
+}
+
+func eventHistory(ctx context.Context, store storageInterface, eventID int) ([]storage.HistoryEntry, error) {
+	entries, err := store.ListHistory(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, storage.ErrEventNotFound
+	}
+	return entries, nil
+}
+
+// RestoreEvent takes a deleted event out of the trash. Once the event has been purged
+// from the trash, it is recreated under its ID, with its attendees, from the snapshot
+// taken when it was deleted.
+func (a *App) RestoreEvent(ctx context.Context, eventID int) (event storage.Event, err error) {
+	err = withinTx(ctx, a.store, func(tx storageInterface) error {
+		event, err = restoreEvent(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+		return a.record(ctx, tx, storage.ActionRestored, eventID, nil, &event)
+	})
+	if err != nil {
+		return storage.Event{}, err
+	}
+	a.announce(ctx, storage.ActionRestored, eventID)
+	return event, nil
+}
+
+func restoreEvent(ctx context.Context, tx storageInterface, eventID int) (storage.Event, error) {
+	err := tx.UndeleteEvent(ctx, eventID)
+	if err == nil {
+		return tx.GetEvent(ctx, eventID)
+	}
+	if !errors.Is(err, storage.ErrEventNotFound) {
+		return storage.Event{}, err
+	}
+
+	entries, err := eventHistory(ctx, tx, eventID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	last := entries[len(entries)-1]
+	if last.Action != storage.ActionDeleted || last.Before == nil {
+		return storage.Event{}, ErrNotRestorable
+	}
+
+	event := *last.Before
+	if err := tx.RecreateEvent(ctx, event); err != nil {
+		if errors.Is(err, storage.ErrEventExists) {
+			return storage.Event{}, ErrNotRestorable
+		}
+		return storage.Event{}, err
+	}
+	return event, nil
+}
+
+// record adds a write to the event history, in the transaction of the write, so that
+// neither takes effect without the other.
+func (a *App) record(
+	ctx context.Context,
+	tx storageInterface,
+	action storage.HistoryAction,
+	eventID int,
+	before, after *storage.Event,
+) error {
+	entry := storage.HistoryEntry{
+		EventID: eventID,
+		Action:  action,
+		Actor:   anonymousActor,
+		At:      time.Now().UTC(),
+		Before:  before,
+		After:   after,
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		entry.Actor, entry.ActorUserID = identity.Subject, identity.UserID
+	}
+	if err := tx.AddHistory(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record %s of event %d: %w", action, eventID, err)
+	}
+	return nil
+}
+
+// announce passes a write to the watchers once its transaction has committed.
+func (a *App) announce(ctx context.Context, action storage.HistoryAction, eventID int) {
+	switch action {
+	case storage.ActionCreated, storage.ActionRestored:
+		a.changed(ctx, storage.ChangeCreated, eventID)
+	case storage.ActionUpdated:
+		a.changed(ctx, storage.ChangeUpdated, eventID)
+	case storage.ActionDeleted:
+		a.changed(ctx, storage.ChangeDeleted, eventID)
+	}
+}
+
+// writeDetails runs a write to the attendees or tags of an event and records the event
+// before and after it, all in one transaction.
+func (a *App) writeDetails(ctx context.Context, eventID int, write func(tx storageInterface) error) error {
+	err := withinTx(ctx, a.store, func(tx storageInterface) error {
+		before, err := tx.GetEvent(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if err := write(tx); err != nil {
+			return err
+		}
+		after, err := tx.GetEvent(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		return a.record(ctx, tx, storage.ActionUpdated, eventID, &before, &after)
+	})
+	if err != nil {
+		return err
+	}
+	a.announce(ctx, storage.ActionUpdated, eventID)
+	return nil
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+func TestEventHistory(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	uid := 7
+	ctx := auth.NewContext(context.Background(), auth.Identity{Subject: "front-desk", UserID: &uid})
+
+	id, err := a.CreateEvent(context.Background(), storage.Event{Title: "Check-up"})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if err := a.UpdateEvent(ctx, storage.Event{ID: id, Title: "Check-up (moved)"}); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	if err := a.InviteAttendee(ctx, storage.Attendee{EventID: id, UserID: 3}); err != nil {
+		t.Fatalf("InviteAttendee: %v", err)
+	}
+	if err := a.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+
+	entries, err := a.GetEventHistory(ctx, id)
+	if err != nil {
+		t.Fatalf("GetEventHistory: %v", err)
+	}
+	wantActions := []storage.HistoryAction{
+		storage.ActionCreated, storage.ActionUpdated, storage.ActionUpdated, storage.ActionDeleted,
+	}
+	if len(entries) != len(wantActions) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(wantActions))
+	}
+	for i, want := range wantActions {
+		if entries[i].Action != want {
+			t.Errorf("entry %d: got %s, want %s", i, entries[i].Action, want)
+		}
+	}
+	if entries[0].Actor != anonymousActor || entries[1].Actor != "front-desk" || *entries[1].ActorUserID != uid {
+		t.Errorf("unexpected actors %q and %q", entries[0].Actor, entries[1].Actor)
+	}
+	changes := entries[1].Changes()
+	if len(changes) != 1 || changes[0].Field != "title" || changes[0].After != "Check-up (moved)" {
+		t.Errorf("unexpected update diff %+v", changes)
+	}
+	if changes := entries[2].Changes(); len(changes) != 1 || changes[0].Field != "attendees" {
+		t.Errorf("unexpected invitation diff %+v", changes)
+	}
+
+	if _, err := a.GetEventHistory(ctx, 99); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("expected ErrEventNotFound for an unknown event, got %v", err)
+	}
+}
+
+func TestRestoreEvent(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+
+	id, err := a.CreateEvent(ctx, storage.Event{Title: "Surgery"})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if err := a.InviteAttendee(ctx, storage.Attendee{EventID: id, UserID: 3}); err != nil {
+		t.Fatalf("InviteAttendee: %v", err)
+	}
+	if _, err := a.RestoreEvent(ctx, id); !errors.Is(err, ErrNotRestorable) {
+		t.Errorf("expected ErrNotRestorable for a live event, got %v", err)
+	}
+	if err := a.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+
+	restored, err := a.RestoreEvent(ctx, id)
+	if err != nil {
+		t.Fatalf("RestoreEvent: %v", err)
+	}
+	if restored.ID != id || restored.Title != "Surgery" {
+		t.Errorf("unexpected restored event %+v", restored)
+	}
+	got, err := a.GetEvent(ctx, id)
+	if err != nil || len(got.Attendees) != 1 {
+		t.Errorf("GetEvent after restore: %+v, %v", got, err)
+	}
+	entries, _ := a.GetEventHistory(ctx, id)
+	if last := entries[len(entries)-1]; last.Action != storage.ActionRestored {
+		t.Errorf("last entry is %s, want restored", last.Action)
+	}
+
+	// New events don't reuse the restored ID.
+	if next, err := a.CreateEvent(ctx, storage.Event{Title: "Next"}); err != nil || next == id {
+		t.Errorf("CreateEvent after restore: %d, %v", next, err)
+	}
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/metrics"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+var storageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "storage",
+	Name:      "operation_duration_seconds",
+	Help:      "Latency of storage operations by backend, operation and outcome.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"backend", "operation", "status"})
+
+// instrumentedStore wraps a storage backend and records the latency of every call.
+// Methods use named results so the deferred observe sees the returned error.
+type instrumentedStore struct {
+	next    storageInterface
+	backend string
+}
+
+func instrumentStore(backend string, next storageInterface) storageInterface {
+	return &instrumentedStore{next: next, backend: backend}
+}
+
+func (s *instrumentedStore) observe(operation string, start time.Time, err *error) {
+	status := "ok"
+	if *err != nil {
+		status = "error"
+	}
+	storageDuration.WithLabelValues(s.backend, operation, status).Observe(time.Since(start).Seconds())
+}
+
+func (s *instrumentedStore) CreateEvent(ctx context.Context, event storage.Event) (id int, err error) {
+	defer s.observe("create_event", time.Now(), &err)
+	return s.next.CreateEvent(ctx, event)
+}
+
+func (s *instrumentedStore) GetEvent(ctx context.Context, id int) (event storage.Event, err error) {
+	defer s.observe("get_event", time.Now(), &err)
+	return s.next.GetEvent(ctx, id)
+}
+
+func (s *instrumentedStore) ListEvents(ctx context.Context, filter storage.Filter) (events []storage.Event, err error) {
+	defer s.observe("list_events", time.Now(), &err)
+	return s.next.ListEvents(ctx, filter)
+}
+
+func (s *instrumentedStore) UpdateEvent(ctx context.Context, event storage.Event) (err error) {
+	defer s.observe("update_event", time.Now(), &err)
+	return s.next.UpdateEvent(ctx, event)
+}
+
+func (s *instrumentedStore) DeleteEvent(ctx context.Context, id int) (err error) {
+	defer s.observe("delete_event", time.Now(), &err)
+	return s.next.DeleteEvent(ctx, id)
+}
+
+func (s *instrumentedStore) CreateEvents(ctx context.Context, events []storage.Event) (ids []int, err error) {
+	defer s.observe("create_events", time.Now(), &err)
+	return s.next.CreateEvents(ctx, events)
+}
+
+func (s *instrumentedStore) DeleteEvents(ctx context.Context, ids []int) (err error) {
+	defer s.observe("delete_events", time.Now(), &err)
+	return s.next.DeleteEvents(ctx, ids)
+}
+
+func (s *instrumentedStore) AddAttendee(ctx context.Context, attendee storage.Attendee) (err error) {
+	defer s.observe("add_attendee", time.Now(), &err)
+	return s.next.AddAttendee(ctx, attendee)
+}
+
+func (s *instrumentedStore) ListAttendees(ctx context.Context, eventID int) (attendees []storage.Attendee, err error) {
+	defer s.observe("list_attendees", time.Now(), &err)
+	return s.next.ListAttendees(ctx, eventID)
+}
+
+func (s *instrumentedStore) UpdateAttendee(ctx context.Context, attendee storage.Attendee) (err error) {
+	defer s.observe("update_attendee", time.Now(), &err)
+	return s.next.UpdateAttendee(ctx, attendee)
+}
+
+func (s *instrumentedStore) RemoveAttendee(ctx context.Context, eventID, userID int) (err error) {
+	defer s.observe("remove_attendee", time.Now(), &err)
+	return s.next.RemoveAttendee(ctx, eventID, userID)
+}
+
+func (s *instrumentedStore) AddHistory(ctx context.Context, entry storage.HistoryEntry) (err error) {
+	defer s.observe("add_history", time.Now(), &err)
+	return s.next.AddHistory(ctx, entry)
+}
+
+func (s *instrumentedStore) ListHistory(ctx context.Context, eventID int) (entries []storage.HistoryEntry, err error) {
+	defer s.observe("list_history", time.Now(), &err)
+	return s.next.ListHistory(ctx, eventID)
+}
+
+func (s *instrumentedStore) RecreateEvent(ctx context.Context, event storage.Event) (err error) {
+	defer s.observe("recreate_event", time.Now(), &err)
+	return s.next.RecreateEvent(ctx, event)
+}
+
+func (s *instrumentedStore) ListDeletedEvents(ctx context.Context) (events []storage.Event, err error) {
+	defer s.observe("list_deleted_events", time.Now(), &err)
+	return s.next.ListDeletedEvents(ctx)
+}
+
+func (s *instrumentedStore) UndeleteEvent(ctx context.Context, id int) (err error) {
+	defer s.observe("undelete_event", time.Now(), &err)
+	return s.next.UndeleteEvent(ctx, id)
+}
+
+func (s *instrumentedStore) PurgeDeletedEvents(ctx context.Context, before time.Time) (n int, err error) {
+	defer s.observe("purge_deleted_events", time.Now(), &err)
+	return s.next.PurgeDeletedEvents(ctx, before)
+}
+
+func (s *instrumentedStore) CreateCategory(ctx context.Context, category storage.Category) (id int, err error) {
+	defer s.observe("create_category", time.Now(), &err)
+	return s.next.CreateCategory(ctx, category)
+}
+
+func (s *instrumentedStore) ListCategories(ctx context.Context) (categories []storage.Category, err error) {
+	defer s.observe("list_categories", time.Now(), &err)
+	return s.next.ListCategories(ctx)
+}
+
+func (s *instrumentedStore) UpdateCategory(ctx context.Context, category storage.Category) (err error) {
+	defer s.observe("update_category", time.Now(), &err)
+	return s.next.UpdateCategory(ctx, category)
+}
+
+func (s *instrumentedStore) DeleteCategory(ctx context.Context, id int) (err error) {
+	defer s.observe("delete_category", time.Now(), &err)
+	return s.next.DeleteCategory(ctx, id)
+}
+
+func (s *instrumentedStore) SetEventTags(ctx context.Context, eventID int, tags []string) (err error) {
+	defer s.observe("set_event_tags", time.Now(), &err)
+	return s.next.SetEventTags(ctx, eventID, tags)
+}
+
+func (s *instrumentedStore) SearchEvents(
+	ctx context.Context,
+	query storage.SearchQuery,
+) (results []storage.SearchResult, err error) {
+	defer s.observe("search_events", time.Now(), &err)
+	return s.next.SearchEvents(ctx, query)
+}
+
+// WithinTx observes the whole transaction as within_tx; the operations of tx are observed
+// one by one as well.
+func (s *instrumentedStore) WithinTx(ctx context.Context, fn func(tx storage.Store) error) (err error) {
+	defer s.observe("within_tx", time.Now(), &err)
+	return withinTx(ctx, s.next, func(tx storageInterface) error {
+		return fn(&instrumentedStore{next: tx, backend: s.backend})
+	})
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// observations returns how many samples the storage histogram holds for the given labels.
+func observations(t *testing.T, operation, status string) uint64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather returned error: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "calendar_storage_operation_duration_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["backend"] == "fake" && labels["operation"] == operation && labels["status"] == status {
+				return m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestInstrumentedStore_RecordsOutcome(t *testing.T) {
+	store := instrumentStore("fake", newFakeStorage())
+	ctx := context.Background()
+	created, failed := observations(t, "create_event", "ok"), observations(t, "get_event", "error")
+
+	if _, err := store.CreateEvent(ctx, storage.Event{ID: 1, Title: "Checkup"}); err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+	if _, err := store.GetEvent(ctx, 42); err == nil {
+		t.Fatal("expected error for missing event, got nil")
+	}
+
+	if got := observations(t, "create_event", "ok") - created; got != 1 {
+		t.Errorf("expected 1 new successful create_event observation, got %d", got)
+	}
+	if got := observations(t, "get_event", "error") - failed; got != 1 {
+		t.Errorf("expected 1 new failed get_event observation, got %d", got)
+	}
+}
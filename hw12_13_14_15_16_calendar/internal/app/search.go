@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// Number of search results returned by default and at most.
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// SearchEvents finds the events whose title or description contains all words and quoted
+// phrases of the text (see storage.ParseSearch), best matches first. The filter restricts
+// the results further, e.g. to a date range; its period defaults to all time. A limit of
+// 0 means DefaultSearchLimit.
+func (a *App) SearchEvents(
+	ctx context.Context,
+	text string,
+	filter storage.Filter,
+	limit int,
+) ([]storage.SearchResult, error) {
+	phrases, err := storage.ParseSearch(text)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 0 || limit > MaxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", storage.ErrInvalidSearch, MaxSearchLimit)
+	}
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+	if filter.Period == "" {
+		filter.Period = storage.PeriodAll
+	}
+	return a.store.SearchEvents(ctx, storage.SearchQuery{Phrases: phrases, Filter: filter, Limit: limit})
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+func TestSearchEvents(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+
+	for _, title := range []string{"Flu shot", "Flu shot booster", "Dental check"} {
+		if _, err := a.CreateEvent(ctx, storage.Event{Title: title}); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+	}
+
+	results, err := a.SearchEvents(ctx, `"FLU shot"`, storage.Filter{}, 0)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("SearchEvents: %+v, %v", results, err)
+	}
+	if results, _ := a.SearchEvents(ctx, "flu booster", storage.Filter{}, 0); len(results) != 1 {
+		t.Errorf("expected all words to be required, got %+v", results)
+	}
+	if results, _ := a.SearchEvents(ctx, "flu", storage.Filter{}, 1); len(results) != 1 {
+		t.Errorf("expected the limit to apply, got %+v", results)
+	}
+
+	for _, tc := range []struct {
+		text  string
+		limit int
+	}{{"", 0}, {`"!?"`, 0}, {"flu", -1}, {"flu", MaxSearchLimit + 1}} {
+		if _, err := a.SearchEvents(ctx, tc.text, storage.Filter{}, tc.limit); !errors.Is(err, storage.ErrInvalidSearch) {
+			t.Errorf("SearchEvents(%q, %d): expected ErrInvalidSearch, got %v", tc.text, tc.limit, err)
+		}
+	}
+}
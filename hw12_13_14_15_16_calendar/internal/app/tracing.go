@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedStore wraps a storage backend and opens a span around every call.
+// Like instrumentedStore, methods use named results so the deferred end sees the error.
+type tracedStore struct {
+	next    storageInterface
+	backend string
+}
+
+func traceStore(backend string, next storageInterface) storageInterface {
+	return &tracedStore{next: next, backend: backend}
+}
+
+func (s *tracedStore) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (
+	context.Context, trace.Span,
+) {
+	attrs = append(attrs, attribute.String("storage.backend", s.backend))
+	return tracing.Tracer().Start(ctx, "storage."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+func (s *tracedStore) CreateEvent(ctx context.Context, event storage.Event) (id int, err error) {
+	ctx, span := s.start(ctx, "CreateEvent")
+	defer endSpan(span, &err)
+	return s.next.CreateEvent(ctx, event)
+}
+
+func (s *tracedStore) GetEvent(ctx context.Context, id int) (event storage.Event, err error) {
+	ctx, span := s.start(ctx, "GetEvent", attribute.Int("event.id", id))
+	defer endSpan(span, &err)
+	return s.next.GetEvent(ctx, id)
+}
+
+func (s *tracedStore) ListEvents(ctx context.Context, filter storage.Filter) (events []storage.Event, err error) {
+	attrs := []attribute.KeyValue{attribute.String("event.period", string(filter.Period))}
+	if filter.Location != nil {
+		attrs = append(attrs, attribute.String("event.time_zone", filter.Location.String()))
+	}
+	if from, to, bounded := filter.Bounds(); bounded && (!filter.From.IsZero() || !filter.To.IsZero()) {
+		attrs = append(attrs,
+			attribute.String("event.from", from.Format(time.RFC3339)),
+			attribute.String("event.to", to.Format(time.RFC3339)))
+	}
+	if len(filter.UserIDs) > 0 {
+		attrs = append(attrs, attribute.IntSlice("event.user_ids", filter.UserIDs))
+	}
+	if filter.Clinic != "" {
+		attrs = append(attrs, attribute.String("event.clinic", filter.Clinic))
+	}
+	if filter.Service != "" {
+		attrs = append(attrs, attribute.String("event.service", filter.Service))
+	}
+	if len(filter.Tags) > 0 {
+		attrs = append(attrs, attribute.StringSlice("event.tags", filter.Tags))
+	}
+	ctx, span := s.start(ctx, "ListEvents", attrs...)
+	defer endSpan(span, &err)
+	return s.next.ListEvents(ctx, filter)
+}
+
+func (s *tracedStore) UpdateEvent(ctx context.Context, event storage.Event) (err error) {
+	ctx, span := s.start(ctx, "UpdateEvent", attribute.Int("event.id", event.ID))
+	defer endSpan(span, &err)
+	return s.next.UpdateEvent(ctx, event)
+}
+
+func (s *tracedStore) DeleteEvent(ctx context.Context, id int) (err error) {
+	ctx, span := s.start(ctx, "DeleteEvent", attribute.Int("event.id", id))
+	defer endSpan(span, &err)
+	return s.next.DeleteEvent(ctx, id)
+}
+
+func (s *tracedStore) CreateEvents(ctx context.Context, events []storage.Event) (ids []int, err error) {
+	ctx, span := s.start(ctx, "CreateEvents", attribute.Int("batch.size", len(events)))
+	defer endSpan(span, &err)
+	return s.next.CreateEvents(ctx, events)
+}
+
+func (s *tracedStore) DeleteEvents(ctx context.Context, ids []int) (err error) {
+	ctx, span := s.start(ctx, "DeleteEvents", attribute.Int("batch.size", len(ids)))
+	defer endSpan(span, &err)
+	return s.next.DeleteEvents(ctx, ids)
+}
+
+func (s *tracedStore) AddAttendee(ctx context.Context, attendee storage.Attendee) (err error) {
+	ctx, span := s.start(ctx, "AddAttendee",
+		attribute.Int("event.id", attendee.EventID), attribute.Int("attendee.user_id", attendee.UserID))
+	defer endSpan(span, &err)
+	return s.next.AddAttendee(ctx, attendee)
+}
+
+func (s *tracedStore) ListAttendees(ctx context.Context, eventID int) (attendees []storage.Attendee, err error) {
+	ctx, span := s.start(ctx, "ListAttendees", attribute.Int("event.id", eventID))
+	defer endSpan(span, &err)
+	return s.next.ListAttendees(ctx, eventID)
+}
+
+func (s *tracedStore) UpdateAttendee(ctx context.Context, attendee storage.Attendee) (err error) {
+	ctx, span := s.start(ctx, "UpdateAttendee",
+		attribute.Int("event.id", attendee.EventID), attribute.Int("attendee.user_id", attendee.UserID))
+	defer endSpan(span, &err)
+	return s.next.UpdateAttendee(ctx, attendee)
+}
+
+func (s *tracedStore) RemoveAttendee(ctx context.Context, eventID, userID int) (err error) {
+	ctx, span := s.start(ctx, "RemoveAttendee",
+		attribute.Int("event.id", eventID), attribute.Int("attendee.user_id", userID))
+	defer endSpan(span, &err)
+	return s.next.RemoveAttendee(ctx, eventID, userID)
+}
+
+func (s *tracedStore) AddHistory(ctx context.Context, entry storage.HistoryEntry) (err error) {
+	ctx, span := s.start(ctx, "AddHistory",
+		attribute.Int("event.id", entry.EventID), attribute.String("history.action", string(entry.Action)))
+	defer endSpan(span, &err)
+	return s.next.AddHistory(ctx, entry)
+}
+
+func (s *tracedStore) ListHistory(ctx context.Context, eventID int) (entries []storage.HistoryEntry, err error) {
+	ctx, span := s.start(ctx, "ListHistory", attribute.Int("event.id", eventID))
+	defer endSpan(span, &err)
+	return s.next.ListHistory(ctx, eventID)
+}
+
+func (s *tracedStore) RecreateEvent(ctx context.Context, event storage.Event) (err error) {
+	ctx, span := s.start(ctx, "RecreateEvent", attribute.Int("event.id", event.ID))
+	defer endSpan(span, &err)
+	return s.next.RecreateEvent(ctx, event)
+}
+
+func (s *tracedStore) ListDeletedEvents(ctx context.Context) (events []storage.Event, err error) {
+	ctx, span := s.start(ctx, "ListDeletedEvents")
+	defer endSpan(span, &err)
+	return s.next.ListDeletedEvents(ctx)
+}
+
+func (s *tracedStore) UndeleteEvent(ctx context.Context, id int) (err error) {
+	ctx, span := s.start(ctx, "UndeleteEvent", attribute.Int("event.id", id))
+	defer endSpan(span, &err)
+	return s.next.UndeleteEvent(ctx, id)
+}
+
+func (s *tracedStore) PurgeDeletedEvents(ctx context.Context, before time.Time) (n int, err error) {
+	ctx, span := s.start(ctx, "PurgeDeletedEvents", attribute.String("purge.before", before.Format(time.RFC3339)))
+	defer endSpan(span, &err)
+	return s.next.PurgeDeletedEvents(ctx, before)
+}
+
+func (s *tracedStore) CreateCategory(ctx context.Context, category storage.Category) (id int, err error) {
+	ctx, span := s.start(ctx, "CreateCategory", attribute.String("category.name", category.Name))
+	defer endSpan(span, &err)
+	return s.next.CreateCategory(ctx, category)
+}
+
+func (s *tracedStore) ListCategories(ctx context.Context) (categories []storage.Category, err error) {
+	ctx, span := s.start(ctx, "ListCategories")
+	defer endSpan(span, &err)
+	return s.next.ListCategories(ctx)
+}
+
+func (s *tracedStore) UpdateCategory(ctx context.Context, category storage.Category) (err error) {
+	ctx, span := s.start(ctx, "UpdateCategory", attribute.Int("category.id", category.ID))
+	defer endSpan(span, &err)
+	return s.next.UpdateCategory(ctx, category)
+}
+
+func (s *tracedStore) DeleteCategory(ctx context.Context, id int) (err error) {
+	ctx, span := s.start(ctx, "DeleteCategory", attribute.Int("category.id", id))
+	defer endSpan(span, &err)
+	return s.next.DeleteCategory(ctx, id)
+}
+
+func (s *tracedStore) SetEventTags(ctx context.Context, eventID int, tags []string) (err error) {
+	ctx, span := s.start(ctx, "SetEventTags",
+		attribute.Int("event.id", eventID), attribute.StringSlice("event.tags", tags))
+	defer endSpan(span, &err)
+	return s.next.SetEventTags(ctx, eventID, tags)
+}
+
+func (s *tracedStore) SearchEvents(
+	ctx context.Context,
+	query storage.SearchQuery,
+) (results []storage.SearchResult, err error) {
+	ctx, span := s.start(ctx, "SearchEvents",
+		attribute.Int("search.phrases", len(query.Phrases)), attribute.Int("search.limit", query.Limit))
+	defer endSpan(span, &err)
+	return s.next.SearchEvents(ctx, query)
+}
+
+// WithinTx opens a span around the transaction; its operations have spans of their own.
+func (s *tracedStore) WithinTx(ctx context.Context, fn func(tx storage.Store) error) (err error) {
+	ctx, span := s.start(ctx, "WithinTx")
+	defer endSpan(span, &err)
+	return withinTx(ctx, s.next, func(tx storageInterface) error {
+		return fn(&tracedStore{next: tx, backend: s.backend})
+	})
+}
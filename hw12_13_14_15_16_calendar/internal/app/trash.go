@@ -0,0 +1,25 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// ListDeletedEvents returns the events in the trash, most recently deleted first.
+func (a *App) ListDeletedEvents(ctx context.Context) ([]storage.Event, error) {
+	return a.store.ListDeletedEvents(ctx)
+}
+
+// PurgeDeletedEvents permanently removes the events deleted before the given time. Their
+// history is kept, so RestoreEvent can still recreate them.
+func (a *App) PurgeDeletedEvents(ctx context.Context, before time.Time) (int, error) {
+	n, err := a.store.PurgeDeletedEvents(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	a.log.Info(fmt.Sprintf("purged %d events deleted before %s", n, before.Format(time.RFC3339)))
+	return n, nil
+}
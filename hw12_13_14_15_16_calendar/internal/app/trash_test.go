@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+func TestTrash(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+
+	id, err := a.CreateEvent(ctx, storage.Event{Title: "Vaccination"})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if err := a.InviteAttendee(ctx, storage.Attendee{EventID: id, UserID: 3}); err != nil {
+		t.Fatalf("InviteAttendee: %v", err)
+	}
+	if err := a.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+
+	if _, err := a.GetEvent(ctx, id); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("expected a deleted event to be hidden, got %v", err)
+	}
+	if events, _ := a.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll}); len(events) != 0 {
+		t.Errorf("expected ListEvents to leave out deleted events, got %+v", events)
+	}
+	if err := a.DeleteEvent(ctx, id); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("expected a second delete to fail, got %v", err)
+	}
+	trash, err := a.ListDeletedEvents(ctx)
+	if err != nil || len(trash) != 1 || trash[0].DeletedAt == nil || len(trash[0].Attendees) != 1 {
+		t.Fatalf("ListDeletedEvents: %+v, %v", trash, err)
+	}
+
+	restored, err := a.RestoreEvent(ctx, id)
+	if err != nil || restored.DeletedAt != nil || len(restored.Attendees) != 1 {
+		t.Fatalf("RestoreEvent from the trash: %+v, %v", restored, err)
+	}
+	if trash, _ := a.ListDeletedEvents(ctx); len(trash) != 0 {
+		t.Errorf("expected an empty trash after restore, got %+v", trash)
+	}
+
+	// Purged events come back from their history.
+	if err := a.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	if n, err := a.PurgeDeletedEvents(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("expected nothing to purge yet, got %d, %v", n, err)
+	}
+	if n, err := a.PurgeDeletedEvents(ctx, time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Fatalf("PurgeDeletedEvents: %d, %v", n, err)
+	}
+	if trash, _ := a.ListDeletedEvents(ctx); len(trash) != 0 {
+		t.Errorf("expected an empty trash after purge, got %+v", trash)
+	}
+	restored, err = a.RestoreEvent(ctx, id)
+	if err != nil || restored.Title != "Vaccination" || len(restored.Attendees) != 1 {
+		t.Fatalf("RestoreEvent from the history: %+v, %v", restored, err)
+	}
+	if _, err := a.GetEvent(ctx, id); err != nil {
+		t.Errorf("GetEvent after restore: %v", err)
+	}
+}
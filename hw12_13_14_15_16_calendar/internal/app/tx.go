@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	filestorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/file"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+	postgresstorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/sql"
+)
+
+// Every storage backend runs transactions; withinTx relies on it.
+var (
+	_ storage.Transactor = (*memorystorage.Storage)(nil)
+	_ storage.Transactor = (*filestorage.Storage)(nil)
+	_ storage.Transactor = (*postgresstorage.Storage)(nil)
+	_ storage.Transactor = (*postgresstorage.PoolStorage)(nil)
+)
+
+// withinTx runs fn as one unit of work. The backends all implement storage.Transactor, as
+// checked above; a store that does not, such as a test double, is not transactional, and
+// fn runs on it directly, its operations taking effect one by one.
+func withinTx(ctx context.Context, store storageInterface, fn func(tx storageInterface) error) error {
+	if tr, ok := store.(storage.Transactor); ok {
+		return tr.WithinTx(ctx, fn)
+	}
+	return fn(store)
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+var errHistory = errors.New("history is full")
+
+// historyFailingStore is a memory storage that cannot record history, in transactions too.
+type historyFailingStore struct {
+	*memorystorage.Storage
+}
+
+func (s historyFailingStore) AddHistory(context.Context, storage.HistoryEntry) error {
+	return errHistory
+}
+
+func (s historyFailingStore) WithinTx(ctx context.Context, fn func(tx storage.Store) error) error {
+	return s.Storage.WithinTx(ctx, func(tx storage.Store) error {
+		return fn(historyFailingStore{tx.(*memorystorage.Storage)})
+	})
+}
+
+func TestWithinTx_HistoryFailureUndoesWrite(t *testing.T) {
+	backend := memorystorage.New()
+	ctx := context.Background()
+	id, err := backend.CreateEvent(ctx, storage.Event{Title: "Checkup"})
+	if err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+	store := historyFailingStore{backend}
+	a := &App{
+		log:   logger.New(""),
+		store: cacheStore(config.CacheConfig{Size: 10, TTL: time.Minute}, instrumentStore("memory", store)),
+	}
+
+	if _, err := a.CreateEvent(ctx, storage.Event{Title: "Surgery"}); !errors.Is(err, errHistory) {
+		t.Errorf("CreateEvent: got %v, want %v", err, errHistory)
+	}
+	if err := a.UpdateEvent(ctx, storage.Event{ID: id, Title: "Surgery"}); !errors.Is(err, errHistory) {
+		t.Errorf("UpdateEvent: got %v, want %v", err, errHistory)
+	}
+	if err := a.DeleteEvent(ctx, id); !errors.Is(err, errHistory) {
+		t.Errorf("DeleteEvent: got %v, want %v", err, errHistory)
+	}
+
+	events, err := a.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll})
+	if err != nil {
+		t.Fatalf("ListEvents returned error: %v", err)
+	}
+	if len(events) != 1 || events[0].Title != "Checkup" {
+		t.Errorf("writes without history took effect: got %+v", events)
+	}
+}
+
+func TestCachedStore_WithinTx(t *testing.T) {
+	backend := memorystorage.New()
+	store := cacheStore(config.CacheConfig{Size: 10, TTL: time.Minute}, backend)
+	ctx := context.Background()
+	id, err := store.CreateEvent(ctx, storage.Event{Title: "Checkup"})
+	if err != nil {
+		t.Fatalf("CreateEvent returned error: %v", err)
+	}
+	if _, err := store.GetEvent(ctx, id); err != nil {
+		t.Fatalf("GetEvent returned error: %v", err)
+	}
+
+	err = withinTx(ctx, store, func(tx storageInterface) error {
+		if err := tx.UpdateEvent(ctx, storage.Event{ID: id, Title: "Surgery"}); err != nil {
+			return err
+		}
+		// The transaction sees its own write, not the cached event.
+		event, err := tx.GetEvent(ctx, id)
+		if err != nil {
+			return err
+		}
+		if event.Title != "Surgery" {
+			t.Errorf("GetEvent in the transaction: got %q, want %q", event.Title, "Surgery")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withinTx returned error: %v", err)
+	}
+	if event, err := store.GetEvent(ctx, id); err != nil || event.Title != "Surgery" {
+		t.Errorf("GetEvent after the transaction: got %q, %v", event.Title, err)
+	}
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/validation"
+)
+
+var (
+	// ErrInvalidEvent wraps the validation.Errors of an event the App refuses to store.
+	ErrInvalidEvent   = errors.New("invalid event")
+	ErrEndBeforeStart = errors.New("end is before start")
+)
+
+// validateEvent checks an event against the validate tags of storage.Event, its time zone
+// and that it does not end before it starts. All violations are reported at once.
+func validateEvent(event storage.Event) error {
+	var errs validation.Errors
+	if err := validation.Struct(event); err != nil && !errors.As(err, &errs) {
+		return err
+	}
+	if _, err := storage.LoadLocation(event.TimeZone); err != nil {
+		errs = append(errs, validation.FieldError{Field: "TimeZone", Err: err})
+	}
+	if event.Start != nil && event.End != nil && event.End.Before(*event.Start) {
+		errs = append(errs, validation.FieldError{Field: "End", Err: ErrEndBeforeStart})
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidEvent, errs)
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/validation"
+)
+
+func TestCreateEvent_Validation(t *testing.T) {
+	a := &App{log: logger.New(""), store: memorystorage.New()}
+	ctx := context.Background()
+	start := time.Date(2024, 12, 24, 10, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	userID := -1
+
+	_, err := a.CreateEvent(ctx, storage.Event{
+		Title:    strings.Repeat("й", 41),
+		Start:    &start,
+		End:      &end,
+		TimeZone: "Mars/Olympus_Mons",
+		UserID:   &userID,
+	})
+	if !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("CreateEvent: got %v, want %v", err, ErrInvalidEvent)
+	}
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("CreateEvent: %v has no validation.Errors", err)
+	}
+	fields := make([]string, len(errs))
+	for i, fe := range errs {
+		fields[i] = fe.Field
+	}
+	if got, want := strings.Join(fields, ","), "Title,UserID,TimeZone,End"; got != want {
+		t.Errorf("violated fields: got %s, want %s", got, want)
+	}
+	for _, want := range []error{
+		validation.ErrTooLong, validation.ErrTooSmall, storage.ErrInvalidTimeZone, ErrEndBeforeStart,
+	} {
+		if !errors.Is(err, want) {
+			t.Errorf("CreateEvent: %v does not wrap %v", err, want)
+		}
+	}
+
+	// 40 characters fit the column even when they take more bytes.
+	title := strings.Repeat("й", 40)
+	if _, err := a.CreateEvent(ctx, storage.Event{Title: title, Start: &start, End: &start}); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if err := a.UpdateEvent(ctx, storage.Event{ID: 1, Start: &start, End: &end}); !errors.Is(err, ErrEndBeforeStart) {
+		t.Errorf("UpdateEvent: got %v, want %v", err, ErrEndBeforeStart)
+	}
+
+	results, err := a.BatchCreateEvents(ctx, []storage.Event{{Title: "Checkup"}, {UserID: &userID}})
+	if err != nil {
+		t.Fatalf("BatchCreateEvents: %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrBatchAborted) || !errors.Is(results[1].Err, validation.ErrTooSmall) {
+		t.Errorf("BatchCreateEvents: unexpected results %+v", results)
+	}
+}
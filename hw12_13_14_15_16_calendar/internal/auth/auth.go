@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// APIKeyHeader is the HTTP header carrying an API key.
+	APIKeyHeader = "X-API-Key"
+	// APIKeyMetadata is the gRPC metadata key carrying an API key.
+	APIKeyMetadata    = "x-api-key"
+	authorizationMeta = "authorization"
+	bearerPrefix      = "bearer "
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidAPIKey      = errors.New("invalid API key")
+	ErrInvalidToken       = errors.New("invalid token")
+)
+
+// Identity describes the authenticated caller.
+type Identity struct {
+	Subject string // key owner or token subject
+	UserID  *int   // calendar user the caller acts as, when known
+	Method  string // "api_key" or "jwt"
+}
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx that carries the identity.
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the identity stored in ctx.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(ctxKey{}).(Identity)
+	return id, ok
+}
+
+// Authenticator validates API keys and JWT bearer tokens.
+type Authenticator struct {
+	apiKeys map[string]Identity
+	secret  []byte
+	jwks    map[string]interface{} // kid -> *rsa.PublicKey
+	parser  *jwt.Parser
+	public  map[string]bool
+}
+
+// New builds an Authenticator from config, loading the JWKS file if one is set.
+func New(cfg config.AuthConf) (*Authenticator, error) {
+	a := &Authenticator{
+		apiKeys: make(map[string]Identity, len(cfg.APIKeys)),
+		secret:  []byte(cfg.JWT.HMACSecret),
+		public:  make(map[string]bool, len(cfg.PublicMethods)),
+	}
+
+	for _, k := range cfg.APIKeys {
+		if k.Key == "" {
+			return nil, fmt.Errorf("api key for %q is empty", k.Subject)
+		}
+		id := Identity{Subject: k.Subject, Method: "api_key"}
+		if k.UserID != 0 {
+			uid := k.UserID
+			id.UserID = &uid
+		}
+		a.apiKeys[k.Key] = id
+	}
+
+	if cfg.JWT.JWKSFile != "" {
+		keys, err := LoadJWKS(cfg.JWT.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = keys
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(a.validMethods()), jwt.WithExpirationRequired()}
+	if cfg.JWT.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWT.Issuer))
+	}
+	if cfg.JWT.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWT.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	for _, m := range cfg.PublicMethods {
+		a.public[m] = true
+	}
+	return a, nil
+}
+
+func (a *Authenticator) validMethods() []string {
+	var methods []string
+	if len(a.secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(a.jwks) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	return methods
+}
+
+// IsPublic reports whether fullMethod (e.g. "/calendarGRPC.CalendarService/HealthCheck")
+// may be called without credentials. Both full and short method names are accepted in config.
+func (a *Authenticator) IsPublic(fullMethod string) bool {
+	if a.public[fullMethod] {
+		return true
+	}
+	short := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	return a.public[short]
+}
+
+// Authenticate validates the credentials found in the incoming gRPC metadata.
+// An API key takes precedence over a bearer token.
+func (a *Authenticator) Authenticate(ctx context.Context) (Identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if keys := md.Get(APIKeyMetadata); len(keys) > 0 {
+		return a.checkAPIKey(keys[0])
+	}
+	return a.checkBearer(md.Get(authorizationMeta))
+}
+
+// AuthenticateHTTP validates the credentials of a plain HTTP request: an X-API-Key header,
+// HTTP Basic credentials whose password is an API key (the user name is ignored; this is
+// what calendar clients send) or a bearer token.
+func (a *Authenticator) AuthenticateHTTP(r *http.Request) (Identity, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return a.checkAPIKey(key)
+	}
+	if _, password, ok := r.BasicAuth(); ok {
+		return a.checkAPIKey(password)
+	}
+	return a.checkBearer(r.Header.Values("Authorization"))
+}
+
+// checkBearer validates the first bearer token among Authorization header values.
+func (a *Authenticator) checkBearer(values []string) (Identity, error) {
+	for _, v := range values {
+		if len(v) > len(bearerPrefix) && strings.EqualFold(v[:len(bearerPrefix)], bearerPrefix) {
+			return a.checkToken(strings.TrimSpace(v[len(bearerPrefix):]))
+		}
+	}
+	return Identity{}, ErrMissingCredentials
+}
+
+func (a *Authenticator) checkAPIKey(key string) (Identity, error) {
+	for k, id := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return id, nil
+		}
+	}
+	return Identity{}, ErrInvalidAPIKey
+}
+
+func (a *Authenticator) checkToken(raw string) (Identity, error) {
+	if len(a.validMethods()) == 0 {
+		return Identity{}, fmt.Errorf("%w: bearer tokens are not configured", ErrInvalidToken)
+	}
+
+	claims := &Claims{}
+	if _, err := a.parser.ParseWithClaims(raw, claims, a.keyFunc); err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	id := Identity{Subject: claims.Subject, Method: "jwt"}
+	switch {
+	case claims.UserID != nil:
+		id.UserID = claims.UserID
+	default:
+		if uid, err := strconv.Atoi(claims.Subject); err == nil {
+			id.UserID = &uid
+		}
+	}
+	return id, nil
+}
+
+func (a *Authenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return a.secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := a.jwks[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(a.jwks) == 1 {
+			for _, key := range a.jwks {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// Claims are the JWT claims understood by the calendar.
+type Claims struct {
+	jwt.RegisteredClaims
+	UserID *int `json:"uid,omitempty"`
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"google.golang.org/grpc/metadata"
+)
+
+func incoming(pairs ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func writeJWKS(t *testing.T, kid string, key *rsa.PublicKey) string {
+	t.Helper()
+	set := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	a, err := New(config.AuthConf{APIKeys: []config.APIKeyConf{{Key: "s3cret", Subject: "front-desk", UserID: 7}}})
+	require.NoError(t, err)
+
+	id, err := a.Authenticate(incoming(APIKeyMetadata, "s3cret"))
+	require.NoError(t, err)
+	require.Equal(t, "front-desk", id.Subject)
+	require.Equal(t, 7, *id.UserID)
+
+	_, err = a.Authenticate(incoming(APIKeyMetadata, "wrong"))
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, err = a.Authenticate(context.Background())
+	require.ErrorIs(t, err, ErrMissingCredentials)
+}
+
+func TestAuthenticateHTTP(t *testing.T) {
+	a, err := New(config.AuthConf{APIKeys: []config.APIKeyConf{{Key: "s3cret", Subject: "front-desk", UserID: 7}}})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/caldav/", nil)
+	r.SetBasicAuth("anyone", "s3cret")
+	id, err := a.AuthenticateHTTP(r)
+	require.NoError(t, err)
+	require.Equal(t, "front-desk", id.Subject)
+
+	r = httptest.NewRequest(http.MethodGet, "/caldav/", nil)
+	r.Header.Set(APIKeyHeader, "wrong")
+	_, err = a.AuthenticateHTTP(r)
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, err = a.AuthenticateHTTP(httptest.NewRequest(http.MethodGet, "/caldav/", nil))
+	require.ErrorIs(t, err, ErrMissingCredentials)
+}
+
+func TestAuthenticate_HS256(t *testing.T) {
+	a, err := New(config.AuthConf{JWT: config.JWTConf{HMACSecret: "hmac-secret", Issuer: "clinic"}})
+	require.NoError(t, err)
+
+	sign := func(claims jwt.Claims, secret string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+	valid := jwt.RegisteredClaims{
+		Subject:   "42",
+		Issuer:    "clinic",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	id, err := a.Authenticate(incoming("authorization", "Bearer "+sign(valid, "hmac-secret")))
+	require.NoError(t, err)
+	require.Equal(t, "jwt", id.Method)
+	require.Equal(t, 42, *id.UserID)
+
+	_, err = a.Authenticate(incoming("authorization", "Bearer "+sign(valid, "other-secret")))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	_, err = a.Authenticate(incoming("authorization", "Bearer "+sign(expired, "hmac-secret")))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	foreign := valid
+	foreign.Issuer = "someone-else"
+	_, err = a.Authenticate(incoming("authorization", "Bearer "+sign(foreign, "hmac-secret")))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestAuthenticate_RS256WithJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	a, err := New(config.AuthConf{JWT: config.JWTConf{JWKSFile: writeJWKS(t, "key-1", &key.PublicKey)}})
+	require.NoError(t, err)
+
+	uid := 5
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "dr.house",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		UserID: &uid,
+	})
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	id, err := a.Authenticate(incoming("authorization", "Bearer "+signed))
+	require.NoError(t, err)
+	require.Equal(t, "dr.house", id.Subject)
+	require.Equal(t, 5, *id.UserID)
+
+	// HS256 tokens are refused when no HMAC secret is configured, even if signed with the public key bytes.
+	hs, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "attacker",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(key.PublicKey.N.Bytes())
+	require.NoError(t, err)
+	_, err = a.Authenticate(incoming("authorization", "Bearer "+hs))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestIsPublic(t *testing.T) {
+	a, err := New(config.AuthConf{PublicMethods: []string{"HealthCheck"}})
+	require.NoError(t, err)
+
+	require.True(t, a.IsPublic("/calendarGRPC.CalendarService/HealthCheck"))
+	require.False(t, a.IsPublic("/calendarGRPC.CalendarService/CreateEvent"))
+}
@@ -1,30 +1,140 @@
 package config
 
+import "time"
+
 type Config struct {
 	Logger         LoggerConf    `yaml:"logger"`
 	HTTP           HTTPConf      `yaml:"http"`
 	Storage        StorageConfig `yaml:"storage"`
-	MigrationsPath string        `yaml:"migrationsPath"`
+	MigrationsPath string        `yaml:"migrationsPath" default:"./migrations"`
 	GRPC           GRPCConfig    `yaml:"grpc"`
+	Metrics        MetricsConf   `yaml:"metrics"`
+	Tracing        TracingConf   `yaml:"tracing"`
+	Auth           AuthConf      `yaml:"auth"`
 }
 
 type LoggerConf struct {
-	Level string `yaml:"level"`
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"info" validate:"in:info,error"`
 }
 
 type HTTPConf struct {
-	Listen string `yaml:"listen"`
+	Listen     string  `yaml:"listen" env:"HTTP_LISTEN" default:":8081"`
+	TLS        TLSConf `yaml:"tls"`
+	GatewayTLS TLSConf `yaml:"gatewayTls"` // client side of the gateway's connection to gRPC
+}
+
+// TLSConf holds certificate paths. On a listener CAFile enables mTLS; on a client it
+// is used to verify the server. Files are reloaded when they change on disk.
+type TLSConf struct {
+	CertFile   string `yaml:"certFile"`
+	KeyFile    string `yaml:"keyFile"`
+	CAFile     string `yaml:"caFile"`
+	ServerName string `yaml:"serverName"` // client only: expected server name
 }
 
 type StorageConfig struct {
-	Type     string         `yaml:"type"`
+	Type     string         `yaml:"type" env:"STORAGE_TYPE" validate:"required|in:memory,postgres,file"`
 	Postgres PostgresConfig `yaml:"postgres"`
+	File     FileConfig     `yaml:"file"`
+	Cache    CacheConfig    `yaml:"cache"`
 }
 
+// CacheConfig configures the read-through cache of events and event lists in front of the
+// storage; Size 0 disables it. Entries are dropped on writes through this process and after
+// TTL, which bounds how long changes made by other processes stay unseen (0 keeps them).
+// With Postgres replicas, a read may fill the cache from a replica that has not replayed
+// the latest writes yet, and the entry is then served to every client. TTL is therefore
+// capped at MaxReplicaLag, trading hit rate for entries at most about twice the allowed
+// replica lag old; a MaxReplicaLag of 0 disables the cache.
+type CacheConfig struct {
+	Size int           `yaml:"size" validate:"min:0"` // entries per cache
+	TTL  time.Duration `yaml:"ttl" default:"30s" validate:"min:0s"`
+}
+
+// FileConfig configures the embedded storage that keeps its data in a directory.
+type FileConfig struct {
+	Dir          string `yaml:"dir" env:"STORAGE_FILE_DIR" default:"./data"`
+	CompactEvery int    `yaml:"compactEvery" default:"1000" validate:"min:0"` // logged writes between snapshots
+}
+
+// PostgresConfig configures the Postgres storage. Writes go to the primary at DSN; event
+// reads made for API clients go to a replica that lags the primary by at most MaxReplicaLag
+// and has replayed the client's last write, or to the primary when none qualifies. Driver
+// selects the pgx connection pool with prepared statements ("pgxpool") or database/sql
+// ("stdlib").
 type PostgresConfig struct {
-	DSN string `yaml:"dsn"`
+	DSN                  string        `yaml:"dsn" env:"POSTGRES_DSN"`
+	Driver               string        `yaml:"driver" env:"POSTGRES_DRIVER" default:"pgxpool" validate:"in:pgxpool,stdlib"`
+	Replicas             []string      `yaml:"replicas"` // DSNs of streaming replicas of the primary
+	MaxReplicaLag        time.Duration `yaml:"maxReplicaLag" default:"1s" validate:"min:0s"`
+	ReplicaCheckInterval time.Duration `yaml:"replicaCheckInterval" default:"1s" validate:"min:10ms"`
 }
 
 type GRPCConfig struct {
-	ListenGrpc string `yaml:"listenGrpc"`
+	ListenGrpc     string          `yaml:"listenGrpc" env:"GRPC_LISTEN" default:":50051"`
+	TLS            TLSConf         `yaml:"tls"`
+	DefaultTimeout time.Duration   `yaml:"defaultTimeout" validate:"min:0s"` // applied when the client sets no deadline
+	MaxRecvMsgSize int             `yaml:"maxRecvMsgSize" validate:"min:0"`  // bytes, 0 keeps the gRPC default (4MB)
+	MaxSendMsgSize int             `yaml:"maxSendMsgSize" validate:"min:0"`  // bytes, 0 keeps the gRPC default
+	RateLimit      RateLimitConfig `yaml:"rateLimit"`
+}
+
+// RateLimitConfig configures the per-client token bucket; RPS <= 0 disables limiting.
+type RateLimitConfig struct {
+	RPS   float64 `yaml:"rps" validate:"min:0"`
+	Burst int     `yaml:"burst" validate:"min:0"`
+	// TrustedProxies are IPs or CIDRs of proxies whose x-forwarded-for names the client;
+	// the HTTP gateway, which dials over loopback, is always trusted.
+	TrustedProxies []string `yaml:"trustedProxies"`
+}
+
+// MetricsConf configures the Prometheus endpoint of binaries without an HTTP server.
+type MetricsConf struct {
+	Listen string `yaml:"listen" env:"METRICS_LISTEN"`
+}
+
+// TracingConf selects where OpenTelemetry spans are exported.
+type TracingConf struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" validate:"in:none,stdout,otlp"` // "none" (default), "stdout" or "otlp"
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`                                // OTLP/HTTP collector URL, e.g. http://localhost:4318
+	SampleRatio float64 `yaml:"sampleRatio" validate:"min:0|max:1"`
+}
+
+// AuthConf configures authentication of CalendarService calls.
+type AuthConf struct {
+	Enabled       bool         `yaml:"enabled" env:"AUTH_ENABLED"`
+	APIKeys       []APIKeyConf `yaml:"apiKeys"`
+	JWT           JWTConf      `yaml:"jwt"`
+	PublicMethods []string     `yaml:"publicMethods"` // e.g. "HealthCheck"; callable without credentials
+}
+
+// APIKeyConf maps a static API key to the identity it authenticates.
+type APIKeyConf struct {
+	Key     string `yaml:"key"`
+	Subject string `yaml:"subject"`
+	UserID  int    `yaml:"userId"`
+}
+
+// JWTConf configures bearer token validation. HS256 tokens are checked against
+// HMACSecret, RS256 tokens against the keys of the JWKS file.
+type JWTConf struct {
+	HMACSecret string `yaml:"hmacSecret" env:"AUTH_JWT_HMAC_SECRET"`
+	JWKSFile   string `yaml:"jwksFile"`
+	Issuer     string `yaml:"issuer"`
+	Audience   string `yaml:"audience"`
+}
+
+// RabbitConf holds the RabbitMQ connection and topology settings of the producer and consumer.
+type RabbitConf struct {
+	User         string `yaml:"user" env:"RABBIT_USER" default:"guest"`
+	Password     string `yaml:"password" env:"RABBIT_PASS" default:"guest"`
+	Host         string `yaml:"host" env:"RABBIT_HOST" validate:"required"`
+	Port         string `yaml:"port" env:"RABBIT_PORT" default:"5672"`
+	Exchange     string `yaml:"exchange" env:"RABBIT_EXCHANGE"`
+	ExchangeType string `yaml:"exchangeType" env:"RABBIT_EXCHANGE_TYPE"`
+	Queue        string `yaml:"queue" env:"RABBIT_QUEUE" validate:"required"`
+	Key          string `yaml:"key" env:"RABBIT_KEY"`
+	ConsumerTag  string `yaml:"consumerTag" env:"RABBIT_TAG"`
+	Lifetime     int    `yaml:"lifetime" env:"RABBIT_LIFETIME" validate:"min:0"` // in seconds (only relevant for consumers)
+	Sync         bool   `yaml:"sync" env:"RABBIT_SYNC"`                          // for synchronous confirm publishing
 }
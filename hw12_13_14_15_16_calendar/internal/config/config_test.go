@@ -1,8 +1,106 @@
 package config_test
 
-import "testing"
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+)
 
 func TestEmpty(t *testing.T) {
 	_ = t
 	// This is an empty test, no assertions or logic yet
 }
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoader_Precedence(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	loader := config.NewLoader[config.Config](fs)
+	require.NoError(t, fs.Parse([]string{"-grpc.rateLimit.rps=7", "-auth.enabled"}))
+
+	path := writeFile(t, `
+logger:
+  level: error
+storage:
+  type: memory
+grpc:
+  listenGrpc: ":6000"
+  defaultTimeout: 3s
+  rateLimit:
+    rps: 1
+`)
+	t.Setenv("GRPC_LISTEN", ":7000")
+
+	cfg, err := loader.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, ":8081", cfg.HTTP.Listen, "default")
+	require.Equal(t, "error", cfg.Logger.Level, "file over default")
+	require.Equal(t, 3*time.Second, cfg.GRPC.DefaultTimeout, "file")
+	require.Equal(t, ":7000", cfg.GRPC.ListenGrpc, "env over file")
+	require.Equal(t, 7.0, cfg.GRPC.RateLimit.RPS, "flag over file")
+	require.True(t, cfg.Auth.Enabled, "bool flag without value")
+}
+
+func TestLoader_ReportsAllErrors(t *testing.T) {
+	path := writeFile(t, `
+logger:
+  level: verbose
+grpc:
+  rateLimit:
+    rps: -1
+`)
+	t.Setenv("RABBIT_LIFETIME", "forever")
+
+	_, err := config.NewLoader[struct {
+		config.Config `yaml:",inline"`
+		Rabbit        config.RabbitConf `yaml:"rabbit"`
+	}](nil).Load(path)
+	require.Error(t, err)
+
+	require.ErrorIs(t, err, config.ErrRequired)
+	require.ErrorIs(t, err, config.ErrNotAllowed)
+	require.ErrorIs(t, err, config.ErrTooSmall)
+	for _, field := range []string{
+		"storage.type", "logger.level", "grpc.rateLimit.rps", "rabbit.lifetime", "rabbit.host", "rabbit.queue",
+	} {
+		require.Contains(t, err.Error(), field+":")
+	}
+}
+
+func TestLoader_FileErrors(t *testing.T) {
+	loader := config.NewLoader[config.Config](nil)
+
+	_, err := loader.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = loader.Load(writeFile(t, "storage:\n  type: memory\n  typo: true\n"))
+	require.ErrorContains(t, err, "typo")
+}
+
+func TestLoader_EnvOnly(t *testing.T) {
+	t.Setenv("STORAGE_TYPE", "postgres")
+	t.Setenv("POSTGRES_DSN", "host=db")
+
+	cfg, err := config.NewLoader[config.Config](nil).Load("")
+	require.NoError(t, err)
+	require.Equal(t, "postgres", cfg.Storage.Type)
+	require.Equal(t, "host=db", cfg.Storage.Postgres.DSN)
+	require.Equal(t, "info", cfg.Logger.Level)
+}
+
+func TestLoader_ShippedConfig(t *testing.T) {
+	cfg, err := config.NewLoader[config.Config](nil).Load("../../configs/config.yaml")
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, cfg.GRPC.DefaultTimeout)
+	require.Equal(t, []string{"HealthCheck"}, cfg.Auth.PublicMethods)
+}
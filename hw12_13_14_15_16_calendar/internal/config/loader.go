@@ -0,0 +1,268 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// The errors of the validate tags; see validation.Field.
+var (
+	ErrRequired   = validation.ErrRequired
+	ErrNotAllowed = validation.ErrNotAllowed
+	ErrTooSmall   = validation.ErrTooSmall
+	ErrTooLarge   = validation.ErrTooLarge
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// FieldError describes a configuration field that could not be set or failed validation.
+// Field is the YAML path of the field, e.g. "grpc.rateLimit.rps".
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Loader builds a configuration of type T by layering, from lowest to highest priority:
+//
+//   - `default:"..."` struct tags;
+//   - the YAML file (unknown keys are an error);
+//   - environment variables named by `env:"..."` tags (empty values are ignored);
+//   - command-line flags, one per scalar field, named after its YAML path (-grpc.rateLimit.rps).
+//
+// The result is checked against `validate:"..."` tags and every problem found is
+// returned at once, joined with errors.Join.
+type Loader[T any] struct {
+	flags map[string]*flagValue
+}
+
+// NewLoader creates a Loader and registers the per-field flags on fs, which must
+// happen before fs is parsed. A nil fs disables flag overrides.
+func NewLoader[T any](fs *flag.FlagSet) *Loader[T] {
+	l := &Loader[T]{flags: make(map[string]*flagValue)}
+	if fs == nil {
+		return l
+	}
+
+	var zero T
+	walk(reflect.ValueOf(&zero).Elem(), "", func(f field) {
+		if !isScalar(f.value.Type()) {
+			return
+		}
+		v := &flagValue{isBool: f.value.Kind() == reflect.Bool}
+		usage := "overrides " + f.path
+		if env := f.tag.Get("env"); env != "" {
+			usage += " (env " + env + ")"
+		}
+		fs.Var(v, f.path, usage)
+		l.flags[f.path] = v
+	})
+	return l
+}
+
+// Load reads the configuration from path; an empty path skips the file.
+// It can be called again (e.g. on SIGHUP) to pick up changes to the file and environment.
+func (l *Loader[T]) Load(path string) (T, error) {
+	var cfg T
+	var errs []error
+	root := reflect.ValueOf(&cfg).Elem()
+
+	walk(root, "", func(f field) {
+		if def, ok := f.tag.Lookup("default"); ok {
+			if err := setValue(f.value, def); err != nil {
+				errs = append(errs, FieldError{Field: f.path, Err: fmt.Errorf("bad default: %w", err)})
+			}
+		}
+	})
+
+	if path != "" {
+		if err := decodeFile(path, &cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	walk(root, "", func(f field) {
+		if env := f.tag.Get("env"); env != "" {
+			if v, ok := os.LookupEnv(env); ok && v != "" {
+				if err := setValue(f.value, v); err != nil {
+					errs = append(errs, FieldError{Field: f.path, Err: fmt.Errorf("env %s: %w", env, err)})
+				}
+			}
+		}
+		if fv, ok := l.flags[f.path]; ok && fv.set {
+			if err := setValue(f.value, fv.value); err != nil {
+				errs = append(errs, FieldError{Field: f.path, Err: fmt.Errorf("flag -%s: %w", f.path, err)})
+			}
+		}
+	})
+
+	walk(root, "", func(f field) {
+		if rules := f.tag.Get("validate"); rules != "" {
+			for _, err := range validation.Field(f.value, rules) {
+				errs = append(errs, FieldError{Field: f.path, Err: err})
+			}
+		}
+	})
+
+	return cfg, errors.Join(errs...)
+}
+
+func decodeFile(path string, out interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+type field struct {
+	path  string
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+// walk calls visit for every non-struct field of v, descending into nested structs.
+// Paths follow the yaml tags so that flags and errors use the names seen in the file.
+func walk(v reflect.Value, prefix string, visit func(field)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		path := prefix
+		if opts != "inline" {
+			if prefix != "" {
+				path += "."
+			}
+			path += name
+		}
+
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+			walk(fv, path, visit)
+			continue
+		}
+		visit(field{path: path, value: fv, tag: sf.Tag})
+	}
+}
+
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// setValue parses s into v. Durations use time.ParseDuration and string slices are comma separated.
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// flagValue records a command-line value until Load knows the field type to parse it into.
+type flagValue struct {
+	value  string
+	set    bool
+	isBool bool
+}
+
+func (f *flagValue) String() string {
+	return f.value
+}
+
+func (f *flagValue) Set(s string) error {
+	f.value, f.set = s, true
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
@@ -0,0 +1,285 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+var (
+	ErrNotCalendar    = errors.New("not an iCalendar document")
+	ErrMissingStart   = errors.New("DTSTART is required")
+	ErrEndBeforeStart = errors.New("DTEND is before DTSTART")
+)
+
+// VEvent is a VEVENT read from an iCalendar document.
+// Index counts VEVENTs from zero in document order.
+type VEvent struct {
+	Index int
+	UID   string
+	Event storage.Event
+}
+
+// EventError reports a VEVENT that could not be converted into an event.
+type EventError struct {
+	Index int
+	UID   string
+	Err   error
+}
+
+func (e *EventError) Error() string {
+	if e.UID != "" {
+		return fmt.Sprintf("VEVENT #%d (UID %s): %v", e.Index, e.UID, e.Err)
+	}
+	return fmt.Sprintf("VEVENT #%d: %v", e.Index, e.Err)
+}
+
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+// Decode reads the VEVENTs of an iCalendar document. Invalid VEVENTs do not stop
+// decoding: they are reported as *EventError in errs, in document order.
+// err is set only when the document itself cannot be read.
+func Decode(r io.Reader) (events []VEvent, errs []error, err error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		inCalendar bool
+		seen       bool
+		calZone    string // X-WR-TIMEZONE, used for floating times
+		current    []contentLine
+		inEvent    bool
+		depth      int // nesting of components inside the VEVENT (VALARM, ...)
+		index      int
+	)
+	for _, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		cl, perr := parseLine(raw)
+		if perr != nil {
+			if inEvent {
+				current = append(current, contentLine{name: "X-INVALID", value: perr.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("%w: %w", ErrNotCalendar, perr)
+		}
+
+		switch {
+		case cl.name == "BEGIN" && strings.EqualFold(cl.value, "VCALENDAR") && !inCalendar:
+			inCalendar, seen = true, true
+		case !inCalendar:
+			return nil, nil, fmt.Errorf("%w: unexpected %s outside VCALENDAR", ErrNotCalendar, cl.name)
+		case cl.name == "END" && strings.EqualFold(cl.value, "VCALENDAR") && !inEvent:
+			inCalendar = false
+		case cl.name == "BEGIN" && strings.EqualFold(cl.value, "VEVENT") && !inEvent:
+			inEvent, current, depth = true, nil, 0
+		case inEvent && cl.name == "BEGIN":
+			depth++
+		case inEvent && cl.name == "END" && depth > 0:
+			depth--
+		case inEvent && cl.name == "END" && strings.EqualFold(cl.value, "VEVENT"):
+			ev, uid, eerr := buildEvent(current, calZone)
+			if eerr != nil {
+				errs = append(errs, &EventError{Index: index, UID: uid, Err: eerr})
+			} else {
+				events = append(events, VEvent{Index: index, UID: uid, Event: ev})
+			}
+			inEvent = false
+			index++
+		case inEvent && depth == 0:
+			current = append(current, cl)
+		case !inEvent && cl.name == "X-WR-TIMEZONE":
+			calZone = cl.value
+		}
+	}
+	if !seen {
+		return nil, nil, ErrNotCalendar
+	}
+	if inCalendar || inEvent {
+		return nil, nil, fmt.Errorf("%w: unterminated component", ErrNotCalendar)
+	}
+	return events, errs, nil
+}
+
+func buildEvent(lines []contentLine, calZone string) (storage.Event, string, error) {
+	var (
+		ev         storage.Event
+		uid        string
+		start, end *dateValue
+		duration   string
+		errs       []error
+	)
+	for _, cl := range lines {
+		var err error
+		switch cl.name {
+		case "X-INVALID":
+			err = errors.New(cl.value)
+		case "UID":
+			uid = cl.value
+		case "SUMMARY":
+			ev.Title = unescapeText(cl.value)
+		case "DESCRIPTION":
+			ev.Description = unescapeText(cl.value)
+		case "LOCATION":
+			ev.Clinic = stringPtr(unescapeText(cl.value))
+		case serviceProperty:
+			ev.Service = stringPtr(unescapeText(cl.value))
+		case "DTSTART":
+			start, err = parseDateValue(cl, calZone)
+		case "DTEND":
+			end, err = parseDateValue(cl, calZone)
+		case "DURATION":
+			duration = cl.value
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cl.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return ev, uid, errors.Join(errs...)
+	}
+	if start == nil {
+		return ev, uid, ErrMissingStart
+	}
+
+	ev.Start = &start.t
+	ev.AllDay = start.allDay
+	ev.TimeZone = start.zone
+	switch {
+	case end != nil:
+		if end.t.Before(start.t) {
+			return ev, uid, ErrEndBeforeStart
+		}
+		ev.End = &end.t
+	case duration != "":
+		d, err := parseDuration(duration)
+		if err != nil {
+			return ev, uid, fmt.Errorf("DURATION: %w", err)
+		}
+		if d < 0 {
+			return ev, uid, ErrEndBeforeStart
+		}
+		t := start.t.Add(d)
+		if start.allDay && d%(24*time.Hour) == 0 {
+			t = start.t.AddDate(0, 0, int(d/(24*time.Hour))) // nominal days across DST changes
+		}
+		ev.End = &t
+	}
+	return ev, uid, nil
+}
+
+type dateValue struct {
+	t      time.Time
+	allDay bool
+	zone   string
+}
+
+// parseDateValue reads a DATE or DATE-TIME property. UTC times ("Z") and times with a
+// TZID are absolute; floating times are taken in the calendar's X-WR-TIMEZONE or UTC.
+func parseDateValue(cl contentLine, calZone string) (*dateValue, error) {
+	zone := calZone
+	if tzid, ok := cl.params["TZID"]; ok {
+		zone = strings.TrimPrefix(tzid, "/")
+	}
+	value := cl.value
+	if strings.HasSuffix(value, "Z") {
+		zone, value = "UTC", strings.TrimSuffix(value, "Z")
+	}
+	loc, err := storage.LoadLocation(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	allDay := strings.EqualFold(cl.params["VALUE"], "DATE") || len(value) == len(dateFormat)
+	layout := dateTimeFormat
+	if allDay {
+		layout = dateFormat
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q", cl.value)
+	}
+	return &dateValue{t: t, allDay: allDay, zone: loc.String()}, nil
+}
+
+type contentLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseLine splits a content line into name, parameters and value (RFC 5545, 3.1).
+// Parameter values may be quoted; only the first value of a multi-valued parameter is kept.
+func parseLine(s string) (contentLine, error) {
+	cl := contentLine{params: map[string]string{}}
+	i := strings.IndexAny(s, ";:")
+	if i <= 0 {
+		return cl, fmt.Errorf("malformed content line %q", s)
+	}
+	cl.name = strings.ToUpper(s[:i])
+
+	for s[i] == ';' {
+		s = s[i+1:]
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return cl, fmt.Errorf("malformed parameter in %s", cl.name)
+		}
+		pname := strings.ToUpper(s[:eq])
+		s = s[eq+1:]
+
+		var pvalue string
+		if strings.HasPrefix(s, `"`) {
+			q := strings.IndexByte(s[1:], '"')
+			if q < 0 {
+				return cl, fmt.Errorf("unterminated quote in %s", cl.name)
+			}
+			pvalue, s = s[1:q+1], s[q+2:]
+			i = 0
+		} else {
+			i = strings.IndexAny(s, ";:")
+			if i < 0 {
+				return cl, fmt.Errorf("malformed content line %s", cl.name)
+			}
+			pvalue, _, _ = strings.Cut(s[:i], ",")
+		}
+		if i >= len(s) || (s[i] != ';' && s[i] != ':') {
+			return cl, fmt.Errorf("malformed parameter in %s", cl.name)
+		}
+		cl.params[pname] = pvalue
+	}
+	cl.value = s[i+1:]
+	return cl, nil
+}
+
+// unfold joins folded lines: a line starting with a space or tab continues the previous one.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,101 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// serviceProperty carries storage.Event.Service, which has no standard iCalendar property.
+const serviceProperty = "X-CALENDAR-SERVICE"
+
+// Encode writes the events as a single VCALENDAR.
+func Encode(w io.Writer, events []storage.Event) error {
+	lw := &lineWriter{w: bufio.NewWriter(w)}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + prodID)
+	lw.line("CALSCALE:GREGORIAN")
+	for _, ev := range events {
+		encodeEvent(lw, ev)
+	}
+	lw.line("END:VCALENDAR")
+	if lw.err != nil {
+		return lw.err
+	}
+	return lw.w.Flush()
+}
+
+func encodeEvent(lw *lineWriter, ev storage.Event) {
+	lw.line("BEGIN:VEVENT")
+	uid := ev.UID
+	if uid == "" {
+		uid = UID(ev.ID)
+	}
+	lw.line("UID:" + uid)
+	lw.line("DTSTAMP:" + now().UTC().Format(dateTimeFormat) + "Z")
+	if ev.Start != nil {
+		lw.line(timeProperty("DTSTART", *ev.Start, ev))
+	}
+	if ev.End != nil {
+		lw.line(timeProperty("DTEND", *ev.End, ev))
+	}
+	lw.line("SUMMARY:" + escapeText(ev.Title))
+	if ev.Description != "" {
+		lw.line("DESCRIPTION:" + escapeText(ev.Description))
+	}
+	if ev.Clinic != nil && *ev.Clinic != "" {
+		lw.line("LOCATION:" + escapeText(*ev.Clinic))
+	}
+	if ev.Service != nil && *ev.Service != "" {
+		lw.line(serviceProperty + ":" + escapeText(*ev.Service))
+	}
+	lw.line("END:VEVENT")
+}
+
+// timeProperty renders DTSTART/DTEND: a DATE for all-day events, a UTC DATE-TIME for
+// UTC events and a local DATE-TIME with TZID otherwise.
+func timeProperty(name string, t time.Time, ev storage.Event) string {
+	loc := ev.Location()
+	switch {
+	case ev.AllDay:
+		return name + ";VALUE=DATE:" + t.In(loc).Format(dateFormat)
+	case loc == time.UTC:
+		return name + ":" + t.UTC().Format(dateTimeFormat) + "Z"
+	default:
+		return name + ";TZID=" + loc.String() + ":" + t.In(loc).Format(dateTimeFormat)
+	}
+}
+
+// lineWriter writes CRLF-terminated content lines folded at 75 octets.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		lw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // the leading space of a continuation line counts
+	}
+	lw.write(s + "\r\n")
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err == nil {
+		_, lw.err = lw.w.WriteString(s)
+	}
+}
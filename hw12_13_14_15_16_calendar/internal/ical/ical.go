@@ -0,0 +1,126 @@
+// Package ical reads and writes calendar events as iCalendar (RFC 5545) data.
+//
+// Only VEVENTs are handled. Times are written with a TZID parameter naming the event's
+// IANA zone (no VTIMEZONE components are emitted) and all-day events use DATE values.
+// When reading, VTIMEZONE definitions and recurrence rules are ignored: TZID must name
+// an IANA zone, and a recurring event is imported as its first occurrence.
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ContentType is the media type of iCalendar documents.
+	ContentType = "text/calendar; charset=utf-8"
+
+	prodID         = "-//whatafunc//Otus Calendar//EN"
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+	uidSuffix      = "@calendar"
+	maxLineOctets  = 75
+)
+
+// now is replaced in tests to get a stable DTSTAMP.
+var now = time.Now
+
+// UID returns the iCalendar UID of a stored event.
+func UID(id int) string {
+	return strconv.Itoa(id) + uidSuffix
+}
+
+// EventID returns the event ID encoded in a UID produced by UID.
+func EventID(uid string) (int, bool) {
+	s, ok := strings.CutSuffix(uid, uidSuffix)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(s)
+	return id, err == nil && id > 0
+}
+
+// escapeText escapes a TEXT value (RFC 5545, 3.3.11).
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// unescapeText reverses escapeText.
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// parseDuration parses a DURATION value such as "PT1H30M", "P1D" or "-P1W".
+func parseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	var num int
+	inTime, digits := false, false
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			num, digits = num*10+int(r-'0'), true
+			continue
+		}
+		if r == 'T' && !digits && !inTime {
+			inTime = true
+			continue
+		}
+
+		var unit time.Duration
+		switch {
+		case !inTime && r == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && r == 'D':
+			unit = 24 * time.Hour
+		case inTime && r == 'H':
+			unit = time.Hour
+		case inTime && r == 'M':
+			unit = time.Minute
+		case inTime && r == 'S':
+			unit = time.Second
+		}
+		if unit == 0 || !digits {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		d += time.Duration(num) * unit
+		num, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	return sign * d, nil
+}
@@ -0,0 +1,204 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+func init() {
+	now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestRoundTrip(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	events := []storage.Event{
+		{
+			ID:          1,
+			Title:       "Check-up; bring card, please",
+			Description: "Line one\nLine two with a backslash \\ and a very long tail that has to be folded ✓✓✓✓✓",
+			Start:       ptr(time.Date(2024, 3, 31, 9, 30, 0, 0, berlin)),
+			End:         ptr(time.Date(2024, 3, 31, 10, 0, 0, 0, berlin)),
+			TimeZone:    "Europe/Berlin",
+			Clinic:      ptr("Main street 1"),
+			Service:     ptr("dental"),
+		},
+		{
+			ID:       2,
+			Title:    "Clinic closed",
+			Start:    ptr(time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)),
+			End:      ptr(time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)),
+			AllDay:   true,
+			TimeZone: "UTC",
+		},
+		{
+			ID:       3,
+			Title:    "Call",
+			Start:    ptr(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)),
+			TimeZone: "UTC",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, events))
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineOctets, line)
+	}
+	require.Contains(t, buf.String(), "DTSTART;TZID=Europe/Berlin:20240331T093000\r\n")
+	require.Contains(t, buf.String(), "DTSTART;VALUE=DATE:20241224\r\n")
+	require.Contains(t, buf.String(), "DTSTART:20240501T120000Z\r\n")
+
+	decoded, errs, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Empty(t, errs)
+	require.Len(t, decoded, len(events))
+	for i, ve := range decoded {
+		want := events[i]
+		id, ok := EventID(ve.UID)
+		require.True(t, ok)
+		require.Equal(t, want.ID, id)
+
+		got := ve.Event
+		require.Equal(t, want.Title, got.Title)
+		require.Equal(t, want.Description, got.Description)
+		require.Equal(t, want.Clinic, got.Clinic)
+		require.Equal(t, want.Service, got.Service)
+		require.Equal(t, want.AllDay, got.AllDay)
+		require.Equal(t, want.TimeZone, got.TimeZone)
+		require.True(t, want.Start.Equal(*got.Start))
+		if want.End == nil {
+			require.Nil(t, got.End)
+		} else {
+			require.True(t, want.End.Equal(*got.End))
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	doc := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"X-WR-TIMEZONE:Asia/Tokyo",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:abc@example.com",
+		`DTSTART;TZID="Europe/Berlin":20240310T090000`,
+		"DURATION:PT1H30M",
+		"SUMMARY:Fol",
+		" ded",
+		"LOCATION:Clinic\\, 2nd floor",
+		"BEGIN:VALARM",
+		"SUMMARY:ignored",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:floating",
+		"DTSTART:20240310T090000",
+		"SUMMARY:Floating",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:no-start",
+		"SUMMARY:Broken",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=Mars/Olympus_Mons:20240310T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:backwards",
+		"DTSTART:20240310T090000Z",
+		"DTEND:20240310T080000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:all-day",
+		"DTSTART;VALUE=DATE:20240310",
+		"DURATION:P2D",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	events, errs, err := Decode(strings.NewReader(doc))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	first := events[0].Event
+	require.Equal(t, "abc@example.com", events[0].UID)
+	require.Equal(t, 5, events[2].Index)
+	require.Equal(t, "Folded", first.Title)
+	require.Equal(t, "Clinic, 2nd floor", *first.Clinic)
+	require.Equal(t, "Europe/Berlin", first.TimeZone)
+	require.Equal(t, time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC), first.Start.UTC())
+	require.Equal(t, 90*time.Minute, first.End.Sub(*first.Start))
+
+	floating := events[1].Event
+	require.Equal(t, "Asia/Tokyo", floating.TimeZone)
+	require.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), floating.Start.UTC())
+	require.Nil(t, floating.End)
+
+	allDay := events[2].Event
+	require.True(t, allDay.AllDay)
+	require.Equal(t, 48*time.Hour, allDay.End.Sub(*allDay.Start))
+
+	require.Len(t, errs, 3)
+	var eventErr *EventError
+	require.ErrorAs(t, errs[0], &eventErr)
+	require.Equal(t, 2, eventErr.Index)
+	require.Equal(t, "no-start", eventErr.UID)
+	require.ErrorIs(t, errs[0], ErrMissingStart)
+
+	require.ErrorAs(t, errs[1], &eventErr)
+	require.Equal(t, 3, eventErr.Index)
+	require.ErrorIs(t, errs[1], storage.ErrInvalidTimeZone)
+
+	require.ErrorIs(t, errs[2], ErrEndBeforeStart)
+}
+
+func TestDecodeNotCalendar(t *testing.T) {
+	for _, doc := range []string{
+		"",
+		"hello world",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20240101\r\n",
+	} {
+		_, _, err := Decode(strings.NewReader(doc))
+		require.True(t, errors.Is(err, ErrNotCalendar), "%q: %v", doc, err)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		err  bool
+	}{
+		{in: "PT1H30M", want: 90 * time.Minute},
+		{in: "P1W", want: 7 * 24 * time.Hour},
+		{in: "P1DT2H", want: 26 * time.Hour},
+		{in: "-PT15M", want: -15 * time.Minute},
+		{in: "PT", err: true},
+		{in: "P1H", err: true},
+		{in: "1H", err: true},
+		{in: "PT5", err: true},
+	}
+	for _, tt := range tests {
+		got, err := parseDuration(tt.in)
+		if tt.err {
+			require.Error(t, err, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.want, got, tt.in)
+	}
+}
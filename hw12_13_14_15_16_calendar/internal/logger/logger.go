@@ -4,26 +4,42 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync/atomic"
 )
 
 // Logger supports 'info' and 'error' levels
 
 type Logger struct {
-	level string
+	level  *atomic.Pointer[string] // shared with loggers derived via With
+	fields string
 }
 
 func New(level string) *Logger {
-	return &Logger{level: strings.ToLower(level)}
+	l := &Logger{level: new(atomic.Pointer[string])}
+	l.SetLevel(level)
+	return l
+}
+
+// SetLevel changes the level of the logger and of every logger derived from it.
+func (l *Logger) SetLevel(level string) {
+	level = strings.ToLower(level)
+	l.level.Store(&level)
+}
+
+// With returns a copy of the logger that prefixes every line with key=value.
+func (l Logger) With(key, value string) *Logger {
+	l.fields += key + "=" + value + " "
+	return &l
 }
 
 func (l Logger) Info(msg string) {
-	if l.level == "info" {
-		fmt.Println(msg)
+	if *l.level.Load() == "info" {
+		fmt.Println(l.fields + msg)
 	}
 }
 
 func (l Logger) Error(msg string) {
-	if l.level == "info" || l.level == "error" {
-		fmt.Fprintln(os.Stderr, msg)
+	if level := *l.level.Load(); level == "info" || level == "error" {
+		fmt.Fprintln(os.Stderr, l.fields+msg)
 	}
 }
@@ -54,3 +54,28 @@ func TestLogger_ErrorLevel(t *testing.T) {
 		t.Errorf("Error message not found in stderr for error level, got: %q", errOut)
 	}
 }
+
+func TestLogger_With(t *testing.T) {
+	log := New("info").With("request_id", "abc").With("peer", "127.0.0.1:1234")
+	infoOut := captureOutput(func() {
+		log.Info("info message")
+	}, &os.Stdout)
+
+	if infoOut != "request_id=abc peer=127.0.0.1:1234 info message\n" {
+		t.Errorf("Expected fields prefix in info output, got: %q", infoOut)
+	}
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	log := New("error")
+	derived := log.With("request_id", "abc")
+	log.SetLevel("info")
+
+	infoOut := captureOutput(func() {
+		derived.Info("info message")
+	}, &os.Stdout)
+
+	if infoOut != "request_id=abc info message\n" {
+		t.Errorf("Expected derived logger to follow the new level, got: %q", infoOut)
+	}
+}
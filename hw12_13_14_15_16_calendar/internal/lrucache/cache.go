@@ -0,0 +1,116 @@
+// Package lrucache is a concurrent least-recently-used cache with expiring entries, after
+// the hw04 cache: a list keeps the entries in order of use and a map finds them by key.
+package lrucache
+
+import (
+	"sync"
+	"time"
+)
+
+type Cache[K comparable, V any] interface {
+	Set(key K, value V) bool // reports whether the key was in the cache
+	Get(key K) (V, bool)
+	Remove(key K) bool
+	Clear()
+	Len() int
+}
+
+type cacheItem[K comparable, V any] struct { // keeps the key to delete it from the map on eviction
+	key     K
+	value   V
+	expires time.Time // zero for entries that don't expire
+}
+
+type lruCache[K comparable, V any] struct {
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex // Get reorders the queue too, so there are no readers-only calls
+	queue List[cacheItem[K, V]]
+	items map[K]*ListItem[cacheItem[K, V]]
+}
+
+// NewCache creates a cache holding at most capacity entries, each for at most ttl;
+// a ttl of 0 keeps entries until they are evicted or removed.
+func NewCache[K comparable, V any](capacity int, ttl time.Duration) Cache[K, V] {
+	return &lruCache[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		queue:    NewList[cacheItem[K, V]](),
+		items:    make(map[K]*ListItem[cacheItem[K, V]], capacity),
+	}
+}
+
+func (l *lruCache[K, V]) Set(key K, value V) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	item := cacheItem[K, V]{key: key, value: value}
+	if l.ttl > 0 {
+		item.expires = l.now().Add(l.ttl)
+	}
+	if node, found := l.items[key]; found {
+		node.Value = item
+		l.queue.MoveToFront(node)
+		return true
+	}
+	l.items[key] = l.queue.PushFront(item)
+	if l.queue.Len() > l.capacity {
+		l.remove(l.queue.Back())
+	}
+	return false
+}
+
+func (l *lruCache[K, V]) Get(key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var zero V
+	node, found := l.items[key]
+	if !found {
+		return zero, false
+	}
+	if !node.Value.expires.IsZero() && !l.now().Before(node.Value.expires) {
+		l.remove(node)
+		return zero, false
+	}
+	l.queue.MoveToFront(node)
+	return node.Value.value, true
+}
+
+func (l *lruCache[K, V]) Remove(key K) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	node, found := l.items[key]
+	if found {
+		l.remove(node)
+	}
+	return found
+}
+
+func (l *lruCache[K, V]) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items = make(map[K]*ListItem[cacheItem[K, V]], l.capacity)
+	l.queue = NewList[cacheItem[K, V]]()
+}
+
+func (l *lruCache[K, V]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.queue.Len()
+}
+
+// remove drops an entry; the caller must hold mu.
+func (l *lruCache[K, V]) remove(node *ListItem[cacheItem[K, V]]) {
+	if node == nil {
+		return
+	}
+	l.queue.Remove(node)
+	delete(l.items, node.Value.key)
+}
@@ -0,0 +1,110 @@
+package lrucache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		c := NewCache[string, int](5, 0)
+
+		require.False(t, c.Set("aaa", 100))
+		require.False(t, c.Set("bbb", 200))
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+
+		require.True(t, c.Set("aaa", 300))
+		val, ok = c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 300, val)
+
+		_, ok = c.Get("ccc")
+		require.False(t, ok)
+	})
+
+	t.Run("evicts the least recently used", func(t *testing.T) {
+		c := NewCache[int, string](3, 0)
+		for i := 1; i <= 3; i++ {
+			c.Set(i, strconv.Itoa(i))
+		}
+		c.Get(1) // 2 is now the least recently used
+		c.Set(3, "three")
+		c.Set(4, "4")
+
+		_, ok := c.Get(2)
+		require.False(t, ok)
+		for _, key := range []int{1, 3, 4} {
+			_, ok := c.Get(key)
+			require.True(t, ok, key)
+		}
+		require.Equal(t, 3, c.Len())
+	})
+
+	t.Run("remove and clear", func(t *testing.T) {
+		c := NewCache[int, int](3, 0)
+		c.Set(1, 1)
+		c.Set(2, 2)
+		require.True(t, c.Remove(1))
+		require.False(t, c.Remove(1))
+		_, ok := c.Get(1)
+		require.False(t, ok)
+
+		c.Clear()
+		require.Equal(t, 0, c.Len())
+		_, ok = c.Get(2)
+		require.False(t, ok)
+	})
+
+	t.Run("expires entries", func(t *testing.T) {
+		now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+		c := NewCache[int, int](3, time.Minute).(*lruCache[int, int])
+		c.now = func() time.Time { return now }
+
+		c.Set(1, 1)
+		now = now.Add(30 * time.Second)
+		c.Set(2, 2)
+		_, ok := c.Get(1)
+		require.True(t, ok)
+
+		now = now.Add(30 * time.Second)
+		_, ok = c.Get(1)
+		require.False(t, ok)
+		require.Equal(t, 1, c.Len())
+		_, ok = c.Get(2)
+		require.True(t, ok)
+	})
+}
+
+func TestCacheMultithreading(t *testing.T) {
+	c := NewCache[string, int](10, time.Minute)
+	wg := &sync.WaitGroup{}
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000; i++ {
+			c.Set(strconv.Itoa(i), i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000; i++ {
+			c.Get(strconv.Itoa(i % 20))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000; i++ {
+			c.Remove(strconv.Itoa(i % 30))
+		}
+	}()
+
+	wg.Wait()
+	require.LessOrEqual(t, c.Len(), 10)
+}
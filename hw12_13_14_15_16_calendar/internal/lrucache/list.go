@@ -0,0 +1,97 @@
+package lrucache
+
+// List is a doubly linked list; the cache keeps its entries in it, most recently used first.
+type List[T any] interface {
+	Len() int
+	Front() *ListItem[T] // head
+	Back() *ListItem[T]  // tail
+	PushFront(v T) *ListItem[T]
+	PushBack(v T) *ListItem[T]
+	Remove(i *ListItem[T])
+	MoveToFront(i *ListItem[T])
+}
+
+type ListItem[T any] struct {
+	Value T
+	Next  *ListItem[T]
+	Prev  *ListItem[T]
+}
+
+type list[T any] struct {
+	head *ListItem[T]
+	tail *ListItem[T]
+	len  int
+}
+
+func NewList[T any]() List[T] {
+	return new(list[T])
+}
+
+func (l *list[T]) Len() int {
+	return l.len
+}
+
+func (l *list[T]) Front() *ListItem[T] {
+	return l.head
+}
+
+func (l *list[T]) Back() *ListItem[T] {
+	return l.tail
+}
+
+func (l *list[T]) PushFront(v T) *ListItem[T] {
+	item := &ListItem[T]{Value: v, Next: l.head}
+	if l.head != nil {
+		l.head.Prev = item
+	} else {
+		l.tail = item // the first item is both head and tail
+	}
+	l.head = item
+	l.len++
+	return item
+}
+
+func (l *list[T]) PushBack(v T) *ListItem[T] {
+	item := &ListItem[T]{Value: v, Prev: l.tail}
+	if l.tail != nil {
+		l.tail.Next = item
+	} else {
+		l.head = item // the first item is both head and tail
+	}
+	l.tail = item
+	l.len++
+	return item
+}
+
+func (l *list[T]) Remove(i *ListItem[T]) {
+	if i == nil {
+		return
+	}
+	if i.Prev != nil {
+		i.Prev.Next = i.Next
+	} else {
+		l.head = i.Next
+	}
+	if i.Next != nil {
+		i.Next.Prev = i.Prev
+	} else {
+		l.tail = i.Prev
+	}
+	l.len--
+	i.Next, i.Prev = nil, nil // help the GC
+}
+
+func (l *list[T]) MoveToFront(i *ListItem[T]) {
+	if i == nil || l.head == i {
+		return
+	}
+	i.Prev.Next = i.Next
+	if i.Next != nil {
+		i.Next.Prev = i.Prev
+	} else {
+		l.tail = i.Prev
+	}
+	i.Prev, i.Next = nil, l.head
+	l.head.Prev = i
+	l.head = i
+}
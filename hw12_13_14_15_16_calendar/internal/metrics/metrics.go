@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes every metric exported by the calendar binaries.
+const Namespace = "calendar"
+
+// Path is where the metrics handler is mounted.
+const Path = "/metrics"
+
+// Handler returns the Prometheus scrape handler for the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ListenAndServe exposes the metrics handler on addr. It is meant for the
+// producer and consumer, which have no HTTP server of their own.
+func ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler())
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
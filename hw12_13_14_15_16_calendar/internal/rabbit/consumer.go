@@ -1,9 +1,15 @@
 package rabbit
 
 import (
+	"context"
 	"log"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Consumer struct {
@@ -69,8 +75,7 @@ func (c *Consumer) Start(quit <-chan struct{}) {
 	for {
 		select {
 		case msg := <-c.msgs:
-			log.Printf("received: %s", msg.Body)
-			msg.Ack(false)
+			c.handle(msg)
 		case <-quit:
 			log.Println("consumer shutting down...")
 			return
@@ -78,6 +83,32 @@ func (c *Consumer) Start(quit <-chan struct{}) {
 	}
 }
 
+// handle processes a single delivery inside a span linked to the producer's trace.
+func (c *Consumer) handle(msg amqp.Delivery) {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), headerCarrier(msg.Headers))
+	_, span := tracing.Tracer().Start(ctx, "amqp.consume",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.source.name", c.queue),
+			attribute.Bool("messaging.rabbitmq.redelivered", msg.Redelivered),
+		))
+	defer span.End()
+
+	if msg.Redelivered {
+		consumerRedelivered.Inc()
+	}
+	log.Printf("received: %s", msg.Body)
+	if err := msg.Ack(false); err != nil {
+		log.Printf("failed to ack message: %v", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		consumerFailed.Inc()
+		return
+	}
+	consumerProcessed.Inc()
+}
+
 func (c *Consumer) Shutdown() error {
 	if err := c.channel.Close(); err != nil {
 		return err
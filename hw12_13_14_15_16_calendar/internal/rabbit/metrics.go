@@ -0,0 +1,41 @@
+package rabbit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/metrics"
+)
+
+var (
+	producerPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "producer",
+		Name:      "published_total",
+		Help:      "Number of notifications published to RabbitMQ.",
+	})
+	producerFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "producer",
+		Name:      "failed_total",
+		Help:      "Number of notifications that could not be published.",
+	})
+
+	consumerProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "consumer",
+		Name:      "processed_total",
+		Help:      "Number of notifications processed and acknowledged.",
+	})
+	consumerFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "consumer",
+		Name:      "failed_total",
+		Help:      "Number of notifications that could not be acknowledged.",
+	})
+	consumerRedelivered = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "consumer",
+		Name:      "redelivered_total",
+		Help:      "Number of notifications received with the redelivered flag set.",
+	})
+)
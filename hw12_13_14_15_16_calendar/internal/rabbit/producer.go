@@ -11,8 +11,17 @@ import (
 	"github.com/robfig/cron/v3"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// trashRetention is how long deleted events stay in the trash before the cleanup job
+// purges them.
+const trashRetention = 30 * 24 * time.Hour
+
 // Event represents the structure of an event.
 type Event struct {
 	ID        int       `json:"id"`
@@ -79,6 +88,9 @@ func (p *Producer) Start(quit <-chan struct{}) {
 		if err := p.CleanOldEvents(ctx); err != nil {
 			log.Printf("[Producer] failed to clean events: %v", err)
 		}
+		if err := p.PurgeTrash(ctx); err != nil {
+			log.Printf("[Producer] failed to purge trash: %v", err)
+		}
 	})
 	if err != nil {
 		log.Fatalf("[Producer] Failed to schedule cleanup cron: %v", err)
@@ -96,7 +108,7 @@ func (p *Producer) Start(quit <-chan struct{}) {
 
 // ListEventsDay generates and publishes event data for the day.
 func (p *Producer) ListEventsDay(ctx context.Context) error {
-	events, err := p.app.ListEvents(ctx, storage.PeriodDay)
+	events, err := p.app.ListEvents(ctx, storage.Filter{Period: storage.PeriodDay})
 	if err != nil {
 		log.Printf("failed to list day events: %v", err)
 		return fmt.Errorf("failed to list day events: %w", err)
@@ -115,7 +127,7 @@ func (p *Producer) ListEventsDay(ctx context.Context) error {
 			continue
 		}
 
-		if err := p.Publish(msg); err != nil {
+		if err := p.Publish(ctx, msg); err != nil {
 			log.Printf("[Producer] failed to publish: %v", err)
 			continue
 		}
@@ -126,23 +138,46 @@ func (p *Producer) ListEventsDay(ctx context.Context) error {
 }
 
 // Publish sends a raw message (JSON) to RabbitMQ.
-func (p *Producer) Publish(body []byte) error {
-	return p.channel.Publish(
+// The trace context of ctx is injected into the message headers.
+func (p *Producer) Publish(ctx context.Context, body []byte) error {
+	ctx, span := tracing.Tracer().Start(ctx, "amqp.publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", p.exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", p.key),
+		))
+	defer span.End()
+
+	headers := amqp.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+
+	err := p.channel.PublishWithContext(
+		ctx,
 		p.exchange,
 		p.key,
 		false,
 		false,
 		amqp.Publishing{
+			Headers:     headers,
 			ContentType: "text/plain",
 			Body:        body,
 			Timestamp:   time.Now(),
 		},
 	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		producerFailed.Inc()
+		return err
+	}
+	producerPublished.Inc()
+	return nil
 }
 
-// CleanOldEvents removes events older than one year.
+// CleanOldEvents moves events older than one year to the trash.
 func (p *Producer) CleanOldEvents(ctx context.Context) error {
-	events, err := p.app.ListEvents(ctx, storage.PeriodAll)
+	events, err := p.app.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll})
 	if err != nil {
 		log.Printf("failed to get all events: %v", err)
 		return fmt.Errorf("failed to get all events: %w", err)
@@ -159,6 +194,16 @@ func (p *Producer) CleanOldEvents(ctx context.Context) error {
 	return nil
 }
 
+// PurgeTrash permanently removes the events deleted more than trashRetention ago.
+func (p *Producer) PurgeTrash(ctx context.Context) error {
+	n, err := p.app.PurgeDeletedEvents(ctx, time.Now().Add(-trashRetention))
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted events: %w", err)
+	}
+	log.Printf("[Producer] purged %d deleted events", n)
+	return nil
+}
+
 func (p *Producer) Shutdown() error {
 	if err := p.channel.Close(); err != nil {
 		return err
@@ -0,0 +1,26 @@
+package rabbit
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// headerCarrier adapts AMQP message headers to the OpenTelemetry TextMapCarrier
+// interface so trace context travels from the producer to the consumer.
+type headerCarrier amqp.Table
+
+func (c headerCarrier) Get(key string) string {
+	v, _ := c[key].(string)
+	return v
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,32 @@
+package rabbit
+
+import (
+	"context"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeaderCarrier_PropagatesTraceContext(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+
+	propagator := propagation.TraceContext{}
+	headers := amqp.Table{}
+	propagator.Inject(ctx, headerCarrier(headers))
+	require.Contains(t, headers, "traceparent")
+
+	extracted := trace.SpanContextFromContext(propagator.Extract(context.Background(), headerCarrier(headers)))
+	require.Equal(t, traceID, extracted.TraceID())
+	require.Equal(t, spanID, extracted.SpanID())
+	require.True(t, extracted.IsRemote())
+}
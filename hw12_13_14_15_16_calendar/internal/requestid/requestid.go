@@ -0,0 +1,62 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const (
+	// Header is the HTTP header carrying the request ID.
+	Header = "X-Request-ID"
+	// MetadataKey is the gRPC metadata key carrying the request ID.
+	MetadataKey = "x-request-id"
+	// MaxLength bounds the length of request IDs sent by clients.
+	MaxLength = 64
+)
+
+type ctxKey struct{}
+
+// New generates a random request ID.
+func New() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// FromClient returns id if it is a valid client-supplied request ID, and a new one
+// otherwise. Client IDs end up in logs and response headers, so they are limited to
+// MaxLength characters out of [A-Za-z0-9._-].
+func FromClient(id string) string {
+	if !valid(id) {
+		return New()
+	}
+	return id
+}
+
+func valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx that carries the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
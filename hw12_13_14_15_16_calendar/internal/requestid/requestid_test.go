@@ -0,0 +1,39 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestContextRoundTrip(t *testing.T) {
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("expected empty request ID, got %q", got)
+	}
+
+	id := New()
+	if len(id) != 32 {
+		t.Errorf("expected 32 hex chars, got %q", id)
+	}
+	if id == New() {
+		t.Errorf("expected unique request IDs")
+	}
+
+	ctx := NewContext(context.Background(), id)
+	if got := FromContext(ctx); got != id {
+		t.Errorf("expected %q, got %q", id, got)
+	}
+}
+
+func TestFromClient(t *testing.T) {
+	for _, id := range []string{"client-id", "from_gateway.1", strings.Repeat("a", MaxLength)} {
+		if got := FromClient(id); got != id {
+			t.Errorf("expected %q to be kept, got %q", id, got)
+		}
+	}
+	for _, id := range []string{"", strings.Repeat("a", MaxLength+1), "two words", "evil\r\nX-Admin: 1", "идентификатор"} {
+		if got := FromClient(id); got == id || len(got) != 32 {
+			t.Errorf("expected %q to be replaced by a new ID, got %q", id, got)
+		}
+	}
+}
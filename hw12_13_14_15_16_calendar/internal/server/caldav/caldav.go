@@ -0,0 +1,568 @@
+// Package caldav serves users' calendars over a minimal CalDAV (RFC 4791) interface so
+// that standard calendar clients can subscribe to and edit them.
+//
+// The URL space below Prefix is:
+//
+//	/caldav/                        service root; current-user-principal
+//	/caldav/{userId}/               principal and calendar home of a user
+//	/caldav/{userId}/events/        the user's calendar: owned and attended events
+//	/caldav/{userId}/events/{id}.ics one event
+//	/caldav/{userId}/events/{name}.ics one event a client created under a name of its own
+//
+// PROPFIND, REPORT (calendar-query and calendar-multiget), GET, PUT and DELETE are
+// supported. A PUT of a new resource creates an event. Under a name the client chose, the
+// event keeps that name and the client's UID in the owner's calendar, so the client finds
+// it again; under {id}.ics of a missing event, the server assigns the ID and returns the
+// URL in Location. Attended events are read only, and always named {id}.ics; only the
+// owner can change or delete an event.
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/ical"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+const (
+	// Prefix is the path the handler is mounted on.
+	Prefix = "/caldav/"
+	// WellKnownPath is the discovery URL of RFC 6764; it redirects to Prefix.
+	WellKnownPath = "/.well-known/caldav"
+
+	calendarSegment = "events"
+	resourceSuffix  = ".ics"
+	maxBody         = 1 << 20
+)
+
+type targetKind int
+
+const (
+	targetRoot targetKind = iota
+	targetHome
+	targetCalendar
+	targetEvent
+)
+
+// target is the resource addressed by a request path.
+type target struct {
+	kind    targetKind
+	userID  int
+	eventID int    // 0 for a resource name the server did not assign
+	name    string // the resource name, e.g. "42.ics"
+}
+
+// parseTarget maps a path below Prefix to a target.
+func parseTarget(path string) (target, bool) {
+	rel, ok := strings.CutPrefix(path, Prefix)
+	if !ok {
+		return target{}, path+"/" == Prefix
+	}
+	parts := strings.Split(strings.TrimSuffix(rel, "/"), "/")
+	if rel == "" {
+		return target{kind: targetRoot}, true
+	}
+
+	uid, err := strconv.Atoi(parts[0])
+	if err != nil || uid <= 0 {
+		return target{}, false
+	}
+	t := target{kind: targetHome, userID: uid}
+	switch {
+	case len(parts) == 1:
+		return t, true
+	case parts[1] != calendarSegment || len(parts) > 3:
+		return target{}, false
+	case len(parts) == 2:
+		t.kind = targetCalendar
+		return t, true
+	}
+
+	name, ok := strings.CutSuffix(parts[2], resourceSuffix)
+	if !ok || name == "" {
+		return target{}, false
+	}
+	t.kind, t.name = targetEvent, parts[2]
+	if id, err := strconv.Atoi(name); err == nil && id > 0 {
+		t.eventID = id
+	}
+	return t, true
+}
+
+func homeHref(userID int) string {
+	return Prefix + strconv.Itoa(userID) + "/"
+}
+
+func calendarHref(userID int) string {
+	return homeHref(userID) + calendarSegment + "/"
+}
+
+// eventHref returns the URL of the event in the user's calendar: the name its client gave
+// it in the owner's calendar, {id}.ics otherwise.
+func eventHref(userID int, ev storage.Event) string {
+	if ev.ResourceName != "" && owns(ev, userID) {
+		return calendarHref(userID) + ev.ResourceName
+	}
+	return calendarHref(userID) + strconv.Itoa(ev.ID) + resourceSuffix
+}
+
+// Handler serves CalDAV requests using app.App.
+type Handler struct {
+	app   *app.App
+	log   *logger.Logger
+	authn *auth.Authenticator
+}
+
+// NewHandler creates a CalDAV handler. With a nil authn every calendar is open, like
+// the gRPC API with authentication disabled; otherwise callers authenticate with HTTP
+// Basic (an API key as password), an X-API-Key header or a bearer token, and callers
+// acting as a calendar user can only reach that user's calendar.
+func NewHandler(application *app.App, log *logger.Logger, authn *auth.Authenticator) *Handler {
+	return &Handler{app: application, log: log, authn: authn}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var identity auth.Identity
+	if h.authn != nil {
+		var err error
+		if identity, err = h.authn.AuthenticateHTTP(r); err != nil {
+			h.log.Error(fmt.Sprintf("caldav: authentication failed: %v", err))
+			w.Header().Set("WWW-Authenticate", `Basic realm="calendar", charset="UTF-8"`)
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		r = r.WithContext(auth.NewContext(r.Context(), identity))
+	}
+
+	t, ok := parseTarget(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if t.kind != targetRoot && identity.UserID != nil && *identity.UserID != t.userID {
+		http.Error(w, "calendar belongs to another user", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusNoContent)
+	case "PROPFIND":
+		h.propfind(w, r, t, identity)
+	case "REPORT":
+		h.report(w, r, t)
+	case http.MethodGet, http.MethodHead:
+		h.get(w, r, t)
+	case http.MethodPut:
+		h.put(w, r, t)
+	case http.MethodDelete:
+		h.delete(w, r, t)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) propfind(w http.ResponseWriter, r *http.Request, t target, identity auth.Identity) {
+	var req propfindRequest
+	if err := decodeXML(r.Body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var names []xml.Name
+	if req.AllProp == nil && len(req.Prop) > 0 {
+		names = req.Prop
+	}
+	deep := r.Header.Get("Depth") != "0"
+
+	var responses []response
+	switch t.kind {
+	case targetRoot:
+		responses = append(responses, response{href: Prefix, props: props{
+			propResourceType: "<D:collection/>",
+			propPrincipal:    principalProp(identity),
+		}})
+		if deep && identity.UserID != nil {
+			responses = append(responses, h.homeResponse(*identity.UserID))
+		}
+	case targetHome:
+		responses = append(responses, h.homeResponse(t.userID))
+		if deep {
+			events, err := h.events(r, t.userID, storage.Filter{Period: storage.PeriodAll})
+			if err != nil {
+				h.fail(w, "list events", err)
+				return
+			}
+			responses = append(responses, calendarResponse(t.userID, events))
+		}
+	case targetCalendar:
+		events, err := h.events(r, t.userID, storage.Filter{Period: storage.PeriodAll})
+		if err != nil {
+			h.fail(w, "list events", err)
+			return
+		}
+		responses = append(responses, calendarResponse(t.userID, events))
+		if deep {
+			for _, ev := range events {
+				responses = append(responses, h.eventResponse(t.userID, ev, wants(names, propCalendarData)))
+			}
+		}
+	case targetEvent:
+		ev, ok := h.visibleEvent(w, r, t)
+		if !ok {
+			return
+		}
+		responses = append(responses, h.eventResponse(t.userID, ev, wants(names, propCalendarData)))
+	}
+	writeMultistatus(w, responses, names)
+}
+
+func (h *Handler) report(w http.ResponseWriter, r *http.Request, t target) {
+	if t.kind != targetCalendar {
+		http.Error(w, "reports are supported on calendar collections only", http.StatusForbidden)
+		return
+	}
+	var req reportRequest
+	if err := decodeXML(r.Body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var names []xml.Name
+	if len(req.Prop) > 0 {
+		names = req.Prop
+	}
+	withData := names == nil || wants(names, propCalendarData)
+
+	var responses []response
+	switch req.XMLName {
+	case reportQuery:
+		from, to, ok, err := req.Filter.eventRange()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ok {
+			events, err := h.events(r, t.userID, storage.Filter{Period: storage.PeriodAll, From: from, To: to})
+			if err != nil {
+				h.fail(w, "query events", err)
+				return
+			}
+			for _, ev := range events {
+				responses = append(responses, h.eventResponse(t.userID, ev, withData))
+			}
+		}
+	case reportMultiget:
+		for _, href := range req.Hrefs {
+			responses = append(responses, h.multigetResponse(r, t.userID, href, withData))
+		}
+	default:
+		http.Error(w, "unsupported report", http.StatusNotImplemented)
+		return
+	}
+	writeMultistatus(w, responses, names)
+}
+
+func (h *Handler) multigetResponse(r *http.Request, userID int, href string, withData bool) response {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return response{href: href, status: http.StatusNotFound}
+	}
+	t, ok := parseTarget(u.Path)
+	if !ok || t.kind != targetEvent || t.userID != userID {
+		return response{href: href, status: http.StatusNotFound}
+	}
+	ev, err := h.lookup(r, t)
+	if err != nil {
+		return response{href: href, status: http.StatusNotFound}
+	}
+	return h.eventResponse(userID, ev, withData)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, t target) {
+	var events []storage.Event
+	switch t.kind {
+	case targetCalendar:
+		var err error
+		if events, err = h.events(r, t.userID, storage.Filter{Period: storage.PeriodAll}); err != nil {
+			h.fail(w, "list events", err)
+			return
+		}
+	case targetEvent:
+		ev, ok := h.visibleEvent(w, r, t)
+		if !ok {
+			return
+		}
+		events = []storage.Event{ev}
+		w.Header().Set("ETag", etag(ev))
+	default:
+		http.Error(w, "not a calendar resource", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, events); err != nil {
+		h.fail(w, "encode calendar", err)
+		return
+	}
+	w.Header().Set("Content-Type", ical.ContentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, t target) {
+	if t.kind != targetEvent {
+		http.Error(w, "only event resources can be written", http.StatusMethodNotAllowed)
+		return
+	}
+	events, errs, err := ical.Decode(io.LimitReader(r.Body, maxBody))
+	switch {
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case len(errs) > 0:
+		http.Error(w, errs[0].Error(), http.StatusBadRequest)
+		return
+	case len(events) != 1:
+		http.Error(w, "a calendar resource must contain exactly one VEVENT", http.StatusBadRequest)
+		return
+	}
+	event := events[0].Event
+
+	var existing *storage.Event
+	ev, err := h.lookup(r, t)
+	switch {
+	case err == nil:
+		existing = &ev
+	case !errors.Is(err, storage.ErrEventNotFound):
+		h.fail(w, "get event", err)
+		return
+	}
+	if !preconditionsMet(r, existing) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	if existing == nil {
+		h.create(w, r, t, event, events[0].UID)
+		return
+	}
+
+	if !owns(*existing, t.userID) {
+		http.Error(w, "only the owner can change the event", http.StatusForbidden)
+		return
+	}
+	event.ID, event.UserID = existing.ID, existing.UserID
+	if err := h.app.UpdateEvent(r.Context(), event); err != nil {
+		h.writeError(w, "update event", err)
+		return
+	}
+	if updated, err := h.app.GetEvent(r.Context(), event.ID); err == nil {
+		w.Header().Set("ETag", etag(updated))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// create stores the event of a PUT to a missing resource. Under a name of the client's,
+// the event keeps the name and the client's UID; otherwise the server names it.
+func (h *Handler) create(w http.ResponseWriter, r *http.Request, t target, event storage.Event, uid string) {
+	event.UserID = &t.userID
+	if t.eventID == 0 {
+		event.ResourceName = t.name
+		if _, ours := ical.EventID(uid); !ours {
+			event.UID = uid
+		}
+	}
+	id, err := h.app.CreateEvent(r.Context(), event)
+	if err != nil {
+		h.writeError(w, "create event", err)
+		return
+	}
+	created, err := h.app.GetEvent(r.Context(), id)
+	if err != nil {
+		h.fail(w, "get event", err)
+		return
+	}
+	w.Header().Set("ETag", etag(created))
+	if t.eventID != 0 {
+		w.Header().Set("Location", eventHref(t.userID, created))
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, t target) {
+	if t.kind != targetEvent {
+		http.Error(w, "only event resources can be deleted", http.StatusMethodNotAllowed)
+		return
+	}
+	ev, ok := h.visibleEvent(w, r, t)
+	if !ok {
+		return
+	}
+	if !preconditionsMet(r, &ev) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if !owns(ev, t.userID) {
+		http.Error(w, "only the owner can delete the event", http.StatusForbidden)
+		return
+	}
+	if err := h.app.DeleteEvent(r.Context(), ev.ID); err != nil {
+		h.writeError(w, "delete event", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// events lists the events the user owns or attends.
+func (h *Handler) events(r *http.Request, userID int, filter storage.Filter) ([]storage.Event, error) {
+	filter.UserIDs = []int{userID}
+	return h.app.ListEvents(r.Context(), filter)
+}
+
+// visibleEvent loads the event of t, writing 404 if it doesn't exist or is not in the user's calendar.
+func (h *Handler) visibleEvent(w http.ResponseWriter, r *http.Request, t target) (storage.Event, bool) {
+	ev, err := h.lookup(r, t)
+	if errors.Is(err, storage.ErrEventNotFound) {
+		http.NotFound(w, r)
+		return storage.Event{}, false
+	}
+	if err != nil {
+		h.fail(w, "get event", err)
+		return storage.Event{}, false
+	}
+	return ev, true
+}
+
+// lookup loads the event that t names in the user's calendar: by ID, or by the name a
+// client gave it in the owner's calendar. It returns storage.ErrEventNotFound if there is
+// none.
+func (h *Handler) lookup(r *http.Request, t target) (storage.Event, error) {
+	if t.eventID != 0 {
+		ev, err := h.app.GetEvent(r.Context(), t.eventID)
+		if err == nil && !ev.Involves(t.userID) {
+			err = storage.ErrEventNotFound
+		}
+		return ev, err
+	}
+	events, err := h.app.ListEvents(r.Context(), storage.Filter{
+		Period: storage.PeriodAll, UserIDs: []int{t.userID}, ResourceName: t.name,
+	})
+	if err != nil {
+		return storage.Event{}, err
+	}
+	for _, ev := range events {
+		if owns(ev, t.userID) {
+			return ev, nil
+		}
+	}
+	return storage.Event{}, storage.ErrEventNotFound
+}
+
+func (h *Handler) homeResponse(userID int) response {
+	return response{href: homeHref(userID), props: props{
+		propResourceType: "<D:collection/><D:principal/>",
+		propDisplayName:  "User " + strconv.Itoa(userID),
+		propPrincipalURL: hrefElement(homeHref(userID)),
+		propHomeSet:      hrefElement(homeHref(userID)),
+	}}
+}
+
+func calendarResponse(userID int, events []storage.Event) response {
+	tags := sha256.New()
+	for _, ev := range events {
+		tags.Write([]byte(etag(ev)))
+	}
+	return response{href: calendarHref(userID), props: props{
+		propResourceType: "<D:collection/><C:calendar/>",
+		propDisplayName:  "Calendar",
+		propComponentSet: `<C:comp name="VEVENT"/>`,
+		propCTag:         hex.EncodeToString(tags.Sum(nil)[:16]),
+	}}
+}
+
+func (h *Handler) eventResponse(userID int, ev storage.Event, withData bool) response {
+	p := props{
+		propResourceType: "",
+		propETag:         escapeString(etag(ev)),
+		propContentType:  "text/calendar; charset=utf-8; component=VEVENT",
+	}
+	if withData {
+		var buf bytes.Buffer
+		if err := ical.Encode(&buf, []storage.Event{ev}); err != nil {
+			h.log.Error(fmt.Sprintf("caldav: encode event %d: %v", ev.ID, err))
+		} else {
+			p[propCalendarData] = escapeString(buf.String())
+		}
+	}
+	return response{href: eventHref(userID, ev), props: p}
+}
+
+func principalProp(identity auth.Identity) string {
+	if identity.UserID == nil {
+		return "<D:unauthenticated/>"
+	}
+	return hrefElement(homeHref(*identity.UserID))
+}
+
+// etag identifies a version of the event; it changes with any stored field.
+func etag(ev storage.Event) string {
+	data, _ := json.Marshal(ev)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// preconditionsMet checks If-Match and If-None-Match against the current version of the
+// resource; existing is nil if there is none.
+func preconditionsMet(r *http.Request, existing *storage.Event) bool {
+	if match := r.Header.Get("If-Match"); match != "" {
+		if existing == nil || (match != "*" && match != etag(*existing)) {
+			return false
+		}
+	}
+	if r.Header.Get("If-None-Match") == "*" && existing != nil {
+		return false
+	}
+	return true
+}
+
+func owns(ev storage.Event, userID int) bool {
+	return ev.UserID != nil && *ev.UserID == userID
+}
+
+func wants(names []xml.Name, name xml.Name) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError reports a failed write: invalid events are the client's fault, anything
+// else is logged and hidden behind a 500.
+func (h *Handler) writeError(w http.ResponseWriter, what string, err error) {
+	if errors.Is(err, app.ErrInvalidEvent) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.fail(w, what, err)
+}
+
+func (h *Handler) fail(w http.ResponseWriter, what string, err error) {
+	h.log.Error(fmt.Sprintf("caldav: failed to %s: %v", what, err))
+	http.Error(w, "something went wrong, pls try again a bit later", http.StatusInternalServerError)
+}
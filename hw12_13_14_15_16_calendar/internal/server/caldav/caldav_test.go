@@ -0,0 +1,255 @@
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// multistatus is the client-side view of a 207 response.
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Status   string `xml:"DAV: status"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				Inner []byte `xml:",innerxml"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+func (m multistatus) hrefs() []string {
+	var hrefs []string
+	for _, r := range m.Responses {
+		hrefs = append(hrefs, r.Href)
+	}
+	return hrefs
+}
+
+type client struct {
+	t        *testing.T
+	base     string
+	password string
+}
+
+func (c client) do(method, path, body string, headers ...string) *http.Response {
+	c.t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), method, c.base+path, strings.NewReader(body))
+	require.NoError(c.t, err)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	if c.password != "" {
+		req.SetBasicAuth("staff", c.password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+	c.t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func (c client) multistatus(method, path, body string, headers ...string) multistatus {
+	c.t.Helper()
+	resp := c.do(method, path, body, headers...)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(c.t, err)
+	require.Equal(c.t, http.StatusMultiStatus, resp.StatusCode, string(data))
+
+	var ms multistatus
+	require.NoError(c.t, xml.Unmarshal(data, &ms))
+	return ms
+}
+
+func newApp(t *testing.T) *app.App {
+	t.Helper()
+	log := logger.New("error")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+
+	ctx := context.Background()
+	owner, other := 7, 8
+	for _, ev := range []storage.Event{
+		{Title: "Own check-up", Start: at(10, 9), End: at(10, 10), UserID: &owner},
+		{Title: "Invited", Start: at(20, 9), UserID: &other},
+		{Title: "Not mine", Start: at(10, 9), UserID: &other},
+	} {
+		_, err := application.CreateEvent(ctx, ev)
+		require.NoError(t, err)
+	}
+	require.NoError(t, application.InviteAttendee(ctx, storage.Attendee{EventID: 2, UserID: owner}))
+	return application
+}
+
+func at(day, hour int) *time.Time {
+	t := time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+	return &t
+}
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/" xmlns:X="urn:example">
+  <D:prop><D:resourcetype/><D:getetag/><CS:getctag/><X:color/></D:prop>
+</D:propfind>`
+
+func TestPropfind(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(newApp(t), logger.New("error"), nil))
+	defer srv.Close()
+	c := client{t: t, base: srv.URL}
+
+	ms := c.multistatus("PROPFIND", "/caldav/7/events/", propfindBody, "Depth", "1")
+	require.Equal(t, []string{"/caldav/7/events/", "/caldav/7/events/1.ics", "/caldav/7/events/2.ics"}, ms.hrefs())
+
+	collection := ms.Responses[0]
+	require.Len(t, collection.Propstat, 2)
+	require.Contains(t, collection.Propstat[0].Status, "200")
+	require.Contains(t, string(collection.Propstat[0].Prop.Inner), "<C:calendar/>")
+	require.Contains(t, string(collection.Propstat[0].Prop.Inner), "getctag")
+	require.Contains(t, collection.Propstat[1].Status, "404")
+	require.Contains(t, string(collection.Propstat[1].Prop.Inner), `<color xmlns="urn:example"/>`)
+
+	ms = c.multistatus("PROPFIND", "/caldav/7/", "", "Depth", "0")
+	require.Equal(t, []string{"/caldav/7/"}, ms.hrefs())
+	require.Contains(t, string(ms.Responses[0].Propstat[0].Prop.Inner),
+		"<C:calendar-home-set><D:href>/caldav/7/</D:href></C:calendar-home-set>")
+
+	require.Equal(t, http.StatusNotFound, c.do("PROPFIND", "/caldav/7/events/3.ics", "").StatusCode)
+	require.Equal(t, http.StatusNotFound, c.do("PROPFIND", "/caldav/seven/", "").StatusCode)
+}
+
+func TestReport(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(newApp(t), logger.New("error"), nil))
+	defer srv.Close()
+	c := client{t: t, base: srv.URL}
+
+	query := `<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">
+    <C:time-range start="20240315T000000Z" end="20240401T000000Z"/>
+  </C:comp-filter></C:comp-filter></C:filter>
+</C:calendar-query>`
+	ms := c.multistatus("REPORT", "/caldav/7/events/", query, "Depth", "1")
+	require.Equal(t, []string{"/caldav/7/events/2.ics"}, ms.hrefs())
+	require.Contains(t, string(ms.Responses[0].Propstat[0].Prop.Inner), "SUMMARY:Invited")
+
+	todos := strings.Replace(query, `name="VEVENT"`, `name="VTODO"`, 1)
+	require.Empty(t, c.multistatus("REPORT", "/caldav/7/events/", todos).Responses)
+
+	multiget := `<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/></D:prop>
+  <D:href>/caldav/7/events/1.ics</D:href>
+  <D:href>/caldav/7/events/3.ics</D:href>
+</C:calendar-multiget>`
+	ms = c.multistatus("REPORT", "/caldav/7/events/", multiget)
+	require.Equal(t, []string{"/caldav/7/events/1.ics", "/caldav/7/events/3.ics"}, ms.hrefs())
+	require.NotContains(t, string(ms.Responses[0].Propstat[0].Prop.Inner), "calendar-data")
+	require.Contains(t, ms.Responses[1].Status, "404")
+}
+
+func TestReadWrite(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(newApp(t), logger.New("error"), nil))
+	defer srv.Close()
+	c := client{t: t, base: srv.URL}
+
+	doc := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:3f6c@client\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20240312T090000\r\nDURATION:PT30M\r\nSUMMARY:Dentist\r\n" +
+		"LOCATION:Main street 1\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	// The event keeps the name and the UID its client gave it.
+	const href = "/caldav/7/events/3f6c.ics"
+	resp := c.do(http.MethodPut, href, doc, "Content-Type", "text/calendar")
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Empty(t, resp.Header.Get("Location"))
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	resp = c.do(http.MethodGet, href, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "UID:3f6c@client")
+	require.Contains(t, string(body), "DTSTART;TZID=Europe/Berlin:20240312T090000")
+	require.Contains(t, string(body), "LOCATION:Main street 1")
+	require.Equal(t, etag, resp.Header.Get("ETag"))
+
+	updated := strings.Replace(doc, "Dentist", "Dentist (moved)", 1)
+	require.Equal(t, http.StatusPreconditionFailed,
+		c.do(http.MethodPut, href, updated, "If-Match", `"stale"`).StatusCode)
+	resp = c.do(http.MethodPut, href, updated, "If-Match", etag)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.NotEqual(t, etag, resp.Header.Get("ETag"))
+	require.Equal(t, http.StatusNoContent, c.do(http.MethodPut, href, updated).StatusCode)
+
+	resp = c.do(http.MethodGet, "/caldav/7/events/", "")
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, 3, strings.Count(string(body), "BEGIN:VEVENT"), "writing the resource again must not add events")
+	require.Contains(t, string(body), "SUMMARY:Dentist (moved)")
+	ms := c.multistatus("PROPFIND", "/caldav/7/events/", propfindBody, "Depth", "1")
+	require.Contains(t, ms.hrefs(), href)
+
+	// Under {id}.ics of a missing event, the server names the event.
+	resp = c.do(http.MethodPut, "/caldav/7/events/99.ics", strings.Replace(doc, "3f6c@client", "99@calendar", 1))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, "/caldav/7/events/5.ics", resp.Header.Get("Location"))
+	require.NotEmpty(t, resp.Header.Get("ETag"))
+
+	require.Equal(t, http.StatusBadRequest,
+		c.do(http.MethodPut, "/caldav/7/events/new.ics", "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n").StatusCode)
+	require.Equal(t, http.StatusForbidden, c.do(http.MethodDelete, "/caldav/7/events/2.ics", "").StatusCode)
+	require.Equal(t, http.StatusNoContent, c.do(http.MethodDelete, href, "").StatusCode)
+	require.Equal(t, http.StatusNotFound, c.do(http.MethodGet, href, "").StatusCode)
+}
+
+// TestPutSameResource checks that a client writing its own resource twice, as it does
+// when it syncs an edit, keeps a single event.
+func TestPutSameResource(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(newApp(t), logger.New("error"), nil))
+	defer srv.Close()
+	c := client{t: t, base: srv.URL}
+
+	const href = "/caldav/7/events/6d1f0a52-8b2e-4c7a-9f3e-1b2c3d4e5f60.ics"
+	doc := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:6d1f0a52-8b2e-4c7a-9f3e-1b2c3d4e5f60\r\n" +
+		"DTSTART:20240314T090000Z\r\nSUMMARY:Physio\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	require.Equal(t, http.StatusCreated, c.do(http.MethodPut, href, doc).StatusCode)
+	require.Equal(t, http.StatusNoContent,
+		c.do(http.MethodPut, href, strings.Replace(doc, "Physio", "Physio (moved)", 1)).StatusCode)
+
+	resp := c.do(http.MethodGet, "/caldav/7/events/", "")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(body), "UID:6d1f0a52-8b2e-4c7a-9f3e-1b2c3d4e5f60"))
+	require.Contains(t, string(body), "SUMMARY:Physio (moved)")
+}
+
+func TestAuthentication(t *testing.T) {
+	authn, err := auth.New(config.AuthConf{APIKeys: []config.APIKeyConf{{Key: "s3cret", Subject: "staff", UserID: 7}}})
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewHandler(newApp(t), logger.New("error"), authn))
+	defer srv.Close()
+
+	anonymous := client{t: t, base: srv.URL}
+	resp := anonymous.do("PROPFIND", "/caldav/", "")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("WWW-Authenticate"), "Basic")
+
+	c := client{t: t, base: srv.URL, password: "s3cret"}
+	ms := c.multistatus("PROPFIND", "/caldav/", "", "Depth", "1")
+	require.Equal(t, []string{"/caldav/", "/caldav/7/"}, ms.hrefs())
+	require.Contains(t, string(ms.Responses[0].Propstat[0].Prop.Inner),
+		"<D:current-user-principal><D:href>/caldav/7/</D:href></D:current-user-principal>")
+
+	require.Equal(t, http.StatusForbidden, c.do(http.MethodGet, "/caldav/8/events/", "").StatusCode)
+	require.Equal(t, http.StatusOK, c.do(http.MethodGet, "/caldav/7/events/", "").StatusCode)
+}
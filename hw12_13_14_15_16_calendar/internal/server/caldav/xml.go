@@ -0,0 +1,235 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+
+	// timeRangeFormat is the UTC DATE-TIME format of time-range attributes.
+	timeRangeFormat = "20060102T150405Z"
+)
+
+var (
+	propResourceType = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName  = xml.Name{Space: nsDAV, Local: "displayname"}
+	propPrincipal    = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propETag         = xml.Name{Space: nsDAV, Local: "getetag"}
+	propContentType  = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propHomeSet      = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propComponentSet = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propCTag         = xml.Name{Space: nsCS, Local: "getctag"}
+	reportQuery      = xml.Name{Space: nsCalDAV, Local: "calendar-query"}
+	reportMultiget   = xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}
+)
+
+var errUnsupportedBody = errors.New("unsupported request body")
+
+// prefixes used when writing responses; other namespaces are declared inline.
+var prefixes = map[string]string{nsDAV: "D", nsCalDAV: "C", nsCS: "CS"}
+
+// propNames collects the names of the child elements of a DAV:prop element.
+type propNames []xml.Name
+
+func (p *propNames) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type propfindRequest struct {
+	XMLName xml.Name  `xml:"DAV: propfind"`
+	AllProp *struct{} `xml:"DAV: allprop"`
+	Prop    propNames `xml:"DAV: prop"`
+}
+
+type timeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+type compFilter struct {
+	Name      string       `xml:"name,attr"`
+	TimeRange *timeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	Comps     []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// reportRequest covers calendar-query and calendar-multiget.
+type reportRequest struct {
+	XMLName xml.Name
+	Prop    propNames  `xml:"DAV: prop"`
+	Filter  compFilter `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+	Hrefs   []string   `xml:"DAV: href"`
+}
+
+// eventRange returns the time range of a calendar-query filter. ok is false when the
+// filter selects components other than VEVENT, which this server does not store.
+func (f compFilter) eventRange() (from, to time.Time, ok bool, err error) {
+	if f.Name == "" && len(f.Comps) == 0 {
+		return from, to, true, nil // no filter: everything
+	}
+	if !strings.EqualFold(f.Name, "VCALENDAR") {
+		return from, to, false, nil
+	}
+	if len(f.Comps) == 0 {
+		return from, to, true, nil
+	}
+	event := f.Comps[0]
+	if !strings.EqualFold(event.Name, "VEVENT") {
+		return from, to, false, nil
+	}
+	if event.TimeRange == nil {
+		return from, to, true, nil
+	}
+	if event.TimeRange.Start != "" {
+		if from, err = time.Parse(timeRangeFormat, event.TimeRange.Start); err != nil {
+			return from, to, false, fmt.Errorf("time-range start: %w", err)
+		}
+	}
+	if event.TimeRange.End != "" {
+		if to, err = time.Parse(timeRangeFormat, event.TimeRange.End); err != nil {
+			return from, to, false, fmt.Errorf("time-range end: %w", err)
+		}
+	}
+	return from, to, true, nil
+}
+
+// decodeXML reads an optional request body; an empty body leaves v untouched.
+func decodeXML(r io.Reader, v interface{}) error {
+	err := xml.NewDecoder(io.LimitReader(r, maxBody)).Decode(v)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUnsupportedBody, err)
+	}
+	return nil
+}
+
+// props maps property names to their XML content, already escaped.
+type props map[xml.Name]string
+
+// response is one DAV:response of a multistatus. A non-zero status reports the resource
+// itself, e.g. a missing calendar-multiget href, instead of its properties.
+type response struct {
+	href   string
+	props  props
+	status int
+}
+
+// writeMultistatus writes a 207 Multi-Status. With names, every response lists those
+// properties, unknown ones with 404; without names all known properties are returned.
+func writeMultistatus(w http.ResponseWriter, responses []response, names []xml.Name) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"` +
+		` xmlns:CS="http://calendarserver.org/ns/">`)
+	for _, resp := range responses {
+		b.WriteString("<D:response><D:href>")
+		xmlEscape(&b, resp.href)
+		b.WriteString("</D:href>")
+		if resp.status != 0 {
+			fmt.Fprintf(&b, "<D:status>HTTP/1.1 %d %s</D:status></D:response>", resp.status, http.StatusText(resp.status))
+			continue
+		}
+
+		found, missing := resp.props, []xml.Name(nil)
+		if names != nil {
+			found = props{}
+			for _, name := range names {
+				if value, ok := resp.props[name]; ok {
+					found[name] = value
+				} else {
+					missing = append(missing, name)
+				}
+			}
+		}
+		if len(found) > 0 {
+			b.WriteString("<D:propstat><D:prop>")
+			for _, name := range sortedNames(found) {
+				writeElement(&b, name, found[name])
+			}
+			b.WriteString("</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>")
+		}
+		if len(missing) > 0 {
+			b.WriteString("<D:propstat><D:prop>")
+			for _, name := range missing {
+				writeElement(&b, name, "")
+			}
+			b.WriteString("</D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat>")
+		}
+		b.WriteString("</D:response>")
+	}
+	b.WriteString("</D:multistatus>")
+
+	w.Header().Set("Content-Type", `application/xml; charset="utf-8"`)
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, b.String())
+}
+
+func writeElement(b *strings.Builder, name xml.Name, content string) {
+	tag, decl := name.Local, ""
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		decl = ` xmlns="` + escapeString(name.Space) + `"`
+	}
+	if content == "" {
+		b.WriteString("<" + tag + decl + "/>")
+		return
+	}
+	b.WriteString("<" + tag + decl + ">" + content + "</" + tag + ">")
+}
+
+// sortedNames keeps the output stable for clients and tests.
+func sortedNames(p props) []xml.Name {
+	names := make([]xml.Name, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Space != names[j].Space {
+			return names[i].Space < names[j].Space
+		}
+		return names[i].Local < names[j].Local
+	})
+	return names
+}
+
+func hrefElement(href string) string {
+	return "<D:href>" + escapeString(href) + "</D:href>"
+}
+
+func escapeString(s string) string {
+	var b strings.Builder
+	xmlEscape(&b, s)
+	return b.String()
+}
+
+func xmlEscape(b *strings.Builder, s string) {
+	_ = xml.EscapeText(b, []byte(s))
+}
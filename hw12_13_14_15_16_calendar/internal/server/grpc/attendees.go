@@ -0,0 +1,88 @@
+package calendargrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toProtoAttendees(attendees []storage.Attendee) []*calendarpb.Attendee {
+	if len(attendees) == 0 {
+		return nil
+	}
+	result := make([]*calendarpb.Attendee, len(attendees))
+	for i, a := range attendees {
+		result[i] = &calendarpb.Attendee{
+			UserId: int32(a.UserID), //nolint:gosec
+			Role:   string(a.Role),
+			Status: string(a.Status),
+		}
+	}
+	return result
+}
+
+// attendeeError maps app and storage errors of the attendee RPCs to gRPC statuses.
+func attendeeError(err error) error {
+	switch {
+	case errors.Is(err, app.ErrInvalidAttendee):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrNotInvitee):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, storage.ErrEventNotFound):
+		return status.Error(codes.NotFound, "requested event not found")
+	case errors.Is(err, storage.ErrAttendeeNotFound):
+		return status.Error(codes.NotFound, "user is not invited to the event")
+	case errors.Is(err, storage.ErrAttendeeExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Errorf(codes.Unavailable, fmt.Sprintf("%v", ErrInternal))
+	}
+}
+
+func (s *EventServer) InviteAttendee(
+	ctx context.Context,
+	req *calendarpb.InviteAttendeeRequest,
+) (*calendarpb.InviteAttendeeResponse, error) {
+	err := s.application.InviteAttendee(ctx, storage.Attendee{
+		EventID: int(req.EventId),
+		UserID:  int(req.UserId),
+		Role:    storage.AttendeeRole(req.Role),
+	})
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to invite attendee: %v", err))
+		return nil, attendeeError(err)
+	}
+	s.log(ctx).Info("attendee invited successfully")
+	return &calendarpb.InviteAttendeeResponse{Success: true}, nil
+}
+
+func (s *EventServer) RespondInvitation(
+	ctx context.Context,
+	req *calendarpb.RespondInvitationRequest,
+) (*calendarpb.RespondInvitationResponse, error) {
+	err := s.application.RespondInvitation(ctx, int(req.EventId), int(req.UserId), storage.RSVPStatus(req.Status))
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to respond to invitation: %v", err))
+		return nil, attendeeError(err)
+	}
+	s.log(ctx).Info("invitation answered successfully")
+	return &calendarpb.RespondInvitationResponse{Success: true}, nil
+}
+
+func (s *EventServer) RemoveAttendee(
+	ctx context.Context,
+	req *calendarpb.RemoveAttendeeRequest,
+) (*calendarpb.RemoveAttendeeResponse, error) {
+	if err := s.application.RemoveAttendee(ctx, int(req.EventId), int(req.UserId)); err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to remove attendee: %v", err))
+		return nil, attendeeError(err)
+	}
+	s.log(ctx).Info("attendee removed successfully")
+	return &calendarpb.RemoveAttendeeResponse{Success: true}, nil
+}
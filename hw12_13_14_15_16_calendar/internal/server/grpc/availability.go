@@ -0,0 +1,106 @@
+package calendargrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *EventServer) FindFreeSlots(
+	ctx context.Context,
+	req *calendarpb.FindFreeSlotsRequest,
+) (*calendarpb.FindFreeSlotsResponse, error) {
+	q, loc, err := availabilityQuery(req.UserIds, req.Clinic, req.From, req.To, req.TimeZone)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	hours := app.WorkingHours{Location: loc}
+	if req.WorkStart != "" || req.WorkEnd != "" {
+		if hours.Start, err = parseClock(req.WorkStart); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid workStart: %v", err)
+		}
+		if hours.End, err = parseClock(req.WorkEnd); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid workEnd: %v", err)
+		}
+	}
+
+	free, err := s.application.FindFreeSlots(ctx, q, time.Duration(req.SlotMinutes)*time.Minute, hours)
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to find free slots: %v", err))
+		return nil, availabilityError(err)
+	}
+	s.log(ctx).Info(fmt.Sprintf("found %d free intervals", len(free)))
+	return &calendarpb.FindFreeSlotsResponse{Free: toProtoIntervals(free, loc)}, nil
+}
+
+func (s *EventServer) FreeBusy(ctx context.Context, req *calendarpb.FreeBusyRequest) (*calendarpb.FreeBusyResponse, error) {
+	q, loc, err := availabilityQuery(req.UserIds, req.Clinic, req.From, req.To, req.TimeZone)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	busy, err := s.application.FreeBusy(ctx, q)
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to query free/busy: %v", err))
+		return nil, availabilityError(err)
+	}
+	s.log(ctx).Info(fmt.Sprintf("found %d busy intervals", len(busy)))
+	return &calendarpb.FreeBusyResponse{Busy: toProtoIntervals(busy, loc)}, nil
+}
+
+func availabilityQuery(
+	userIDs []int32,
+	clinic, from, to, timeZone string,
+) (app.AvailabilityQuery, *time.Location, error) {
+	q := app.AvailabilityQuery{Clinic: clinic}
+	for _, id := range userIDs {
+		q.UserIDs = append(q.UserIDs, int(id))
+	}
+	var err error
+	if q.From, err = time.Parse(time.RFC3339, from); err != nil {
+		return q, nil, fmt.Errorf("invalid from: %w", err)
+	}
+	if q.To, err = time.Parse(time.RFC3339, to); err != nil {
+		return q, nil, fmt.Errorf("invalid to: %w", err)
+	}
+	loc, err := storage.LoadLocation(timeZone)
+	return q, loc, err
+}
+
+// parseClock parses a local time of day "HH:MM" into an offset from midnight; "24:00" is
+// the following midnight.
+func parseClock(s string) (time.Duration, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || len(s) != len("15:04") {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("%q is not a time of day", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+func toProtoIntervals(intervals []app.Interval, loc *time.Location) []*calendarpb.TimeInterval {
+	result := make([]*calendarpb.TimeInterval, len(intervals))
+	for i, in := range intervals {
+		result[i] = &calendarpb.TimeInterval{
+			Start: in.Start.In(loc).Format(time.RFC3339),
+			End:   in.End.In(loc).Format(time.RFC3339),
+		}
+	}
+	return result
+}
+
+func availabilityError(err error) error {
+	if errors.Is(err, app.ErrInvalidAvailability) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Errorf(codes.Unavailable, fmt.Sprintf("%v", ErrInternal))
+}
@@ -0,0 +1,124 @@
+package calendargrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// batchItemErrors are shown to the client as they are; other item errors are hidden
+// behind ErrInternal.
+var batchItemErrors = []error{
+	app.ErrBatchAborted,
+	app.ErrInvalidEvent,
+	storage.ErrBatchDuplicate,
+	storage.ErrEventNotFound,
+	storage.ErrInvalidTimeZone,
+	ErrInvalidDate,
+	ErrEmptyInput,
+}
+
+// BatchCreateEvents creates all events atomically. Events that cannot be converted
+// fail the batch like events the application rejects.
+func (s *EventServer) BatchCreateEvents(
+	ctx context.Context,
+	req *calendarpb.BatchCreateEventsRequest,
+) (*calendarpb.BatchResponse, error) {
+	if len(req.Events) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "events missing")
+	}
+
+	events := make([]storage.Event, len(req.Events))
+	results := make([]app.BatchResult, len(req.Events))
+	for i, pe := range req.Events {
+		var err error
+		if events[i], err = FromProtoEvent(pe); err != nil {
+			results[i].Err = err
+		}
+	}
+	if app.BatchApplied(results) {
+		var err error
+		if results, err = s.application.BatchCreateEvents(ctx, events); err != nil {
+			s.log(ctx).Error(fmt.Sprintf("failed to create batch of %d events: %v", len(events), err))
+			return nil, batchError(err)
+		}
+	} else {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = app.ErrBatchAborted
+			}
+		}
+	}
+
+	resp := s.batchResponse(ctx, results)
+	s.log(ctx).Info(fmt.Sprintf("batch of %d events created: %t", len(events), resp.Applied))
+	return resp, nil
+}
+
+// BatchDeleteEvents deletes all events atomically.
+func (s *EventServer) BatchDeleteEvents(
+	ctx context.Context,
+	req *calendarpb.BatchDeleteEventsRequest,
+) (*calendarpb.BatchResponse, error) {
+	if len(req.Ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ids missing")
+	}
+
+	ids := make([]int, len(req.Ids))
+	for i, id := range req.Ids {
+		ids[i] = int(id)
+	}
+	results, err := s.application.BatchDeleteEvents(ctx, ids)
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to delete batch of %d events: %v", len(ids), err))
+		return nil, batchError(err)
+	}
+
+	resp := s.batchResponse(ctx, results)
+	s.log(ctx).Info(fmt.Sprintf("batch of %d events deleted: %t", len(ids), resp.Applied))
+	return resp, nil
+}
+
+func (s *EventServer) batchResponse(ctx context.Context, results []app.BatchResult) *calendarpb.BatchResponse {
+	resp := &calendarpb.BatchResponse{
+		Applied: app.BatchApplied(results),
+		Results: make([]*calendarpb.BatchResult, len(results)),
+	}
+	for i, r := range results {
+		item := &calendarpb.BatchResult{
+			Index:   int32(i),    //nolint:gosec
+			Id:      int32(r.ID), //nolint:gosec
+			Success: r.Err == nil,
+		}
+		if r.Err != nil {
+			item.Error = batchItemError(r.Err).Error()
+			if !errors.Is(r.Err, app.ErrBatchAborted) {
+				s.log(ctx).Error(fmt.Sprintf("batch item %d failed: %v", i, r.Err))
+			}
+		}
+		resp.Results[i] = item
+	}
+	return resp
+}
+
+func batchItemError(err error) error {
+	for _, known := range batchItemErrors {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+	return ErrInternal
+}
+
+func batchError(err error) error {
+	if errors.Is(err, app.ErrInvalidBatch) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Errorf(codes.Unavailable, fmt.Sprintf("%v", ErrInternal))
+}
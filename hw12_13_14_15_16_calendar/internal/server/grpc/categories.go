@@ -0,0 +1,109 @@
+package calendargrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *EventServer) CreateCategory(
+	ctx context.Context,
+	req *calendarpb.CreateCategoryRequest,
+) (*calendarpb.Category, error) {
+	if req.Category == nil {
+		return nil, status.Error(codes.InvalidArgument, "category data missing")
+	}
+	category, err := s.application.CreateCategory(ctx, fromProtoCategory(req.Category))
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to create category %q: %v", req.Category.Name, err))
+		return nil, categoryError(err)
+	}
+	s.log(ctx).Info(fmt.Sprintf("created category %d", category.ID))
+	return toProtoCategory(category), nil
+}
+
+func (s *EventServer) ListCategories(
+	ctx context.Context,
+	_ *calendarpb.ListCategoriesRequest,
+) (*calendarpb.ListCategoriesResponse, error) {
+	categories, err := s.application.ListCategories(ctx)
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to list categories: %v", err))
+		return nil, status.Error(codes.Internal, "failed to list categories")
+	}
+	resp := &calendarpb.ListCategoriesResponse{Categories: make([]*calendarpb.Category, len(categories))}
+	for i, category := range categories {
+		resp.Categories[i] = toProtoCategory(category)
+	}
+	return resp, nil
+}
+
+func (s *EventServer) UpdateCategory(
+	ctx context.Context,
+	req *calendarpb.UpdateCategoryRequest,
+) (*calendarpb.Category, error) {
+	if req.Category == nil {
+		return nil, status.Error(codes.InvalidArgument, "category data missing")
+	}
+	category, err := s.application.UpdateCategory(ctx, fromProtoCategory(req.Category))
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to update category %d: %v", req.Category.Id, err))
+		return nil, categoryError(err)
+	}
+	s.log(ctx).Info(fmt.Sprintf("updated category %d", category.ID))
+	return toProtoCategory(category), nil
+}
+
+func (s *EventServer) DeleteCategory(
+	ctx context.Context,
+	req *calendarpb.DeleteCategoryRequest,
+) (*calendarpb.DeleteCategoryResponse, error) {
+	if err := s.application.DeleteCategory(ctx, int(req.Id)); err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to delete category %d: %v", req.Id, err))
+		return nil, categoryError(err)
+	}
+	s.log(ctx).Info(fmt.Sprintf("deleted category %d", req.Id))
+	return &calendarpb.DeleteCategoryResponse{Success: true}, nil
+}
+
+func (s *EventServer) SetEventTags(
+	ctx context.Context,
+	req *calendarpb.SetEventTagsRequest,
+) (*calendarpb.SetEventTagsResponse, error) {
+	event, err := s.application.SetEventTags(ctx, int(req.EventId), req.Tags)
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to tag event %d: %v", req.EventId, err))
+		return nil, categoryError(err)
+	}
+	s.log(ctx).Info(fmt.Sprintf("tagged event %d with %v", req.EventId, event.Tags))
+	return &calendarpb.SetEventTagsResponse{Event: toProtoEvent(event)}, nil
+}
+
+func fromProtoCategory(pc *calendarpb.Category) storage.Category {
+	return storage.Category{ID: int(pc.Id), Name: pc.Name, Color: pc.Color}
+}
+
+func toProtoCategory(c storage.Category) *calendarpb.Category {
+	return &calendarpb.Category{
+		Id:    int32(c.ID), //nolint:gosec
+		Name:  c.Name,
+		Color: c.Color,
+	}
+}
+
+func categoryError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrInvalidCategory):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, storage.ErrCategoryExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, storage.ErrCategoryNotFound), errors.Is(err, storage.ErrEventNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Errorf(codes.Unavailable, fmt.Sprintf("%v", ErrInternal))
+}
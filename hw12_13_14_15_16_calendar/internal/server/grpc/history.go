@@ -0,0 +1,77 @@
+package calendargrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *EventServer) GetEventHistory(
+	ctx context.Context,
+	req *calendarpb.GetEventHistoryRequest,
+) (*calendarpb.GetEventHistoryResponse, error) {
+	entries, err := s.application.GetEventHistory(ctx, int(req.EventId))
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to get history of event %d: %v", req.EventId, err))
+		return nil, historyError(err)
+	}
+
+	resp := &calendarpb.GetEventHistoryResponse{Entries: make([]*calendarpb.HistoryEntry, len(entries))}
+	for i, entry := range entries {
+		resp.Entries[i] = toProtoHistoryEntry(entry)
+	}
+	s.log(ctx).Info(fmt.Sprintf("found %d history entries of event %d", len(entries), req.EventId))
+	return resp, nil
+}
+
+func (s *EventServer) RestoreEvent(
+	ctx context.Context,
+	req *calendarpb.RestoreEventRequest,
+) (*calendarpb.RestoreEventResponse, error) {
+	event, err := s.application.RestoreEvent(ctx, int(req.Id))
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to restore event %d: %v", req.Id, err))
+		return nil, historyError(err)
+	}
+	s.log(ctx).Info(fmt.Sprintf("restored event %d", req.Id))
+	return &calendarpb.RestoreEventResponse{Event: toProtoEvent(event)}, nil
+}
+
+func toProtoHistoryEntry(entry storage.HistoryEntry) *calendarpb.HistoryEntry {
+	pe := &calendarpb.HistoryEntry{
+		Id:     int32(entry.ID), //nolint:gosec
+		Action: string(entry.Action),
+		Actor:  entry.Actor,
+		Time:   entry.At.Format(time.RFC3339),
+	}
+	if entry.ActorUserID != nil {
+		pe.ActorUserId = int32(*entry.ActorUserID) //nolint:gosec
+	}
+	if entry.Before != nil {
+		pe.Before = toProtoEvent(*entry.Before)
+	}
+	if entry.After != nil {
+		pe.After = toProtoEvent(*entry.After)
+	}
+	for _, c := range entry.Changes() {
+		pe.Changes = append(pe.Changes, &calendarpb.FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+	}
+	return pe
+}
+
+func historyError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrEventNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, app.ErrNotRestorable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Errorf(codes.Unavailable, fmt.Sprintf("%v", ErrInternal))
+}
@@ -0,0 +1,108 @@
+package calendargrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/ical"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *EventServer) ExportICS(ctx context.Context, req *calendarpb.ExportICSRequest) (*httpbody.HttpBody, error) {
+	filter := storage.Filter{Period: storage.PeriodAll}
+	var err error
+	if filter.From, err = parseRangeTime(req.From); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+	}
+	if filter.To, err = parseRangeTime(req.To); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+	}
+	if !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+	if req.UserId != 0 {
+		filter.UserIDs = []int{int(req.UserId)}
+	}
+
+	events, err := s.application.ListEvents(ctx, filter)
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to list events for export: %v", err))
+		return nil, status.Error(codes.Internal, "failed to export events")
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, events); err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to encode calendar: %v", err))
+		return nil, status.Error(codes.Internal, "failed to export events")
+	}
+	s.log(ctx).Info(fmt.Sprintf("exported %d events", len(events)))
+	return &httpbody.HttpBody{ContentType: ical.ContentType, Data: buf.Bytes()}, nil
+}
+
+// parseRangeTime parses an optional RFC3339 bound of an export range.
+func parseRangeTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// ImportICS creates the events of a calendar document one by one. Invalid VEVENTs and
+// events the application rejects are reported per VEVENT; the rest are still created.
+func (s *EventServer) ImportICS(
+	ctx context.Context,
+	req *calendarpb.ImportICSRequest,
+) (*calendarpb.ImportICSResponse, error) {
+	if len(req.GetBody().GetData()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "calendar data missing")
+	}
+
+	events, decodeErrs, err := ical.Decode(bytes.NewReader(req.Body.Data))
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to decode calendar: %v", err))
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	resp := &calendarpb.ImportICSResponse{}
+	for _, err := range decodeErrs {
+		var eventErr *ical.EventError
+		if errors.As(err, &eventErr) {
+			resp.Errors = append(resp.Errors, importError(eventErr.Index, eventErr.UID, eventErr.Err))
+		}
+	}
+	for _, ve := range events {
+		event := ve.Event
+		if req.UserId != 0 {
+			uid := int(req.UserId)
+			event.UserID = &uid
+		}
+		if _, err := s.application.CreateEvent(ctx, event); err != nil {
+			s.log(ctx).Error(fmt.Sprintf("failed to import event %q: %v", ve.UID, err))
+			if !errors.Is(err, app.ErrInvalidEvent) {
+				err = ErrInternal
+			}
+			resp.Errors = append(resp.Errors, importError(ve.Index, ve.UID, err))
+			continue
+		}
+		resp.Created++
+	}
+
+	s.log(ctx).Info(fmt.Sprintf("imported %d events, %d rejected", resp.Created, len(resp.Errors)))
+	return resp, nil
+}
+
+func importError(index int, uid string, err error) *calendarpb.ImportICSError {
+	return &calendarpb.ImportICSError{
+		Index: int32(index), //nolint:gosec
+		Uid:   uid,
+		Error: err.Error(),
+	}
+}
@@ -0,0 +1,35 @@
+package calendargrpc
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+)
+
+// Limits holds the per-call limits that can be changed while the server is running
+// (e.g. on SIGHUP). Message size limits are fixed when the server is created.
+type Limits struct {
+	timeout atomic.Int64
+	limiter *ClientRateLimiter
+}
+
+// NewLimits creates Limits from the gRPC config.
+func NewLimits(cfg config.GRPCConfig) *Limits {
+	l := &Limits{limiter: NewClientRateLimiter(cfg.RateLimit.RPS, cfg.RateLimit.Burst)}
+	l.limiter.SetTrustedProxies(cfg.RateLimit.TrustedProxies)
+	l.timeout.Store(int64(cfg.DefaultTimeout))
+	return l
+}
+
+// Update applies the default timeout, rate limit and trusted proxies of cfg to subsequent calls.
+func (l *Limits) Update(cfg config.GRPCConfig) {
+	l.timeout.Store(int64(cfg.DefaultTimeout))
+	l.limiter.SetLimit(cfg.RateLimit.RPS, cfg.RateLimit.Burst)
+	l.limiter.SetTrustedProxies(cfg.RateLimit.TrustedProxies)
+}
+
+// DefaultTimeout returns the deadline applied to calls that come without one.
+func (l *Limits) DefaultTimeout() time.Duration {
+	return time.Duration(l.timeout.Load())
+}
@@ -3,12 +3,93 @@ package calendargrpc
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/metrics"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/requestid"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/session"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tlsconfig"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
+var (
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Number of handled gRPC requests by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of gRPC requests by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+// RequestIDUnaryInterceptor returns a unary interceptor that takes the request ID from
+// the incoming metadata (or generates a new one if it is missing or invalid), stores it
+// in the context and echoes it back in the response header.
+func RequestIDUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(requestid.MetadataKey); len(values) > 0 {
+				id = values[0]
+			}
+		}
+		id = requestid.FromClient(id)
+
+		ctx = requestid.NewContext(ctx, id)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id))
+
+		return handler(ctx, req)
+	}
+}
+
+// SessionUnaryInterceptor returns a unary interceptor that starts the request's session at
+// the token from the incoming metadata and returns the session's token, advanced by the
+// request's writes, in the response header.
+func SessionUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		var token string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(session.MetadataKey); len(values) > 0 {
+				token = values[0]
+			}
+		}
+
+		sess := session.New(token)
+		resp, err := handler(session.NewContext(ctx, sess), req)
+		if token := sess.Token(); token != "" {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(session.MetadataKey, token))
+		}
+		return resp, err
+	}
+}
+
 // LoggingUnaryInterceptor returns a unary interceptor that logs details about the request.
 func LoggingUnaryInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
 	return func(
@@ -18,17 +99,244 @@ func LoggingUnaryInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
 		start := time.Now()
-		logger.Info(fmt.Sprintf("gRPC call start: %s", info.FullMethod))
+		log := requestLogger(ctx, logger)
+		if p, ok := peer.FromContext(ctx); ok {
+			log = log.With("peer", p.Addr.String())
+		}
+		if identity, ok := tlsconfig.PeerIdentity(ctx); ok {
+			log = log.With("client", identity)
+		}
+		log.Info(fmt.Sprintf("gRPC call start: %s | size: %d", info.FullMethod, payloadSize(req)))
 
 		resp, err := handler(ctx, req)
 
 		duration := time.Since(start)
+		code := status.Code(err)
 		if err != nil {
-			logger.Info(fmt.Sprintf("gRPC call error: %s | duration: %s | error: %v", info.FullMethod, duration, err))
+			log.Info(fmt.Sprintf("gRPC call error: %s | status: %s | duration: %s | error: %v",
+				info.FullMethod, code, duration, err))
 		} else {
-			logger.Info(fmt.Sprintf("gRPC call end: %s | duration: %s", info.FullMethod, duration))
+			log.Info(fmt.Sprintf("gRPC call end: %s | status: %s | size: %d | duration: %s",
+				info.FullMethod, code, payloadSize(resp), duration))
 		}
 
 		return resp, err
 	}
 }
+
+// MetricsUnaryInterceptor returns a unary interceptor that records per-method request
+// counts and latency histograms.
+func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		grpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		grpcRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+
+		return resp, err
+	}
+}
+
+// RecoveryUnaryInterceptor returns a unary interceptor that turns a panic in a handler
+// into a codes.Internal error instead of crashing the server.
+func RecoveryUnaryInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				requestLogger(ctx, logger).Error(fmt.Sprintf("gRPC call panic: %s | panic: %v\n%s",
+					info.FullMethod, r, debug.Stack()))
+				resp, err = nil, status.Errorf(codes.Internal, "%v", ErrInternal)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthUnaryInterceptor returns a unary interceptor that rejects calls without valid
+// credentials with codes.Unauthenticated and stores the caller identity in the context.
+// Methods listed as public are let through untouched.
+func AuthUnaryInterceptor(authn *auth.Authenticator, logger *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if authn.IsPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		identity, err := authn.Authenticate(ctx)
+		if err != nil {
+			requestLogger(ctx, logger).Error(fmt.Sprintf("gRPC call unauthenticated: %s | error: %v", info.FullMethod, err))
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(auth.NewContext(ctx, identity), req)
+	}
+}
+
+// TimeoutUnaryInterceptor returns a unary interceptor that applies the default deadline
+// returned by timeout to calls whose client did not set one. A zero timeout disables it.
+func TimeoutUnaryInterceptor(timeout func() time.Duration) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		timeout := timeout()
+		if _, ok := ctx.Deadline(); timeout <= 0 || ok {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitUnaryInterceptor returns a unary interceptor that rejects calls with
+// codes.ResourceExhausted once the client's token bucket is empty.
+func RateLimitUnaryInterceptor(limiter *ClientRateLimiter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !limiter.Allow(limiter.clientKey(ctx)) {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// serverStream replaces the context of a wrapped server stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// RequestIDStreamInterceptor is the streaming counterpart of RequestIDUnaryInterceptor.
+func RequestIDStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(requestid.MetadataKey); len(values) > 0 {
+				id = values[0]
+			}
+		}
+		id = requestid.FromClient(id)
+
+		_ = ss.SetHeader(metadata.Pairs(requestid.MetadataKey, id))
+		return handler(srv, &serverStream{ServerStream: ss, ctx: requestid.NewContext(ctx, id)})
+	}
+}
+
+// LoggingStreamInterceptor logs the start and the end of a stream.
+func LoggingStreamInterceptor(logger *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		log := requestLogger(ss.Context(), logger)
+		if p, ok := peer.FromContext(ss.Context()); ok {
+			log = log.With("peer", p.Addr.String())
+		}
+		if identity, ok := tlsconfig.PeerIdentity(ss.Context()); ok {
+			log = log.With("client", identity)
+		}
+		log.Info("gRPC stream start: " + info.FullMethod)
+
+		err := handler(srv, ss)
+
+		log.Info(fmt.Sprintf("gRPC stream end: %s | status: %s | duration: %s",
+			info.FullMethod, status.Code(err), time.Since(start)))
+		return err
+	}
+}
+
+// MetricsStreamInterceptor counts streams by method and status code. Stream durations
+// are left out of the latency histogram, which is meant for unary calls.
+func MetricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		grpcRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		return err
+	}
+}
+
+// RecoveryStreamInterceptor is the streaming counterpart of RecoveryUnaryInterceptor.
+func RecoveryStreamInterceptor(logger *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				requestLogger(ss.Context(), logger).Error(fmt.Sprintf("gRPC stream panic: %s | panic: %v\n%s",
+					info.FullMethod, r, debug.Stack()))
+				err = status.Errorf(codes.Internal, "%v", ErrInternal)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// AuthStreamInterceptor is the streaming counterpart of AuthUnaryInterceptor.
+func AuthStreamInterceptor(authn *auth.Authenticator, logger *logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if authn.IsPublic(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx := ss.Context()
+		identity, err := authn.Authenticate(ctx)
+		if err != nil {
+			requestLogger(ctx, logger).Error(fmt.Sprintf("gRPC stream unauthenticated: %s | error: %v", info.FullMethod, err))
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: auth.NewContext(ctx, identity)})
+	}
+}
+
+// RateLimitStreamInterceptor charges one token for opening a stream.
+func RateLimitStreamInterceptor(limiter *ClientRateLimiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !limiter.Allow(limiter.clientKey(ss.Context())) {
+			return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", info.FullMethod)
+		}
+		return handler(srv, ss)
+	}
+}
+
+// requestLogger returns a logger tagged with the request ID from ctx, if any.
+func requestLogger(ctx context.Context, log *logger.Logger) *logger.Logger {
+	if id := requestid.FromContext(ctx); id != "" {
+		return log.With("request_id", id)
+	}
+	return log
+}
+
+func payloadSize(msg interface{}) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
+}
@@ -0,0 +1,245 @@
+package calendargrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/requestid"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/session"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestIDUnaryInterceptor(t *testing.T) {
+	interceptor := RequestIDUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/calendarGRPC.CalendarService/HealthCheck"}
+
+	var seen string
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		seen = requestid.FromContext(ctx)
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.MetadataKey, "from-gateway"))
+	_, err := interceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "from-gateway", seen)
+
+	_, err = interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	require.NotEmpty(t, seen)
+	require.NotEqual(t, "from-gateway", seen)
+
+	tooLong := strings.Repeat("x", requestid.MaxLength+1)
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.MetadataKey, tooLong))
+	_, err = interceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+	require.Len(t, seen, 32)
+}
+
+func TestSessionUnaryInterceptor(t *testing.T) {
+	interceptor := SessionUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/calendarGRPC.CalendarService/UpdateEvent"}
+
+	var seen string
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		sess := session.FromContext(ctx)
+		require.NotNil(t, sess)
+		seen = sess.Token()
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(session.MetadataKey, "0/16B3748"))
+	_, err := interceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "0/16B3748", seen)
+
+	_, err = interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	require.Empty(t, seen)
+}
+
+func TestMetricsUnaryInterceptor(t *testing.T) {
+	interceptor := MetricsUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/calendarGRPC.CalendarService/GetEvent"}
+
+	before := testutil.ToFloat64(grpcRequests.WithLabelValues(info.FullMethod, codes.NotFound.String()))
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "requested event not found")
+	})
+	require.Error(t, err)
+
+	after := testutil.ToFloat64(grpcRequests.WithLabelValues(info.FullMethod, codes.NotFound.String()))
+	require.Equal(t, before+1, after)
+}
+
+func TestRecoveryUnaryInterceptor(t *testing.T) {
+	interceptor := RecoveryUnaryInterceptor(logger.New("none"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/calendarGRPC.CalendarService/CreateEvent"}
+
+	resp, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	require.Nil(t, resp)
+	require.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestTimeoutUnaryInterceptor(t *testing.T) {
+	interceptor := TimeoutUnaryInterceptor(func() time.Duration { return time.Second })
+	info := &grpc.UnaryServerInfo{FullMethod: "/calendarGRPC.CalendarService/ListEventsDay"}
+
+	var deadline time.Time
+	var hasDeadline bool
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		deadline, hasDeadline = ctx.Deadline()
+		return nil, nil
+	}
+
+	_, err := interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	require.True(t, hasDeadline)
+	require.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+	clientCtx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	_, err = interceptor(clientCtx, nil, info, handler)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Second, "client deadline must be kept")
+}
+
+func TestRateLimitUnaryInterceptor(t *testing.T) {
+	limiter := NewClientRateLimiter(1, 2)
+	limiter.SetTrustedProxies([]string{"192.0.2.10", "198.51.100.0/24", "bogus"})
+	interceptor := RateLimitUnaryInterceptor(limiter)
+	info := &grpc.UnaryServerInfo{FullMethod: "/calendarGRPC.CalendarService/HealthCheck"}
+	handler := func(context.Context, interface{}) (interface{}, error) { return nil, nil }
+
+	client := func(addr string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 5555}})
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := interceptor(client("10.0.0.1"), nil, info, handler)
+		require.NoError(t, err)
+	}
+	_, err := interceptor(client("10.0.0.1"), nil, info, handler)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = interceptor(client("10.0.0.2"), nil, info, handler)
+	require.NoError(t, err, "other clients have their own bucket")
+
+	spoofed := metadata.NewIncomingContext(client("10.0.0.1"), metadata.Pairs("x-forwarded-for", "203.0.113.7"))
+	_, err = interceptor(spoofed, nil, info, handler)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), "untrusted peers cannot pick their bucket")
+
+	for _, tc := range []struct {
+		proxy, forwardedFor, key string
+	}{
+		{"127.0.0.1", "203.0.113.7", "203.0.113.7"},
+		{"192.0.2.10", "203.0.113.8, 192.0.2.99", "192.0.2.99"},
+		{"198.51.100.5", "203.0.113.9", "203.0.113.9"},
+		{"127.0.0.1", "203.0.113.10, 198.51.100.7", "203.0.113.10"},
+		{"127.0.0.1", "127.0.0.1", "127.0.0.1"},
+	} {
+		forwarded := metadata.NewIncomingContext(client(tc.proxy), metadata.Pairs("x-forwarded-for", tc.forwardedFor))
+		require.Equal(t, tc.key, limiter.clientKey(forwarded), "%s via %s", tc.forwardedFor, tc.proxy)
+		_, err = interceptor(forwarded, nil, info, handler)
+		require.NoError(t, err, "callers behind %s are keyed by the forwarded address", tc.proxy)
+	}
+
+	// The gateway appends the address of its client to what the client sent, so a prefix
+	// chosen by the client must not pick the bucket.
+	for _, prefix := range []string{"", "203.0.113.50, ", "198.51.100.1, 203.0.113.51, "} {
+		forwarded := metadata.NewIncomingContext(client("127.0.0.1"),
+			metadata.Pairs("x-forwarded-for", prefix+"10.0.0.1"))
+		require.Equal(t, "10.0.0.1", limiter.clientKey(forwarded))
+		_, err = interceptor(forwarded, nil, info, handler)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err), "prefix %q", prefix)
+	}
+}
+
+func TestLimits_Update(t *testing.T) {
+	limits := NewLimits(config.GRPCConfig{
+		DefaultTimeout: time.Second,
+		RateLimit:      config.RateLimitConfig{RPS: 1, Burst: 1},
+	})
+	require.True(t, limits.limiter.Allow("10.0.0.1"))
+	require.False(t, limits.limiter.Allow("10.0.0.1"))
+
+	limits.Update(config.GRPCConfig{DefaultTimeout: time.Minute})
+	require.Equal(t, time.Minute, limits.DefaultTimeout())
+	require.True(t, limits.limiter.Allow("10.0.0.1"), "rate limiting is disabled by the new config")
+
+	limits.Update(config.GRPCConfig{RateLimit: config.RateLimitConfig{RPS: 1, Burst: 3}})
+	for i := 0; i < 3; i++ {
+		require.True(t, limits.limiter.Allow("10.0.0.2"))
+	}
+	require.False(t, limits.limiter.Allow("10.0.0.2"))
+}
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	authn, err := auth.New(config.AuthConf{
+		APIKeys:       []config.APIKeyConf{{Key: "s3cret", Subject: "front-desk"}},
+		PublicMethods: []string{"HealthCheck"},
+	})
+	require.NoError(t, err)
+	interceptor := AuthUnaryInterceptor(authn, logger.New("none"))
+
+	var identity auth.Identity
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		identity, _ = auth.FromContext(ctx)
+		return nil, nil
+	}
+
+	public := &grpc.UnaryServerInfo{FullMethod: "/calendarGRPC.CalendarService/HealthCheck"}
+	_, err = interceptor(context.Background(), nil, public, handler)
+	require.NoError(t, err)
+
+	private := &grpc.UnaryServerInfo{FullMethod: "/calendarGRPC.CalendarService/CreateEvent"}
+	_, err = interceptor(context.Background(), nil, private, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(auth.APIKeyMetadata, "s3cret"))
+	_, err = interceptor(ctx, nil, private, handler)
+	require.NoError(t, err)
+	require.Equal(t, "front-desk", identity.Subject)
+}
+
+// fakeStream is a grpc.ServerStream that only carries a context.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeStream) Context() context.Context { return s.ctx }
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	authn, err := auth.New(config.AuthConf{APIKeys: []config.APIKeyConf{{Key: "s3cret", Subject: "front-desk"}}})
+	require.NoError(t, err)
+	interceptor := AuthStreamInterceptor(authn, logger.New("none"))
+
+	var identity auth.Identity
+	handler := func(_ interface{}, ss grpc.ServerStream) error {
+		identity, _ = auth.FromContext(ss.Context())
+		return nil
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/calendarGRPC.CalendarService/WatchEvents", IsServerStream: true}
+
+	err = interceptor(nil, fakeStream{ctx: context.Background()}, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(auth.APIKeyMetadata, "s3cret"))
+	require.NoError(t, interceptor(nil, fakeStream{ctx: ctx}, info, handler))
+	require.Equal(t, "front-desk", identity.Subject)
+}
@@ -0,0 +1,174 @@
+package calendargrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// idleLimiterTTL is how long a client's bucket is kept after its last call.
+const idleLimiterTTL = 10 * time.Minute
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// ClientRateLimiter keeps one token bucket per client.
+type ClientRateLimiter struct {
+	mu        sync.Mutex
+	rps       rate.Limit
+	burst     int
+	trusted   []*net.IPNet // proxies whose x-forwarded-for is believed, besides loopback
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+// NewClientRateLimiter creates a limiter allowing rps calls per second with the given burst
+// for every client. A non-positive rps disables limiting.
+func NewClientRateLimiter(rps float64, burst int) *ClientRateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &ClientRateLimiter{
+		rps:     rate.Limit(rps),
+		burst:   burst,
+		clients: make(map[string]*clientLimiter),
+	}
+}
+
+// SetLimit changes the rate and burst for new and existing clients.
+func (l *ClientRateLimiter) SetLimit(rps float64, burst int) {
+	if burst <= 0 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rps, l.burst = rate.Limit(rps), burst
+	for _, c := range l.clients {
+		c.limiter.SetLimit(l.rps)
+		c.limiter.SetBurst(l.burst)
+	}
+}
+
+// Allow reports whether the client identified by key may make a call now.
+func (l *ClientRateLimiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rps <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > idleLimiterTTL {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > idleLimiterTTL {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.limiter.AllowN(now, 1)
+}
+
+// SetTrustedProxies replaces the proxies, given as IPs or CIDRs, whose x-forwarded-for
+// metadata names the client. Entries that are neither are ignored, so they trust nothing.
+func (l *ClientRateLimiter) SetTrustedProxies(proxies []string) {
+	var trusted []*net.IPNet
+	for _, proxy := range proxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			if ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			trusted = append(trusted, network)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.trusted = trusted
+}
+
+// clientKey identifies the caller by the host of the gRPC peer. Only when the peer is
+// the HTTP gateway, which dials over loopback, or a trusted proxy is x-forwarded-for
+// used instead, and then only the hops appended by trusted proxies: walking it from the
+// right, the first address that is not trusted is the client. The entries left of it
+// come from the client, which could change them to get a fresh bucket with every call.
+func (l *ClientRateLimiter) clientKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+	host := p.Addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if !l.trusts(net.ParseIP(host)) {
+		return host
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var hops []string
+	for _, value := range md.Get("x-forwarded-for") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !l.trusts(net.ParseIP(hops[i])) {
+			return hops[i]
+		}
+	}
+	if len(hops) > 0 {
+		return hops[0] // all hops are trusted, so the first one made the call
+	}
+	return host
+}
+
+// trusts reports whether ip is the loopback address or one of the trusted proxies.
+func (l *ClientRateLimiter) trusts(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	if l == nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, network := range l.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package calendargrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *EventServer) SearchEvents(
+	ctx context.Context,
+	req *calendarpb.SearchEventsRequest,
+) (*calendarpb.SearchEventsResponse, error) {
+	filter := storage.Filter{Period: storage.PeriodAll}
+	var err error
+	if filter.From, err = parseRangeTime(req.From); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+	}
+	if filter.To, err = parseRangeTime(req.To); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+	}
+	if !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	results, err := s.application.SearchEvents(ctx, req.Query, filter, int(req.Limit))
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to search events for %q: %v", req.Query, err))
+		if errors.Is(err, storage.ErrInvalidSearch) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, status.Error(codes.Internal, "failed to search events")
+	}
+
+	resp := &calendarpb.SearchEventsResponse{Results: make([]*calendarpb.SearchResult, len(results))}
+	for i, r := range results {
+		resp.Results[i] = &calendarpb.SearchResult{
+			Event:                toProtoEvent(r.Event),
+			Rank:                 r.Rank,
+			TitleHighlight:       r.Title,
+			DescriptionHighlight: r.Description,
+		}
+	}
+	s.log(ctx).Info(fmt.Sprintf("found %d events for %q", len(results), req.Query))
+	return resp, nil
+}
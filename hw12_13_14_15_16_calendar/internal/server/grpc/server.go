@@ -8,8 +8,12 @@ import (
 
 	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/validation"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -32,11 +36,62 @@ func NewEventServer(application *app.App, log *logger.Logger) *EventServer {
 	return &EventServer{application: application, logger: log}
 }
 
-// NewGRPCServer creates a grpc.Server with logging interceptor and registers the EventServer.
-func NewGRPCServer(app *app.App, log *logger.Logger) *grpc.Server {
+// NewGRPCServer creates a grpc.Server with tracing, request ID, logging, metrics, panic recovery,
+// rate limiting, authentication and default deadline interceptors (streams get all but the
+// deadline) and registers the EventServer.
+// A nil authenticator disables authentication; nil limits are built from cfg. Extra options
+// (e.g. transport credentials) are appended as is.
+func NewGRPCServer(
+	app *app.App,
+	log *logger.Logger,
+	cfg config.GRPCConfig,
+	limits *Limits,
+	authn *auth.Authenticator,
+	extra ...grpc.ServerOption,
+) *grpc.Server {
+	if limits == nil {
+		limits = NewLimits(cfg)
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		RequestIDUnaryInterceptor(),
+		SessionUnaryInterceptor(),
+		LoggingUnaryInterceptor(log),
+		MetricsUnaryInterceptor(),
+		RecoveryUnaryInterceptor(log),
+		// Keyed by the peer, not the caller identity, the limiter comes before
+		// authentication so that it also throttles credential guessing.
+		RateLimitUnaryInterceptor(limits.limiter),
+	}
+	if authn != nil {
+		interceptors = append(interceptors, AuthUnaryInterceptor(authn, log))
+	}
+	interceptors = append(interceptors, TimeoutUnaryInterceptor(limits.DefaultTimeout))
+
+	// Streams are long-lived, so they get no default deadline.
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		RequestIDStreamInterceptor(),
+		LoggingStreamInterceptor(log),
+		MetricsStreamInterceptor(),
+		RecoveryStreamInterceptor(log),
+		RateLimitStreamInterceptor(limits.limiter),
+	}
+	if authn != nil {
+		streamInterceptors = append(streamInterceptors, AuthStreamInterceptor(authn, log))
+	}
+
 	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(LoggingUnaryInterceptor(log)),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	if cfg.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize))
 	}
+	if cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(cfg.MaxSendMsgSize))
+	}
+	opts = append(opts, extra...)
 	grpcServer := grpc.NewServer(opts...)
 
 	eventServer := NewEventServer(app, log)
@@ -47,6 +102,11 @@ func NewGRPCServer(app *app.App, log *logger.Logger) *grpc.Server {
 
 // --- Helpers ---
 
+// log returns the server logger tagged with the request ID of the current call.
+func (s *EventServer) log(ctx context.Context) *logger.Logger {
+	return requestLogger(ctx, s.logger)
+}
+
 func parseTimePtr(s string) *time.Time {
 	if s == "" {
 		return nil
@@ -65,28 +125,34 @@ func formatTimePtr(t *time.Time) string {
 	return t.Format(time.RFC3339)
 }
 
-func fromProtoEvent(pe *calendarpb.Event) (storage.Event, error) {
+// FromProtoEvent converts an API event, parsing its RFC3339 times. Times that do not
+// parse are reported as validation.Errors wrapping ErrInvalidDate.
+func FromProtoEvent(pe *calendarpb.Event) (storage.Event, error) {
 	if pe == nil {
 		// return storage.Event{}, fmt.Errorf("event is nil")
 		return storage.Event{}, fmt.Errorf("%w: event is nil", ErrEmptyInput)
 	}
 
+	var errs validation.Errors
 	start := parseTimePtr(pe.Start)
 	if start == nil {
-		return storage.Event{}, fmt.Errorf(
-			"%w: expected RFC3339, got %q", ErrInvalidDate, pe.Start,
-		)
+		errs = append(errs, validation.FieldError{
+			Field: "Start", Err: fmt.Errorf("%w: expected RFC3339, got %q", ErrInvalidDate, pe.Start),
+		})
 	}
 
 	var end *time.Time
 	if pe.End != "" {
 		end = parseTimePtr(pe.End)
 		if end == nil {
-			return storage.Event{}, fmt.Errorf(
-				"%w: expected RFC3339, got %q", ErrInvalidDate, pe.End,
-			)
+			errs = append(errs, validation.FieldError{
+				Field: "End", Err: fmt.Errorf("%w: expected RFC3339, got %q", ErrInvalidDate, pe.End),
+			})
 		}
 	}
+	if len(errs) > 0 {
+		return storage.Event{}, errs
+	}
 
 	return storage.Event{
 			ID:          int(pe.Id),
@@ -94,13 +160,9 @@ func fromProtoEvent(pe *calendarpb.Event) (storage.Event, error) {
 			Description: pe.Description,
 			Start:       start,
 			End:         end,
-			AllDay: func() float64 {
-				if pe.AllDay {
-					return 1
-				}
-				return 0
-			}(),
-			Clinic: &pe.Clinic,
+			AllDay:      pe.AllDay,
+			TimeZone:    pe.TimeZone,
+			Clinic:      &pe.Clinic,
 			UserID: func() *int {
 				if pe.UserId == 0 {
 					return nil
@@ -113,14 +175,25 @@ func fromProtoEvent(pe *calendarpb.Event) (storage.Event, error) {
 		nil
 }
 
+// toProtoEvent converts an event for the API, rendering its times in the event's zone.
 func toProtoEvent(ev storage.Event) *calendarpb.Event {
+	loc := ev.Location()
+	inZone := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		local := t.In(loc)
+		return &local
+	}
+
 	return &calendarpb.Event{
 		Id:          int32(ev.ID), //nolint:gosec
 		Title:       ev.Title,
 		Description: ev.Description,
-		Start:       formatTimePtr(ev.Start),
-		End:         formatTimePtr(ev.End),
-		AllDay:      ev.AllDay != 0,
+		Start:       formatTimePtr(inZone(ev.Start)),
+		End:         formatTimePtr(inZone(ev.End)),
+		AllDay:      ev.AllDay,
+		TimeZone:    loc.String(),
 		Clinic: func() string {
 			if ev.Clinic != nil {
 				return *ev.Clinic
@@ -139,6 +212,9 @@ func toProtoEvent(ev storage.Event) *calendarpb.Event {
 			}
 			return ""
 		}(),
+		Attendees: toProtoAttendees(ev.Attendees),
+		DeletedAt: formatTimePtr(ev.DeletedAt),
+		Tags:      ev.Tags,
 	}
 }
 
@@ -160,7 +236,7 @@ const (
 // --- RPC Implementations ---
 
 func (s *EventServer) HealthCheck(ctx context.Context, req *emptypb.Empty) (*calendarpb.HealthResponse, error) {
-	s.logger.Info("health check requested")
+	s.log(ctx).Info("health check requested")
 	return &calendarpb.HealthResponse{Status: "OK"}, nil
 }
 
@@ -168,27 +244,30 @@ func (s *EventServer) CreateEvent(
 	ctx context.Context,
 	req *calendarpb.CreateEventRequest,
 ) (*calendarpb.CreateEventResponse, error) {
-	eventValidated, err := fromProtoEvent(req.Event)
+	eventValidated, err := FromProtoEvent(req.Event)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrEmptyInput):
-			s.logger.Error(fmt.Sprintf("validation failed: %v", err))
+			s.log(ctx).Error(fmt.Sprintf("validation failed: %v", err))
 			return nil, status.Errorf(codes.InvalidArgument, "event data missing")
 		case errors.Is(err, ErrInvalidDate):
-			s.logger.Error(fmt.Sprintf("validation failed: %v", err))
-			return nil, status.Errorf(codes.InvalidArgument, "invalid date format")
+			s.log(ctx).Error(fmt.Sprintf("validation failed: %v", err))
+			return nil, invalidEvent("invalid date format", err)
 		default:
-			s.logger.Error(fmt.Sprintf("unexpected error: %v", err))
+			s.log(ctx).Error(fmt.Sprintf("unexpected error: %v", err))
 			return nil, status.Errorf(codes.Internal, "something went wrong, pls try again a bit later")
 		}
 	}
 
-	if err := s.application.CreateEvent(ctx, eventValidated); err != nil {
-		s.logger.Error(fmt.Sprintf("failed to create event: %v", err))
+	if _, err := s.application.CreateEvent(ctx, eventValidated); err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to create event: %v", err))
+		if errors.Is(err, app.ErrInvalidEvent) {
+			return nil, invalidEvent(app.ErrInvalidEvent.Error(), err)
+		}
 		return nil, status.Errorf(codes.Unavailable, "something went wrong, pls try again a bit later")
 	}
 
-	s.logger.Info("event created successfully")
+	s.log(ctx).Info("event created successfully")
 	return &calendarpb.CreateEventResponse{Success: true}, nil
 }
 
@@ -198,56 +277,72 @@ func (s *EventServer) GetEvent(ctx context.Context, req *calendarpb.GetEventRequ
 ) {
 	ev, err := s.application.GetEvent(ctx, int(req.Id))
 	if err != nil {
-		s.logger.Error(fmt.Sprintf("error on getting event: %v", err))
+		s.log(ctx).Error(fmt.Sprintf("error on getting event: %v", err))
 		return nil, status.Errorf(codes.NotFound, "requested event not found")
 	}
 
-	s.logger.Info("returned founded event successfully")
+	s.log(ctx).Info("returned founded event successfully")
 	return &calendarpb.GetEventResponse{
 		Event: toProtoEvent(ev),
 	}, nil
 }
 
-func (s *EventServer) ListEventsDay(ctx context.Context, req *emptypb.Empty) (*calendarpb.ListEventsResponse, error) {
-	_ = req
+// listEvents serves the ListEvents* RPCs; what names the period in log and error messages.
+func (s *EventServer) listEvents(
+	ctx context.Context,
+	req *calendarpb.ListEventsRequest,
+	period storage.Period,
+	what string,
+) (*calendarpb.ListEventsResponse, error) {
+	loc, err := storage.LoadLocation(req.GetTimeZone())
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("validation failed: %v", err))
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
-	events, err := s.application.ListEvents(ctx, storage.PeriodDay)
+	events, err := s.application.ListEvents(ctx, storage.Filter{
+		Period:   period,
+		Location: loc,
+		Clinic:   req.GetClinic(),
+		Service:  req.GetService(),
+		Tags:     req.GetTags(),
+	})
 	if err != nil {
-		s.logger.Error(fmt.Sprintf("failed to list events for the day: %v", err))
-		return nil, status.Errorf(codes.Internal, "failed to list events for the day")
+		s.log(ctx).Error(fmt.Sprintf("failed to list events for %s: %v", what, err))
+		return nil, status.Errorf(codes.Internal, "failed to list events for %s", what)
 	}
-	s.logger.Info("listed day events successfully")
+	s.log(ctx).Info(fmt.Sprintf("listed %s events successfully", period))
 	return &calendarpb.ListEventsResponse{
 		Events: toProtoEvents(events),
 	}, nil
 }
 
-func (s *EventServer) ListEventsWeek(ctx context.Context, req *emptypb.Empty) (*calendarpb.ListEventsResponse, error) {
-	_ = req
+func (s *EventServer) ListEvents(
+	ctx context.Context,
+	req *calendarpb.ListEventsRequest,
+) (*calendarpb.ListEventsResponse, error) {
+	return s.listEvents(ctx, req, storage.PeriodAll, "all time")
+}
 
-	events, err := s.application.ListEvents(ctx, storage.PeriodWeek)
-	if err != nil {
-		s.logger.Error(fmt.Sprintf("failed to list events for the week: %v", err))
-		return nil, status.Errorf(codes.Internal, "failed to list events for the week")
-	}
-	s.logger.Info("listed week events successfully")
-	return &calendarpb.ListEventsResponse{
-		Events: toProtoEvents(events),
-	}, nil
+func (s *EventServer) ListEventsDay(
+	ctx context.Context,
+	req *calendarpb.ListEventsRequest,
+) (*calendarpb.ListEventsResponse, error) {
+	return s.listEvents(ctx, req, storage.PeriodDay, "the day")
 }
 
-func (s *EventServer) ListEventsMonth(ctx context.Context, req *emptypb.Empty) (*calendarpb.ListEventsResponse, error) {
-	_ = req
+func (s *EventServer) ListEventsWeek(
+	ctx context.Context,
+	req *calendarpb.ListEventsRequest,
+) (*calendarpb.ListEventsResponse, error) {
+	return s.listEvents(ctx, req, storage.PeriodWeek, "the week")
+}
 
-	events, err := s.application.ListEvents(ctx, storage.PeriodMonth)
-	if err != nil {
-		s.logger.Error(fmt.Sprintf("failed to list events for the month: %v", err))
-		return nil, status.Errorf(codes.Internal, "failed to list events for the month")
-	}
-	s.logger.Info("listed month events successfully")
-	return &calendarpb.ListEventsResponse{
-		Events: toProtoEvents(events),
-	}, nil
+func (s *EventServer) ListEventsMonth(
+	ctx context.Context,
+	req *calendarpb.ListEventsRequest,
+) (*calendarpb.ListEventsResponse, error) {
+	return s.listEvents(ctx, req, storage.PeriodMonth, "the month")
 }
 
 func (s *EventServer) UpdateEvent(
@@ -255,30 +350,33 @@ func (s *EventServer) UpdateEvent(
 	req *calendarpb.UpdateEventRequest,
 ) (*calendarpb.UpdateEventResponse, error) {
 	if req.Event == nil {
-		s.logger.Error("Update Event failed: client provided no event data")
+		s.log(ctx).Error("Update Event failed: client provided no event data")
 		return &calendarpb.UpdateEventResponse{
 			Success: false,
 			Error:   "no event data provided",
 		}, nil
 	}
 
-	eventValidated, err := fromProtoEvent(req.Event)
+	eventValidated, err := FromProtoEvent(req.Event)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrInvalidDate):
-			s.logger.Error(fmt.Sprintf("validation failed: %v", err))
-			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("%v", ErrInvalidDate))
+			s.log(ctx).Error(fmt.Sprintf("validation failed: %v", err))
+			return nil, invalidEvent(ErrInvalidDate.Error(), err)
 		default:
-			s.logger.Error(fmt.Sprintf("unexpected error: %v", err))
+			s.log(ctx).Error(fmt.Sprintf("unexpected error: %v", err))
 			return nil, status.Errorf(codes.Internal, fmt.Sprintf("%v", ErrInternal))
 		}
 	}
 
 	if err := s.application.UpdateEvent(ctx, eventValidated); err != nil {
-		s.logger.Error(fmt.Sprintf("failed to update event: %v", err))
+		s.log(ctx).Error(fmt.Sprintf("failed to update event: %v", err))
+		if errors.Is(err, app.ErrInvalidEvent) {
+			return nil, invalidEvent(app.ErrInvalidEvent.Error(), err)
+		}
 		return nil, status.Errorf(codes.Unavailable, fmt.Sprintf("%v", ErrInternal))
 	}
-	s.logger.Info("event updated successfully")
+	s.log(ctx).Info("event updated successfully")
 	return &calendarpb.UpdateEventResponse{Success: true}, nil
 }
 
@@ -287,9 +385,9 @@ func (s *EventServer) DeleteEvent(
 	req *calendarpb.DeleteEventRequest,
 ) (*calendarpb.DeleteEventResponse, error) {
 	if err := s.application.DeleteEvent(ctx, int(req.Id)); err != nil {
-		s.logger.Error(fmt.Sprintf("failed to delete event: %v", err))
+		s.log(ctx).Error(fmt.Sprintf("failed to delete event: %v", err))
 		return nil, status.Errorf(codes.Internal, fmt.Sprintf("%v", ErrInternal))
 	}
-	s.logger.Info("deleted founded event successfully")
+	s.log(ctx).Info("deleted founded event successfully")
 	return &calendarpb.DeleteEventResponse{Success: true}, nil
 }
@@ -2,10 +2,29 @@ package calendargrpc
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
 	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/ical"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestCreateEventReturnsErrorWhenAppIsNil(t *testing.T) {
@@ -19,3 +38,460 @@ func TestCreateEventReturnsErrorWhenAppIsNil(t *testing.T) {
 		t.Fatal("expected error when application is nil, got nil")
 	}
 }
+
+func TestAttendeeRPCs(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+	ctx := context.Background()
+
+	_, err := server.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		Title: "Check-up", Start: time.Now().Format(time.RFC3339),
+	}})
+	require.NoError(t, err)
+
+	_, err = server.InviteAttendee(ctx, &calendarpb.InviteAttendeeRequest{EventId: 1, UserId: 4, Role: "optional"})
+	require.NoError(t, err)
+	_, err = server.InviteAttendee(ctx, &calendarpb.InviteAttendeeRequest{EventId: 1, UserId: 4})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = server.InviteAttendee(ctx, &calendarpb.InviteAttendeeRequest{EventId: 2, UserId: 4})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = server.RespondInvitation(ctx, &calendarpb.RespondInvitationRequest{EventId: 1, UserId: 4, Status: "maybe"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.RespondInvitation(ctx, &calendarpb.RespondInvitationRequest{EventId: 1, UserId: 4, Status: "tentative"})
+	require.NoError(t, err)
+
+	resp, err := server.GetEvent(ctx, &calendarpb.GetEventRequest{Id: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Event.Attendees, 1)
+	require.Equal(t, "optional", resp.Event.Attendees[0].Role)
+	require.Equal(t, "tentative", resp.Event.Attendees[0].Status)
+
+	_, err = server.RemoveAttendee(ctx, &calendarpb.RemoveAttendeeRequest{EventId: 1, UserId: 4})
+	require.NoError(t, err)
+	_, err = server.RemoveAttendee(ctx, &calendarpb.RemoveAttendeeRequest{EventId: 1, UserId: 4})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestTimeZones(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+	ctx := context.Background()
+
+	_, err := server.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		Title: "Clinic closed", Start: "2024-12-24T00:00:00Z", End: "2024-12-26T00:00:00Z",
+		AllDay: true, TimeZone: "Asia/Tokyo",
+	}})
+	require.NoError(t, err)
+
+	resp, err := server.GetEvent(ctx, &calendarpb.GetEventRequest{Id: 1})
+	require.NoError(t, err)
+	require.Equal(t, "Asia/Tokyo", resp.Event.TimeZone)
+	require.Equal(t, "2024-12-24T00:00:00+09:00", resp.Event.Start)
+	require.Equal(t, "2024-12-26T00:00:00+09:00", resp.Event.End)
+
+	_, err = server.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		Title: "Nowhere", Start: "2024-12-24T10:00:00Z", TimeZone: "Mars/Olympus_Mons",
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.ListEventsDay(ctx, &calendarpb.ListEventsRequest{TimeZone: "Mars/Olympus_Mons"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	all, err := server.ListEvents(ctx, &calendarpb.ListEventsRequest{TimeZone: "Asia/Tokyo"})
+	require.NoError(t, err)
+	require.Len(t, all.Events, 1)
+}
+
+func TestEventValidation(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+
+	_, err := server.CreateEvent(context.Background(), &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		Title:  strings.Repeat("x", 41),
+		Start:  "2024-12-24T10:00:00Z",
+		End:    "2024-12-24T09:00:00Z",
+		UserId: -1,
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, map[string]bool{"event.title": true, "event.end": true, "event.userId": true}, violations(t, err))
+
+	_, err = server.UpdateEvent(context.Background(), &calendarpb.UpdateEventRequest{Event: &calendarpb.Event{
+		Id: 1, Title: "Checkup", Start: "tomorrow", End: "later",
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, map[string]bool{"event.start": true, "event.end": true}, violations(t, err))
+
+	// The HTTP gateway answers with 400 and the violations in the body.
+	mux := runtime.NewServeMux()
+	require.NoError(t, calendarpb.RegisterCalendarServiceHandlerServer(context.Background(), mux, server))
+	body := `{"event": {"title": "Checkup", "start": "2024-12-24T10:00:00Z", "userId": -3}}`
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/create", strings.NewReader(body)))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), `"fieldViolations"`)
+	require.Contains(t, rec.Body.String(), `"field":"event.userId"`)
+}
+
+// violations returns the fields named by the BadRequest detail of err.
+func violations(t *testing.T, err error) map[string]bool {
+	t.Helper()
+	fields := make(map[string]bool)
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.FieldViolations {
+				require.NotEmpty(t, v.Description, v.Field)
+				fields[v.Field] = true
+			}
+		}
+	}
+	return fields
+}
+
+func TestICSExportImport(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+	ctx := context.Background()
+
+	for _, ev := range []*calendarpb.Event{
+		{Title: "Check-up", Start: "2024-03-10T09:00:00+01:00", End: "2024-03-10T09:30:00+01:00",
+			TimeZone: "Europe/Berlin", Clinic: "Main street 1", UserId: 7},
+		{Title: "Other user", Start: "2024-03-10T10:00:00Z", UserId: 8},
+		{Title: "Too late", Start: "2024-04-10T10:00:00Z", UserId: 7},
+	} {
+		_, err := server.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: ev})
+		require.NoError(t, err)
+	}
+
+	body, err := server.ExportICS(ctx, &calendarpb.ExportICSRequest{
+		UserId: 7, From: "2024-03-01T00:00:00Z", To: "2024-04-01T00:00:00Z",
+	})
+	require.NoError(t, err)
+	require.Equal(t, ical.ContentType, body.ContentType)
+	require.Equal(t, 1, strings.Count(string(body.Data), "BEGIN:VEVENT"))
+	require.Contains(t, string(body.Data), "DTSTART;TZID=Europe/Berlin:20240310T090000")
+	require.Contains(t, string(body.Data), "LOCATION:Main street 1")
+
+	all, err := server.ExportICS(ctx, &calendarpb.ExportICSRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 3, strings.Count(string(all.Data), "BEGIN:VEVENT"))
+
+	_, err = server.ExportICS(ctx, &calendarpb.ExportICSRequest{From: "yesterday"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	doc := strings.Replace(string(body.Data), "END:VCALENDAR",
+		"BEGIN:VEVENT\r\nUID:broken\r\nSUMMARY:No start\r\nEND:VEVENT\r\nEND:VCALENDAR", 1)
+	resp, err := server.ImportICS(ctx, &calendarpb.ImportICSRequest{
+		Body: &httpbody.HttpBody{Data: []byte(doc)}, UserId: 9,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), resp.Created)
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, int32(1), resp.Errors[0].Index)
+	require.Equal(t, "broken", resp.Errors[0].Uid)
+
+	imported, err := server.GetEvent(ctx, &calendarpb.GetEventRequest{Id: 4})
+	require.NoError(t, err)
+	require.Equal(t, "Check-up", imported.Event.Title)
+	require.Equal(t, "Europe/Berlin", imported.Event.TimeZone)
+	require.Equal(t, "2024-03-10T09:00:00+01:00", imported.Event.Start)
+	require.Equal(t, int32(9), imported.Event.UserId)
+
+	_, err = server.ImportICS(ctx, &calendarpb.ImportICSRequest{Body: &httpbody.HttpBody{Data: []byte("hello")}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAvailability(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+	ctx := context.Background()
+
+	_, err := server.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		Title: "Private consultation", Start: "2024-03-11T10:00:00+01:00", End: "2024-03-11T11:00:00+01:00",
+		UserId: 5, Clinic: "Main street 1",
+	}})
+	require.NoError(t, err)
+
+	busy, err := server.FreeBusy(ctx, &calendarpb.FreeBusyRequest{
+		Clinic: "Main street 1", From: "2024-03-11T00:00:00Z", To: "2024-03-12T00:00:00Z", TimeZone: "Europe/Berlin",
+	})
+	require.NoError(t, err)
+	require.Len(t, busy.Busy, 1)
+	require.Equal(t, "2024-03-11T10:00:00+01:00", busy.Busy[0].Start)
+	require.Equal(t, "2024-03-11T11:00:00+01:00", busy.Busy[0].End)
+
+	free, err := server.FindFreeSlots(ctx, &calendarpb.FindFreeSlotsRequest{
+		UserIds: []int32{5}, From: "2024-03-11T00:00:00+01:00", To: "2024-03-12T00:00:00+01:00",
+		SlotMinutes: 30, WorkStart: "09:00", WorkEnd: "18:00", TimeZone: "Europe/Berlin",
+	})
+	require.NoError(t, err)
+	require.Len(t, free.Free, 2)
+	require.Equal(t, "2024-03-11T09:00:00+01:00", free.Free[0].Start)
+	require.Equal(t, "2024-03-11T10:00:00+01:00", free.Free[0].End)
+	require.Equal(t, "2024-03-11T11:00:00+01:00", free.Free[1].Start)
+	require.Equal(t, "2024-03-11T18:00:00+01:00", free.Free[1].End)
+
+	for _, req := range []*calendarpb.FindFreeSlotsRequest{
+		{UserIds: []int32{5}, From: "2024-03-11", To: "2024-03-12T00:00:00Z", SlotMinutes: 30},
+		{UserIds: []int32{5}, From: "2024-03-11T00:00:00Z", To: "2024-03-12T00:00:00Z", SlotMinutes: 30, WorkStart: "9am"},
+		{UserIds: []int32{5}, From: "2024-03-11T00:00:00Z", To: "2024-03-12T00:00:00Z"},
+		{From: "2024-03-11T00:00:00Z", To: "2024-03-12T00:00:00Z", SlotMinutes: 30},
+	} {
+		_, err = server.FindFreeSlots(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), req.String())
+	}
+}
+
+func TestBatchRPCs(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+	ctx := context.Background()
+
+	resp, err := server.BatchCreateEvents(ctx, &calendarpb.BatchCreateEventsRequest{Events: []*calendarpb.Event{
+		{Title: "Check-up", Start: "2024-03-11T10:00:00Z"},
+		{Title: "No start"},
+	}})
+	require.NoError(t, err)
+	require.False(t, resp.Applied)
+	require.Contains(t, resp.Results[0].Error, "not applied")
+	require.Contains(t, resp.Results[1].Error, "invalid date format")
+
+	resp, err = server.BatchCreateEvents(ctx, &calendarpb.BatchCreateEventsRequest{Events: []*calendarpb.Event{
+		{Title: "Check-up", Start: "2024-03-11T10:00:00Z"},
+		{Title: "Follow-up", Start: "2024-03-18T10:00:00Z"},
+	}})
+	require.NoError(t, err)
+	require.True(t, resp.Applied)
+	require.Equal(t, []int32{1, 2}, []int32{resp.Results[0].Id, resp.Results[1].Id})
+
+	resp, err = server.BatchDeleteEvents(ctx, &calendarpb.BatchDeleteEventsRequest{Ids: []int32{1, 3}})
+	require.NoError(t, err)
+	require.False(t, resp.Applied)
+	require.True(t, strings.HasPrefix(resp.Results[1].Error, "event not found"), resp.Results[1].Error)
+
+	resp, err = server.BatchDeleteEvents(ctx, &calendarpb.BatchDeleteEventsRequest{Ids: []int32{1, 2}})
+	require.NoError(t, err)
+	require.True(t, resp.Applied)
+
+	_, err = server.BatchDeleteEvents(ctx, &calendarpb.BatchDeleteEventsRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatchEvents(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(application, log, config.GRPCConfig{}, nil, nil)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := calendarpb.NewCalendarServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchEvents(ctx, &calendarpb.WatchEventsRequest{UserId: 7})
+	require.NoError(t, err)
+	// The watch is registered once the response headers arrive.
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	_, err = client.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		Title: "Someone else's", Start: "2024-03-11T10:00:00Z", UserId: 8,
+	}})
+	require.NoError(t, err)
+	_, err = client.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		Title: "Check-up", Start: "2024-03-11T10:00:00Z", UserId: 7,
+	}})
+	require.NoError(t, err)
+	_, err = client.DeleteEvent(ctx, &calendarpb.DeleteEventRequest{Id: 2})
+	require.NoError(t, err)
+
+	change, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "created", change.Type)
+	require.Equal(t, "Check-up", change.Event.Title)
+	change, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "deleted", change.Type)
+	require.EqualValues(t, 2, change.EventId)
+	require.Nil(t, change.Event)
+}
+
+func TestRateLimitBeforeAuth(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	authn, err := auth.New(config.AuthConf{APIKeys: []config.APIKeyConf{{Key: "s3cret", Subject: "front-desk"}}})
+	require.NoError(t, err)
+	cfg := config.GRPCConfig{RateLimit: config.RateLimitConfig{RPS: 0.01, Burst: 2}}
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(application, log, cfg, nil, authn)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := calendarpb.NewCalendarServiceClient(conn)
+
+	// Guessed credentials use up the bucket like any other call.
+	ctx := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyMetadata, "guess")
+	for i := 0; i < 2; i++ {
+		_, err = client.GetEvent(ctx, &calendarpb.GetEventRequest{Id: 1})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	_, err = client.GetEvent(ctx, &calendarpb.GetEventRequest{Id: 1})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestHistoryRPCs(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+	ctx := context.Background()
+
+	_, err := server.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+		Title: "Check-up", Start: "2024-03-11T10:00:00Z",
+	}})
+	require.NoError(t, err)
+	_, err = server.UpdateEvent(ctx, &calendarpb.UpdateEventRequest{Event: &calendarpb.Event{
+		Id: 1, Title: "Check-up", Start: "2024-03-11T11:00:00Z",
+	}})
+	require.NoError(t, err)
+	_, err = server.RestoreEvent(ctx, &calendarpb.RestoreEventRequest{Id: 1})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = server.DeleteEvent(ctx, &calendarpb.DeleteEventRequest{Id: 1})
+	require.NoError(t, err)
+
+	history, err := server.GetEventHistory(ctx, &calendarpb.GetEventHistoryRequest{EventId: 1})
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 3)
+	update := history.Entries[1]
+	require.Equal(t, "updated", update.Action)
+	require.Equal(t, "anonymous", update.Actor)
+	require.Len(t, update.Changes, 1)
+	require.Equal(t, "start", update.Changes[0].Field)
+	require.Equal(t, "2024-03-11T11:00:00Z", update.Changes[0].After)
+	require.Nil(t, history.Entries[2].After)
+
+	_, err = server.GetEvent(ctx, &calendarpb.GetEventRequest{Id: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	trash, err := server.ListDeletedEvents(ctx, &calendarpb.ListDeletedEventsRequest{})
+	require.NoError(t, err)
+	require.Len(t, trash.Events, 1)
+	require.NotEmpty(t, trash.Events[0].DeletedAt)
+
+	restored, err := server.RestoreEvent(ctx, &calendarpb.RestoreEventRequest{Id: 1})
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-11T11:00:00Z", restored.Event.Start)
+	require.Empty(t, restored.Event.DeletedAt)
+	_, err = server.GetEvent(ctx, &calendarpb.GetEventRequest{Id: 1})
+	require.NoError(t, err)
+	trash, err = server.ListDeletedEvents(ctx, &calendarpb.ListDeletedEventsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, trash.Events)
+
+	_, err = server.GetEventHistory(ctx, &calendarpb.GetEventHistoryRequest{EventId: 42})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCategoryRPCs(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+	ctx := context.Background()
+
+	_, err := server.CreateCategory(ctx, &calendarpb.CreateCategoryRequest{
+		Category: &calendarpb.Category{Name: "Vaccination", Color: "green"},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	category, err := server.CreateCategory(ctx, &calendarpb.CreateCategoryRequest{
+		Category: &calendarpb.Category{Name: "Vaccination", Color: "#00AA00"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "#00aa00", category.Color)
+	_, err = server.CreateCategory(ctx, &calendarpb.CreateCategoryRequest{
+		Category: &calendarpb.Category{Name: "Vaccination"},
+	})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	for _, clinic := range []string{"North", "South"} {
+		_, err := server.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: &calendarpb.Event{
+			Title: "Flu shot", Start: "2024-03-11T10:00:00Z", Clinic: clinic,
+		}})
+		require.NoError(t, err)
+	}
+	_, err = server.SetEventTags(ctx, &calendarpb.SetEventTagsRequest{EventId: 1, Tags: []string{"Unknown"}})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	tagged, err := server.SetEventTags(ctx, &calendarpb.SetEventTagsRequest{EventId: 1, Tags: []string{"Vaccination"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Vaccination"}, tagged.Event.Tags)
+
+	list, err := server.ListEvents(ctx, &calendarpb.ListEventsRequest{Tags: []string{"Vaccination"}})
+	require.NoError(t, err)
+	require.Len(t, list.Events, 1)
+	require.Equal(t, int32(1), list.Events[0].Id)
+	list, err = server.ListEvents(ctx, &calendarpb.ListEventsRequest{Clinic: "South"})
+	require.NoError(t, err)
+	require.Len(t, list.Events, 1)
+	require.Equal(t, int32(2), list.Events[0].Id)
+
+	_, err = server.UpdateCategory(ctx, &calendarpb.UpdateCategoryRequest{
+		Category: &calendarpb.Category{Id: category.Id, Name: "Vaccinations"},
+	})
+	require.NoError(t, err)
+	got, err := server.GetEvent(ctx, &calendarpb.GetEventRequest{Id: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Vaccinations"}, got.Event.Tags)
+
+	_, err = server.DeleteCategory(ctx, &calendarpb.DeleteCategoryRequest{Id: category.Id})
+	require.NoError(t, err)
+	_, err = server.DeleteCategory(ctx, &calendarpb.DeleteCategoryRequest{Id: category.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	categories, err := server.ListCategories(ctx, &calendarpb.ListCategoriesRequest{})
+	require.NoError(t, err)
+	require.Empty(t, categories.Categories)
+}
+
+func TestSearchEventsRPC(t *testing.T) {
+	log := logger.New("")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	server := NewEventServer(application, log)
+	ctx := context.Background()
+
+	for _, ev := range []*calendarpb.Event{
+		{Title: "Flu shot", Description: "Seasonal vaccination", Start: "2024-03-04T09:00:00Z"},
+		{Title: "Check-up", Description: "Discuss the flu shot", Start: "2024-03-11T09:00:00Z"},
+	} {
+		_, err := server.CreateEvent(ctx, &calendarpb.CreateEventRequest{Event: ev})
+		require.NoError(t, err)
+	}
+
+	resp, err := server.SearchEvents(ctx, &calendarpb.SearchEventsRequest{Query: `"flu shot"`})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	require.Equal(t, "<b>Flu</b> <b>shot</b>", resp.Results[0].TitleHighlight)
+	require.Greater(t, resp.Results[0].Rank, resp.Results[1].Rank)
+
+	resp, err = server.SearchEvents(ctx, &calendarpb.SearchEventsRequest{Query: "flu", From: "2024-03-10T00:00:00Z"})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	require.Equal(t, "Check-up", resp.Results[0].Event.Title)
+
+	_, err = server.SearchEvents(ctx, &calendarpb.SearchEventsRequest{Query: " "})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.SearchEvents(ctx, &calendarpb.SearchEventsRequest{Query: "flu", From: "March"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
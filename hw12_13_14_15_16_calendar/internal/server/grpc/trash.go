@@ -0,0 +1,23 @@
+package calendargrpc
+
+import (
+	"context"
+	"fmt"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *EventServer) ListDeletedEvents(
+	ctx context.Context,
+	_ *calendarpb.ListDeletedEventsRequest,
+) (*calendarpb.ListEventsResponse, error) {
+	events, err := s.application.ListDeletedEvents(ctx)
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to list deleted events: %v", err))
+		return nil, status.Error(codes.Internal, "failed to list deleted events")
+	}
+	s.log(ctx).Info(fmt.Sprintf("found %d deleted events", len(events)))
+	return &calendarpb.ListEventsResponse{Events: toProtoEvents(events)}, nil
+}
@@ -0,0 +1,51 @@
+package calendargrpc
+
+import (
+	"errors"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// eventFields maps the fields of storage.Event to those of the API event.
+var eventFields = map[string]string{
+	"ID":          "id",
+	"Title":       "title",
+	"Description": "description",
+	"Start":       "start",
+	"End":         "end",
+	"AllDay":      "allDay",
+	"TimeZone":    "timeZone",
+	"Clinic":      "clinic",
+	"UserID":      "userId",
+	"Service":     "service",
+}
+
+// invalidEvent returns an InvalidArgument status with message msg. The field violations
+// found in err, if any, are attached as a BadRequest detail, with fields named like in
+// the request, e.g. "event.title"; the HTTP gateway renders them in the 400 body.
+func invalidEvent(msg string, err error) error {
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, fe := range errs {
+		field, ok := eventFields[fe.Field]
+		if !ok {
+			field = fe.Field
+		}
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       "event." + field,
+			Description: fe.Err.Error(),
+		})
+	}
+	st, detailsErr := status.New(codes.InvalidArgument, msg).WithDetails(badRequest)
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+	return st.Err()
+}
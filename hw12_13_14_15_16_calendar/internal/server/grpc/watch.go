@@ -0,0 +1,73 @@
+package calendargrpc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// WatchEvents sends the changes to the requested events until the client goes away.
+// A client that falls behind gets codes.ResourceExhausted and has to reload and watch again.
+func (s *EventServer) WatchEvents(
+	req *calendarpb.WatchEventsRequest,
+	stream grpc.ServerStreamingServer[calendarpb.EventChange],
+) error {
+	ctx := stream.Context()
+	watcher, err := s.application.WatchEvents(ctx, int(req.UserId))
+	if err != nil {
+		s.log(ctx).Error(fmt.Sprintf("failed to watch events: %v", err))
+		return watchError(err)
+	}
+	defer watcher.Close()
+	s.log(ctx).Info(fmt.Sprintf("watching events of user %d", req.UserId))
+	// Sending the headers right away tells the client that the watch is live.
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-watcher.Changes():
+			if !ok {
+				s.log(ctx).Error(fmt.Sprintf("watch ended: %v", watcher.Err()))
+				return watchError(watcher.Err())
+			}
+			if err := stream.Send(ToProtoEventChange(change)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ToProtoEventChange converts a change for the API.
+func ToProtoEventChange(change storage.Change) *calendarpb.EventChange {
+	pc := &calendarpb.EventChange{
+		Type:    string(change.Type),
+		EventId: int32(change.EventID), //nolint:gosec
+		Time:    change.At.Format(time.RFC3339),
+	}
+	if change.Event != nil {
+		pc.Event = toProtoEvent(*change.Event)
+	}
+	return pc
+}
+
+func watchError(err error) error {
+	switch {
+	case errors.Is(err, app.ErrWatchForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, app.ErrWatcherTooSlow):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Errorf(codes.Unavailable, fmt.Sprintf("%v", ErrInternal))
+}
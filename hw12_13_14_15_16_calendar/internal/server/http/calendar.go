@@ -0,0 +1,55 @@
+package internalhttp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/ical"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+)
+
+// CalendarMIME is the request content type handled by CalendarMarshaler.
+const CalendarMIME = "text/calendar"
+
+// maxCalendarBody limits the size of an uploaded calendar document.
+const maxCalendarBody = 10 << 20
+
+// CalendarMarshaler lets the gateway accept raw text/calendar request bodies: they are
+// passed to the gRPC server as a google.api.HttpBody instead of being parsed as JSON.
+// Responses are written like the gateway default (JSON, or the bytes of an HttpBody).
+type CalendarMarshaler struct {
+	runtime.HTTPBodyMarshaler
+}
+
+// NewCalendarMarshaler returns the marshaler to register for CalendarMIME with
+// runtime.WithMarshalerOption.
+func NewCalendarMarshaler() *CalendarMarshaler {
+	return &CalendarMarshaler{runtime.HTTPBodyMarshaler{
+		Marshaler: &runtime.JSONPb{},
+	}}
+}
+
+func (m *CalendarMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	return runtime.DecoderFunc(func(v interface{}) error {
+		data, err := io.ReadAll(io.LimitReader(r, maxCalendarBody+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > maxCalendarBody {
+			return fmt.Errorf("calendar is larger than %d bytes", maxCalendarBody)
+		}
+
+		body := &httpbody.HttpBody{ContentType: ical.ContentType, Data: data}
+		switch v := v.(type) {
+		case **httpbody.HttpBody:
+			*v = body
+		case *httpbody.HttpBody:
+			v.ContentType, v.Data = body.ContentType, body.Data
+		default:
+			return m.Marshaler.NewDecoder(bytes.NewReader(data)).Decode(v)
+		}
+		return nil
+	})
+}
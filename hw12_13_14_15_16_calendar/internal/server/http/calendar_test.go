@@ -0,0 +1,52 @@
+package internalhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	calendarpb "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/calendarGRPC/pb"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+)
+
+type icsServer struct {
+	calendarpb.UnimplementedCalendarServiceServer
+	imported *calendarpb.ImportICSRequest
+}
+
+func (s *icsServer) ImportICS(_ context.Context, req *calendarpb.ImportICSRequest) (*calendarpb.ImportICSResponse, error) {
+	s.imported = req
+	return &calendarpb.ImportICSResponse{Created: 1}, nil
+}
+
+func (s *icsServer) ExportICS(context.Context, *calendarpb.ExportICSRequest) (*httpbody.HttpBody, error) {
+	return &httpbody.HttpBody{ContentType: "text/calendar; charset=utf-8", Data: []byte("BEGIN:VCALENDAR\r\n")}, nil
+}
+
+func TestCalendarMarshaler(t *testing.T) {
+	srv := &icsServer{}
+	mux := runtime.NewServeMux(runtime.WithMarshalerOption(CalendarMIME, NewCalendarMarshaler()))
+	require.NoError(t, calendarpb.RegisterCalendarServiceHandlerServer(context.Background(), mux, srv))
+
+	doc := "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/ics/import?userId=3", strings.NewReader(doc))
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, doc, string(srv.imported.GetBody().GetData()))
+	require.Equal(t, int32(3), srv.imported.UserId)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"created":1}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/ics/export?userId=3", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Equal(t, "BEGIN:VCALENDAR\r\n", rec.Body.String())
+}
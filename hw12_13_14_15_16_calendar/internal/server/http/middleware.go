@@ -0,0 +1,97 @@
+package internalhttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/requestid"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/session"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/tlsconfig"
+)
+
+// responseRecorder captures the status code and body size written by the wrapped handler.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.size += n
+	return n, err
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Flush lets streaming handlers keep working behind the recorder.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// AccessLogMiddleware assigns a request ID to every request (reusing the X-Request-ID
+// header when the client sends a valid one) and writes an access log line once the request is served.
+func AccessLogMiddleware(log *logger.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := requestid.FromClient(r.Header.Get(requestid.Header))
+		r.Header.Set(requestid.Header, id)
+		w.Header().Set(requestid.Header, id)
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r.WithContext(requestid.NewContext(r.Context(), id)))
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		reqLog := log.With("request_id", id).With("peer", r.RemoteAddr)
+		if identity, ok := tlsconfig.RequestIdentity(r); ok {
+			reqLog = reqLog.With("client", identity)
+		}
+		reqLog.Info(fmt.Sprintf(
+			"HTTP %s %s | status: %d | size: %d | duration: %s",
+			r.Method, r.URL.RequestURI(), rec.status, rec.size, time.Since(start)))
+	})
+}
+
+// IncomingHeaderMatcher forwards the X-Request-ID, X-Session-Token and X-API-Key headers to the gRPC
+// server as metadata, falling back to the gateway defaults for every other header.
+func IncomingHeaderMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case textproto.CanonicalMIMEHeaderKey(requestid.Header):
+		return requestid.MetadataKey, true
+	case textproto.CanonicalMIMEHeaderKey(session.Header):
+		return session.MetadataKey, true
+	case textproto.CanonicalMIMEHeaderKey(auth.APIKeyHeader):
+		return auth.APIKeyMetadata, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// OutgoingHeaderMatcher returns the session token of the gRPC response as the X-Session-Token
+// header, and other response metadata with the gateway's default Grpc-Metadata- prefix.
+func OutgoingHeaderMatcher(key string) (string, bool) {
+	if key == session.MetadataKey {
+		return session.Header, true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
@@ -0,0 +1,75 @@
+package internalhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/requestid"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/session"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	var seenID string
+	handler := AccessLogMiddleware(logger.New("error"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = requestid.FromContext(r.Context())
+		require.Equal(t, seenID, r.Header.Get(requestid.Header))
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("short and stout"))
+	}))
+
+	t.Run("generates request id", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+
+		require.Equal(t, http.StatusTeapot, rec.Code)
+		require.NotEmpty(t, seenID)
+		require.Equal(t, seenID, rec.Header().Get(requestid.Header))
+	})
+
+	t.Run("keeps client request id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+		req.Header.Set(requestid.Header, "client-id")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, "client-id", seenID)
+		require.Equal(t, "client-id", rec.Header().Get(requestid.Header))
+	})
+
+	t.Run("replaces invalid client request id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+		req.Header.Set(requestid.Header, "<script>alert(1)</script>")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.NotEqual(t, "<script>alert(1)</script>", seenID)
+		require.Len(t, seenID, 32)
+		require.Equal(t, seenID, rec.Header().Get(requestid.Header))
+	})
+}
+
+func TestIncomingHeaderMatcher(t *testing.T) {
+	key, ok := IncomingHeaderMatcher("X-Request-Id")
+	require.True(t, ok)
+	require.Equal(t, requestid.MetadataKey, key)
+
+	key, ok = IncomingHeaderMatcher("X-Session-Token")
+	require.True(t, ok)
+	require.Equal(t, session.MetadataKey, key)
+
+	_, ok = IncomingHeaderMatcher("X-Unrelated")
+	require.False(t, ok)
+}
+
+func TestOutgoingHeaderMatcher(t *testing.T) {
+	key, ok := OutgoingHeaderMatcher(session.MetadataKey)
+	require.True(t, ok)
+	require.Equal(t, session.Header, key)
+
+	key, ok = OutgoingHeaderMatcher("x-request-id")
+	require.True(t, ok)
+	require.Equal(t, "Grpc-Metadata-x-request-id", key)
+}
@@ -0,0 +1,116 @@
+package internalhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	calendarGRPC "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/server/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// WatchPath serves the WatchEvents stream as server-sent events.
+const WatchPath = "/api/events/watch"
+
+// watchHeartbeat is how often an idle stream sends a comment, so that proxies keep it open.
+const watchHeartbeat = 30 * time.Second
+
+// WatchHandler streams event changes as server-sent events: one "created", "updated" or
+// "deleted" event per change, with the JSON form of EventChange as data. A watcher that
+// falls behind gets an "error" event and the stream ends.
+type WatchHandler struct {
+	app   *app.App
+	log   *logger.Logger
+	authn *auth.Authenticator
+}
+
+// NewWatchHandler creates the handler; a nil authn disables authentication, as for CalDAV.
+func NewWatchHandler(application *app.App, log *logger.Logger, authn *auth.Authenticator) *WatchHandler {
+	return &WatchHandler{app: application, log: log, authn: authn}
+}
+
+func (h *WatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	if h.authn != nil {
+		identity, err := h.authn.AuthenticateHTTP(r)
+		if err != nil {
+			h.log.Error(fmt.Sprintf("watch: authentication failed: %v", err))
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		ctx = auth.NewContext(ctx, identity)
+	}
+
+	var userID int
+	if v := r.URL.Query().Get("userId"); v != "" {
+		var err error
+		if userID, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "invalid userId", http.StatusBadRequest)
+			return
+		}
+	}
+	watcher, err := h.app.WatchEvents(ctx, userID)
+	if errors.Is(err, app.ErrWatchForbidden) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		h.log.Error(fmt.Sprintf("watch: %v", err))
+		http.Error(w, "something went wrong, pls try again a bit later", http.StatusServiceUnavailable)
+		return
+	}
+	defer watcher.Close()
+
+	// The stream outlives the server's write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.log.Error(fmt.Sprintf("watch: failed to clear write deadline: %v", err))
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		h.log.Error(fmt.Sprintf("watch: streaming not supported: %v", err))
+		return
+	}
+
+	heartbeat := time.NewTicker(watchHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": ping\n\n")
+		case change, ok := <-watcher.Changes():
+			if !ok {
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", watcher.Err())
+				_ = rc.Flush()
+				return
+			}
+			data, merr := protojson.Marshal(calendarGRPC.ToProtoEventChange(change))
+			if merr != nil {
+				h.log.Error(fmt.Sprintf("watch: failed to encode change: %v", merr))
+				continue
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", change.Type, data)
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return // the client went away
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package internalhttp
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/app"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/auth"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/logger"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+func TestWatchHandler(t *testing.T) {
+	log := logger.New("error")
+	application := app.NewWithConfig(config.Config{Storage: config.StorageConfig{Type: "memory"}}, log)
+	authn, err := auth.New(config.AuthConf{APIKeys: []config.APIKeyConf{{Key: "s3cret", Subject: "staff", UserID: 7}}})
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(AccessLogMiddleware(log, NewWatchHandler(application, log, authn)))
+	srv.Config.WriteTimeout = 100 * time.Millisecond // the stream has to outlive it
+	srv.Start()
+	t.Cleanup(srv.Close) // after the response bodies are closed
+
+	get := func(query string, key string) *http.Response {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+WatchPath+query, nil)
+		require.NoError(t, err)
+		if key != "" {
+			req.Header.Set(auth.APIKeyHeader, key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	require.Equal(t, http.StatusUnauthorized, get("", "").StatusCode)
+	require.Equal(t, http.StatusForbidden, get("?userId=8", "s3cret").StatusCode)
+	require.Equal(t, http.StatusBadRequest, get("?userId=seven", "s3cret").StatusCode)
+
+	resp := get("", "s3cret")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	time.Sleep(200 * time.Millisecond)
+	owner := 7
+	_, err = application.CreateEvent(context.Background(), storage.Event{Title: "Check-up", UserID: &owner})
+	require.NoError(t, err)
+
+	lines := bufio.NewScanner(resp.Body)
+	var frame []string
+	for lines.Scan() && lines.Text() != "" {
+		frame = append(frame, lines.Text())
+	}
+	require.Len(t, frame, 2, strings.Join(frame, "\n"))
+	require.Equal(t, "event: created", frame[0])
+	require.Contains(t, frame[1], `"eventId":1`)
+	require.Contains(t, frame[1], `"title":"Check-up"`)
+}
@@ -0,0 +1,60 @@
+// Package session carries a client's read-your-writes position between requests. After a
+// write the storage advances the session to a token naming the position of the write, the
+// server returns the token to the client, and a storage that serves reads from replicas
+// serves the client's next reads only from replicas that have caught up with it.
+//
+// Tokens are opaque outside the storage that issued them.
+package session
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// Header is the HTTP header carrying the session token.
+	Header = "X-Session-Token"
+	// MetadataKey is the gRPC metadata key carrying the session token.
+	MetadataKey = "x-session-token"
+)
+
+// Session holds the token of a request. It is safe for concurrent use.
+type Session struct {
+	mu    sync.Mutex
+	token string
+}
+
+// New returns a session starting at token, which may be empty.
+func New(token string) *Session {
+	return &Session{token: token}
+}
+
+// Token returns the current token, or an empty string for a nil session.
+func (s *Session) Token() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// Advance replaces the token after a write.
+func (s *Session) Advance(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx that carries the session.
+func NewContext(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, ctxKey{}, s)
+}
+
+// FromContext returns the session stored in ctx, or nil.
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(ctxKey{}).(*Session)
+	return s
+}
@@ -0,0 +1,27 @@
+package session
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextRoundTrip(t *testing.T) {
+	s := FromContext(context.Background())
+	if s != nil {
+		t.Fatalf("expected no session, got %v", s)
+	}
+	if got := s.Token(); got != "" {
+		t.Errorf("expected empty token of a nil session, got %q", got)
+	}
+
+	s = New("0/16B3748")
+	ctx := NewContext(context.Background(), s)
+	if got := FromContext(ctx); got != s {
+		t.Fatalf("expected the stored session, got %v", got)
+	}
+
+	s.Advance("0/16B3790")
+	if got := FromContext(ctx).Token(); got != "0/16B3790" {
+		t.Errorf("expected the advanced token, got %q", got)
+	}
+}
@@ -0,0 +1,54 @@
+package storage
+
+import "errors"
+
+var (
+	ErrEventNotFound    = errors.New("event not found")
+	ErrAttendeeNotFound = errors.New("attendee not found")
+	ErrAttendeeExists   = errors.New("user is already an attendee of the event")
+)
+
+// AttendeeRole is the part a participant plays in an event.
+type AttendeeRole string
+
+const (
+	RoleOrganizer AttendeeRole = "organizer"
+	RoleRequired  AttendeeRole = "required"
+	RoleOptional  AttendeeRole = "optional"
+)
+
+// Valid reports whether r is one of the known roles.
+func (r AttendeeRole) Valid() bool {
+	switch r {
+	case RoleOrganizer, RoleRequired, RoleOptional:
+		return true
+	}
+	return false
+}
+
+// RSVPStatus is the participant's answer to an invitation.
+type RSVPStatus string
+
+const (
+	RSVPPending   RSVPStatus = "pending"
+	RSVPAccepted  RSVPStatus = "accepted"
+	RSVPDeclined  RSVPStatus = "declined"
+	RSVPTentative RSVPStatus = "tentative"
+)
+
+// Valid reports whether s is one of the known statuses.
+func (s RSVPStatus) Valid() bool {
+	switch s {
+	case RSVPPending, RSVPAccepted, RSVPDeclined, RSVPTentative:
+		return true
+	}
+	return false
+}
+
+// Attendee is a calendar user invited to an event.
+type Attendee struct {
+	EventID int
+	UserID  int
+	Role    AttendeeRole
+	Status  RSVPStatus
+}
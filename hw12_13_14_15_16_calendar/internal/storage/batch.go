@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrBatchDuplicate = errors.New("item is repeated in the batch")
+
+// BatchError reports the items that made a batch operation fail. Batches are atomic,
+// so none of the batch was applied.
+type BatchError struct {
+	Items map[int]error // by index in the batch
+}
+
+func (e *BatchError) Error() string {
+	indexes := make([]int, 0, len(e.Items))
+	for i := range e.Items {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	if len(indexes) == 0 {
+		return "batch failed"
+	}
+	return fmt.Sprintf("batch failed: %d item(s), first #%d: %v", len(indexes), indexes[0], e.Items[indexes[0]])
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrInvalidCategory  = errors.New("invalid category")
+)
+
+// maxCategoryName matches the length limit of event titles.
+const maxCategoryName = 40
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// Category is a named tag, such as a consultation type, that events can carry.
+type Category struct {
+	ID    int
+	Name  string // unique; events refer to their categories by name
+	Color string // "#rrggbb", or "" for none
+}
+
+// Normalize trims the name, lowercases the color and validates both.
+func (c *Category) Normalize() error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Color = strings.ToLower(strings.TrimSpace(c.Color))
+	if c.Name == "" || len(c.Name) > maxCategoryName {
+		return fmt.Errorf("%w: name must have 1 to %d characters", ErrInvalidCategory, maxCategoryName)
+	}
+	if c.Color != "" && !colorPattern.MatchString(c.Color) {
+		return fmt.Errorf("%w: color %q is not #rrggbb", ErrInvalidCategory, c.Color)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package storage
+
+import "time"
+
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated" // includes changes to the attendees
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// Change describes a write to an event. Event is the stored event after a create or an
+// update and nil after a delete. Involved lists the owner and attendees a deleted event
+// had, so that only they are told about the deletion.
+type Change struct {
+	Type     ChangeType
+	EventID  int
+	Event    *Event
+	Involved []int
+	At       time.Time
+}
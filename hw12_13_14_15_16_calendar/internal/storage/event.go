@@ -1,15 +1,99 @@
 package storage
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
+var ErrInvalidTimeZone = errors.New("invalid time zone")
+
+// Event is a calendar event. The app checks the validate tags before storing it; see the
+// validation package.
 type Event struct {
-	ID          int // auto-increment or assigned
-	Title       string
+	ID          int    // auto-increment or assigned
+	Title       string `validate:"max:40"` // the VARCHAR(40) column
 	Description string
 	Start       *time.Time // nullable
-	End         *time.Time // nullable
-	AllDay      float64
+	End         *time.Time // nullable; exclusive, the day after the last day for all-day events
+	AllDay      bool
+	TimeZone    string  // IANA zone the event is planned in, e.g. "Europe/Berlin"; "" means UTC
 	Clinic      *string // nullable
-	UserID      *int    // nullable
+	UserID      *int    `validate:"min:1"` // nullable
 	Service     *string // nullable
+	Attendees   []Attendee
+	Tags        []string   // names of the event's categories, sorted
+	DeletedAt   *time.Time // set while the event is in the trash
+
+	// Set when a CalDAV client creates the event under a name of its own and kept as is
+	// by updates: the iCalendar UID it gave the event ("" for "{id}@calendar") and the
+	// resource name in the owner's calendar ("" for "{id}.ics").
+	UID          string
+	ResourceName string
+}
+
+// Involves reports whether the user owns or attends the event.
+func (e Event) Involves(userID int) bool {
+	if e.UserID != nil && *e.UserID == userID {
+		return true
+	}
+	for _, a := range e.Attendees {
+		if a.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Location returns the event's time zone, falling back to UTC when it is unset or unknown.
+func (e Event) Location() *time.Location {
+	loc, err := LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LoadLocation resolves an IANA zone name; the empty name means UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	return loc, nil
+}
+
+// Normalize validates the time zone and aligns all-day events to local midnights.
+// The calendar dates of Start and End are taken as written; an End with a time of day
+// is extended to the following midnight and a missing End makes a one-day event.
+func (e *Event) Normalize() error {
+	loc, err := LoadLocation(e.TimeZone)
+	if err != nil {
+		return err
+	}
+	e.TimeZone = loc.String()
+
+	if !e.AllDay || e.Start == nil {
+		return nil
+	}
+
+	y, m, d := e.Start.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1)
+	if e.End != nil {
+		y, m, d := e.End.Date()
+		last := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		h, mi, s := e.End.Clock()
+		if h != 0 || mi != 0 || s != 0 || e.End.Nanosecond() != 0 {
+			last = last.AddDate(0, 0, 1)
+		}
+		if last.After(start) {
+			end = last
+		}
+	}
+	e.Start, e.End = &start, &end
+	return nil
 }
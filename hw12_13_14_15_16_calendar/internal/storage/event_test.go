@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -20,7 +21,7 @@ func TestEventFields(t *testing.T) {
 		Description: "Annual check-up",
 		Start:       &start,
 		End:         &end,
-		AllDay:      0,
+		AllDay:      false,
 		Clinic:      &clinic,
 		UserID:      &userID,
 		Service:     &service,
@@ -47,8 +48,8 @@ func TestEventFields(t *testing.T) {
 		t.Errorf("expected End to be %v, got %v", end, event.End)
 	}
 
-	if event.AllDay != 0 {
-		t.Errorf("expected AllDay 0, got %v", event.AllDay)
+	if event.AllDay {
+		t.Errorf("expected AllDay false, got %v", event.AllDay)
 	}
 
 	if event.Clinic == nil || *event.Clinic != clinic {
@@ -63,3 +64,55 @@ func TestEventFields(t *testing.T) {
 		t.Errorf("expected Service %q, got %v", service, event.Service)
 	}
 }
+
+func TestEventNormalizeAllDay(t *testing.T) {
+	// Written in UTC, but the dates are what counts for an all-day event.
+	start := time.Date(2024, 7, 1, 15, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 7, 3, 0, 0, 0, 0, time.UTC)
+	event := Event{Start: &start, End: &end, AllDay: true, TimeZone: "America/New_York"}
+
+	if err := event.Normalize(); err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	ny := event.Location()
+	if want := time.Date(2024, 7, 1, 0, 0, 0, 0, ny); !event.Start.Equal(want) {
+		t.Errorf("expected start %v, got %v", want, event.Start)
+	}
+	if want := time.Date(2024, 7, 3, 0, 0, 0, 0, ny); !event.End.Equal(want) {
+		t.Errorf("expected end %v, got %v", want, event.End)
+	}
+
+	single := Event{Start: &start, AllDay: true}
+	if err := single.Normalize(); err != nil {
+		t.Fatalf("Normalize returned error: %v", err)
+	}
+	if single.TimeZone != "UTC" || single.End.Sub(*single.Start) != 24*time.Hour {
+		t.Errorf("expected a one-day UTC event, got %v - %v (%s)", single.Start, single.End, single.TimeZone)
+	}
+
+	bad := Event{TimeZone: "Mars/Olympus_Mons"}
+	if err := bad.Normalize(); !errors.Is(err, ErrInvalidTimeZone) {
+		t.Errorf("expected ErrInvalidTimeZone, got %v", err)
+	}
+}
+
+func TestCategoryNormalize(t *testing.T) {
+	c := Category{Name: "  Follow-up ", Color: "#A1B2C3"}
+	if err := c.Normalize(); err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	if c.Name != "Follow-up" || c.Color != "#a1b2c3" {
+		t.Errorf("unexpected normalized category %+v", c)
+	}
+
+	for _, bad := range []Category{
+		{Name: " "},
+		{Name: "This name is much longer than forty characters"},
+		{Name: "Urgent", Color: "red"},
+		{Name: "Urgent", Color: "#12345"},
+	} {
+		if err := bad.Normalize(); !errors.Is(err, ErrInvalidCategory) {
+			t.Errorf("%+v: expected ErrInvalidCategory, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Package filestorage keeps the calendar in memory and persists it in a directory: every
+// write is appended to a write-ahead log before the call returns, and the log is compacted
+// into a snapshot from time to time. On open the snapshot is loaded and the log replayed.
+//
+// The directory must be used by one process at a time.
+package filestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+// ErrClosed is returned by writes after Close or after a failed log write. The writes that
+// returned successfully are on disk; reopen the storage to continue.
+var ErrClosed = errors.New("file storage is closed")
+
+// Storage is safe for concurrent use. Reads are served from memory and run in parallel;
+// writes are serialized.
+type Storage struct {
+	mem *memorystorage.Storage
+
+	mu           sync.Mutex // serializes writes with their log records
+	dir          string
+	wal          *os.File
+	seq          int64     // sequence number of the last logged write
+	logged       int       // writes logged since the last snapshot
+	compactEvery int       // 0 compacts only on Close
+	now          time.Time // time of the write being applied
+	err          error     // set once writing is impossible
+	closed       bool
+}
+
+// Open loads the storage kept in dir, creating the directory if needed. The log is
+// compacted after every compactEvery writes; 0 compacts only on Close.
+func Open(dir string, compactEvery int) (*Storage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create storage directory: %w", err)
+	}
+	s := &Storage{dir: dir, compactEvery: compactEvery}
+	s.mem = memorystorage.NewWithClock(func() time.Time { return s.now })
+
+	if err := s.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := s.openLog(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close compacts the log and closes it.
+func (s *Storage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	var err error
+	if s.err == nil && s.logged > 0 {
+		err = s.compact()
+	}
+	if s.err == nil {
+		s.err = ErrClosed
+	}
+	s.closed = true
+	return errors.Join(err, s.wal.Close())
+}
+
+// Compact writes a snapshot of the storage and empties the log.
+func (s *Storage) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	return s.compact()
+}
+
+// write applies a write to memory and logs it on success. The record's time is the
+// clock of the write, so replaying it stamps deletions as the original did.
+func (s *Storage) write(ctx context.Context, rec record, apply func(ctx context.Context) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	rec.At = time.Now().UTC()
+	s.now = rec.At
+	if err := apply(ctx); err != nil {
+		return err
+	}
+	return s.log(rec)
+}
+
+func (s *Storage) CreateEvent(ctx context.Context, event storage.Event) (id int, err error) {
+	err = s.write(ctx, record{Op: opCreateEvent, Event: &event}, func(ctx context.Context) error {
+		id, err = s.mem.CreateEvent(ctx, event)
+		return err
+	})
+	return id, err
+}
+
+func (s *Storage) GetEvent(ctx context.Context, id int) (storage.Event, error) {
+	return s.mem.GetEvent(ctx, id)
+}
+
+func (s *Storage) ListEvents(ctx context.Context, filter storage.Filter) ([]storage.Event, error) {
+	return s.mem.ListEvents(ctx, filter)
+}
+
+func (s *Storage) UpdateEvent(ctx context.Context, event storage.Event) error {
+	return s.write(ctx, record{Op: opUpdateEvent, Event: &event}, func(ctx context.Context) error {
+		return s.mem.UpdateEvent(ctx, event)
+	})
+}
+
+func (s *Storage) DeleteEvent(ctx context.Context, id int) error {
+	return s.write(ctx, record{Op: opDeleteEvent, ID: id}, func(ctx context.Context) error {
+		return s.mem.DeleteEvent(ctx, id)
+	})
+}
+
+func (s *Storage) CreateEvents(ctx context.Context, events []storage.Event) (ids []int, err error) {
+	err = s.write(ctx, record{Op: opCreateEvents, Events: events}, func(ctx context.Context) error {
+		ids, err = s.mem.CreateEvents(ctx, events)
+		return err
+	})
+	return ids, err
+}
+
+func (s *Storage) DeleteEvents(ctx context.Context, ids []int) error {
+	return s.write(ctx, record{Op: opDeleteEvents, IDs: ids}, func(ctx context.Context) error {
+		return s.mem.DeleteEvents(ctx, ids)
+	})
+}
+
+func (s *Storage) AddAttendee(ctx context.Context, attendee storage.Attendee) error {
+	return s.write(ctx, record{Op: opAddAttendee, Attendee: &attendee}, func(ctx context.Context) error {
+		return s.mem.AddAttendee(ctx, attendee)
+	})
+}
+
+func (s *Storage) ListAttendees(ctx context.Context, eventID int) ([]storage.Attendee, error) {
+	return s.mem.ListAttendees(ctx, eventID)
+}
+
+func (s *Storage) UpdateAttendee(ctx context.Context, attendee storage.Attendee) error {
+	return s.write(ctx, record{Op: opUpdateAttendee, Attendee: &attendee}, func(ctx context.Context) error {
+		return s.mem.UpdateAttendee(ctx, attendee)
+	})
+}
+
+func (s *Storage) RemoveAttendee(ctx context.Context, eventID, userID int) error {
+	rec := record{Op: opRemoveAttendee, ID: eventID, UserID: userID}
+	return s.write(ctx, rec, func(ctx context.Context) error {
+		return s.mem.RemoveAttendee(ctx, eventID, userID)
+	})
+}
+
+func (s *Storage) AddHistory(ctx context.Context, entry storage.HistoryEntry) error {
+	return s.write(ctx, record{Op: opAddHistory, Entry: &entry}, func(ctx context.Context) error {
+		return s.mem.AddHistory(ctx, entry)
+	})
+}
+
+func (s *Storage) ListHistory(ctx context.Context, eventID int) ([]storage.HistoryEntry, error) {
+	return s.mem.ListHistory(ctx, eventID)
+}
+
+func (s *Storage) RecreateEvent(ctx context.Context, event storage.Event) error {
+	return s.write(ctx, record{Op: opRecreateEvent, Event: &event}, func(ctx context.Context) error {
+		return s.mem.RecreateEvent(ctx, event)
+	})
+}
+
+func (s *Storage) ListDeletedEvents(ctx context.Context) ([]storage.Event, error) {
+	return s.mem.ListDeletedEvents(ctx)
+}
+
+func (s *Storage) UndeleteEvent(ctx context.Context, id int) error {
+	return s.write(ctx, record{Op: opUndeleteEvent, ID: id}, func(ctx context.Context) error {
+		return s.mem.UndeleteEvent(ctx, id)
+	})
+}
+
+func (s *Storage) PurgeDeletedEvents(ctx context.Context, before time.Time) (n int, err error) {
+	err = s.write(ctx, record{Op: opPurgeDeleted, Before: before}, func(ctx context.Context) error {
+		n, err = s.mem.PurgeDeletedEvents(ctx, before)
+		return err
+	})
+	return n, err
+}
+
+func (s *Storage) CreateCategory(ctx context.Context, category storage.Category) (id int, err error) {
+	err = s.write(ctx, record{Op: opCreateCategory, Category: &category}, func(ctx context.Context) error {
+		id, err = s.mem.CreateCategory(ctx, category)
+		return err
+	})
+	return id, err
+}
+
+func (s *Storage) ListCategories(ctx context.Context) ([]storage.Category, error) {
+	return s.mem.ListCategories(ctx)
+}
+
+func (s *Storage) UpdateCategory(ctx context.Context, category storage.Category) error {
+	return s.write(ctx, record{Op: opUpdateCategory, Category: &category}, func(ctx context.Context) error {
+		return s.mem.UpdateCategory(ctx, category)
+	})
+}
+
+func (s *Storage) DeleteCategory(ctx context.Context, id int) error {
+	return s.write(ctx, record{Op: opDeleteCategory, ID: id}, func(ctx context.Context) error {
+		return s.mem.DeleteCategory(ctx, id)
+	})
+}
+
+func (s *Storage) SetEventTags(ctx context.Context, eventID int, tags []string) error {
+	return s.write(ctx, record{Op: opSetEventTags, ID: eventID, Tags: tags}, func(ctx context.Context) error {
+		return s.mem.SetEventTags(ctx, eventID, tags)
+	})
+}
+
+func (s *Storage) SearchEvents(ctx context.Context, query storage.SearchQuery) ([]storage.SearchResult, error) {
+	return s.mem.SearchEvents(ctx, query)
+}
@@ -0,0 +1,315 @@
+package filestorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Store {
+		t.Helper()
+		s, err := Open(t.TempDir(), 5)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, s.Close()) })
+		return s
+	})
+}
+
+// fill writes one of every kind of record and returns the ID of the live event.
+func fill(t *testing.T, s *Storage) int {
+	t.Helper()
+	ctx := context.Background()
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	ids, err := s.CreateEvents(ctx, []storage.Event{
+		{Title: "Flu shot", Description: "Seasonal vaccination", Start: &start},
+		{Title: "Check-up", Start: &start},
+	})
+	require.NoError(t, err)
+	id, err := s.CreateEvent(ctx, storage.Event{Title: "Surgery"})
+	require.NoError(t, err)
+	updated := storage.Event{ID: ids[0], Title: "Flu shot", Description: "For kids", Start: &start}
+	require.NoError(t, s.UpdateEvent(ctx, updated))
+	require.NoError(t, s.AddAttendee(ctx, storage.Attendee{EventID: ids[0], UserID: 3}))
+	require.NoError(t, s.AddAttendee(ctx, storage.Attendee{EventID: ids[0], UserID: 4}))
+	accepted := storage.Attendee{EventID: ids[0], UserID: 3, Status: storage.RSVPAccepted}
+	require.NoError(t, s.UpdateAttendee(ctx, accepted))
+	require.NoError(t, s.RemoveAttendee(ctx, ids[0], 4))
+
+	catID, err := s.CreateCategory(ctx, storage.Category{Name: "Vaccination", Color: "#00aa00"})
+	require.NoError(t, err)
+	_, err = s.CreateCategory(ctx, storage.Category{Name: "Old"})
+	require.NoError(t, err)
+	require.NoError(t, s.UpdateCategory(ctx, storage.Category{ID: catID, Name: "Vaccinations"}))
+	require.NoError(t, s.DeleteCategory(ctx, catID+1))
+	require.NoError(t, s.SetEventTags(ctx, ids[0], []string{"Vaccinations"}))
+
+	require.NoError(t, s.AddHistory(ctx, storage.HistoryEntry{EventID: id, Action: storage.ActionCreated, At: start}))
+	require.NoError(t, s.DeleteEvent(ctx, id))
+	require.NoError(t, s.DeleteEvents(ctx, []int{ids[1]}))
+	require.NoError(t, s.UndeleteEvent(ctx, ids[1]))
+	require.NoError(t, s.DeleteEvent(ctx, ids[1]))
+	n, err := s.PurgeDeletedEvents(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.NoError(t, s.RecreateEvent(ctx, storage.Event{ID: id, Title: "Surgery"}))
+	return ids[0]
+}
+
+func TestReopen(t *testing.T) {
+	for _, compactEvery := range []int{0, 1, 5} {
+		t.Run(fmt.Sprintf("compactEvery=%d", compactEvery), func(t *testing.T) {
+			dir := t.TempDir()
+			s, err := Open(dir, compactEvery)
+			require.NoError(t, err)
+			id := fill(t, s)
+			want := s.mem.State()
+
+			// Reopen without closing, as after a crash.
+			reopened, err := Open(dir, compactEvery)
+			require.NoError(t, err)
+			require.Equal(t, want, reopened.mem.State())
+
+			event, err := reopened.GetEvent(context.Background(), id)
+			require.NoError(t, err)
+			require.Equal(t, "For kids", event.Description)
+			require.Equal(t, []string{"Vaccinations"}, event.Tags)
+			require.Len(t, event.Attendees, 1)
+			results, err := reopened.SearchEvents(context.Background(),
+				storage.SearchQuery{Phrases: [][]string{{"kids"}}, Filter: storage.Filter{Period: storage.PeriodAll}})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			// IDs continue where they stopped.
+			next, err := reopened.CreateEvent(context.Background(), storage.Event{Title: "Next"})
+			require.NoError(t, err)
+			require.Equal(t, 4, next)
+			require.NoError(t, reopened.Close())
+		})
+	}
+}
+
+func TestDeletionTimeSurvivesReplay(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 0)
+	require.NoError(t, err)
+	id, err := s.CreateEvent(context.Background(), storage.Event{Title: "Surgery"})
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteEvent(context.Background(), id))
+	deleted, err := s.ListDeletedEvents(context.Background())
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	reopened, err := Open(dir, 0)
+	require.NoError(t, err)
+	replayed, err := reopened.ListDeletedEvents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, replayed, 1)
+	require.True(t, deleted[0].DeletedAt.Equal(*replayed[0].DeletedAt))
+}
+
+func TestCompaction(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 3)
+	require.NoError(t, err)
+	for i := 0; i < 7; i++ {
+		_, err := s.CreateEvent(context.Background(), storage.Event{Title: "Event"})
+		require.NoError(t, err)
+	}
+
+	// The snapshot holds six writes and the log the seventh.
+	require.Equal(t, 1, countRecords(t, dir))
+	_, err = os.Stat(filepath.Join(dir, snapshotFile))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Close())
+	require.Equal(t, 0, countRecords(t, dir))
+	_, err = s.CreateEvent(context.Background(), storage.Event{Title: "Late"})
+	require.ErrorIs(t, err, ErrClosed)
+
+	reopened, err := Open(dir, 3)
+	require.NoError(t, err)
+	events, err := reopened.ListEvents(context.Background(), storage.Filter{Period: storage.PeriodAll})
+	require.NoError(t, err)
+	require.Len(t, events, 7)
+}
+
+func TestStaleLogAfterSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 0)
+	require.NoError(t, err)
+	_, err = s.CreateEvent(context.Background(), storage.Event{Title: "First"})
+	require.NoError(t, err)
+	stale, err := os.ReadFile(filepath.Join(dir, logFile))
+	require.NoError(t, err)
+	require.NoError(t, s.Compact())
+
+	// A crash between writing the snapshot and emptying the log leaves records the
+	// snapshot already holds.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, logFile), stale, 0o600))
+	reopened, err := Open(dir, 0)
+	require.NoError(t, err)
+	_, err = reopened.CreateEvent(context.Background(), storage.Event{Title: "Second"})
+	require.NoError(t, err)
+
+	reopened, err = Open(dir, 0)
+	require.NoError(t, err)
+	events, err := reopened.ListEvents(context.Background(), storage.Filter{Period: storage.PeriodAll})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+}
+
+func TestTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 0)
+	require.NoError(t, err)
+	_, err = s.CreateEvent(context.Background(), storage.Event{Title: "Kept"})
+	require.NoError(t, err)
+
+	for name, tail := range map[string]string{
+		"partial line":      `00000000 {"seq":2,"op":"crea`,
+		"bad checksum line": `00000000 {"seq":2,"op":"create_event","event":{"Title":"Lost"}}` + "\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			appendLog(t, dir, tail)
+			reopened, err := Open(dir, 0)
+			require.NoError(t, err)
+			events, err := reopened.ListEvents(context.Background(), storage.Filter{Period: storage.PeriodAll})
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			require.Equal(t, 1, countRecords(t, dir))
+		})
+	}
+}
+
+func TestCorruptLog(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 0)
+	require.NoError(t, err)
+	for _, title := range []string{"First", "Second"} {
+		_, err = s.CreateEvent(context.Background(), storage.Event{Title: title})
+		require.NoError(t, err)
+	}
+
+	path := filepath.Join(dir, logFile)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, bytes.Replace(data, []byte("First"), []byte("Fir5t"), 1), 0o600))
+	_, err = Open(dir, 0)
+	require.ErrorIs(t, err, ErrCorrupt)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, snapshotFile), []byte("{"), 0o600))
+	_, err = Open(dir, 0)
+	require.ErrorIs(t, err, ErrCorrupt)
+}
+
+func TestFailedWriteIsNotLogged(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, s.DeleteEvent(context.Background(), 42), storage.ErrEventNotFound)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.CreateEvent(ctx, storage.Event{Title: "Canceled"})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 0, countRecords(t, dir))
+}
+
+func TestTransactionIsOneRecord(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 0)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	errAbort := errors.New("abort")
+	err = s.WithinTx(ctx, func(tx storage.Store) error {
+		_, err := tx.CreateEvent(ctx, storage.Event{Title: "Rolled back"})
+		require.NoError(t, err)
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+	require.Equal(t, 0, countRecords(t, dir))
+
+	var id int
+	require.NoError(t, s.WithinTx(ctx, func(tx storage.Store) error {
+		id, err = tx.CreateEvent(ctx, storage.Event{Title: "Surgery"})
+		require.NoError(t, err)
+		require.NoError(t, tx.AddAttendee(ctx, storage.Attendee{EventID: id, UserID: 3}))
+		// The writes of a committed nested transaction are logged with the outer one's.
+		return tx.(storage.Transactor).WithinTx(ctx, func(tx storage.Store) error {
+			return tx.AddHistory(ctx, storage.HistoryEntry{EventID: id, Action: storage.ActionCreated})
+		})
+	}))
+	require.Equal(t, 1, countRecords(t, dir))
+	want := s.mem.State()
+
+	reopened, err := Open(dir, 0)
+	require.NoError(t, err)
+	require.Equal(t, want, reopened.mem.State())
+	history, err := reopened.ListHistory(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.NoError(t, reopened.Close())
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 10)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				if _, err := s.CreateEvent(ctx, storage.Event{Title: "Event"}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				if _, err := s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	reopened, err := Open(dir, 10)
+	require.NoError(t, err)
+	events, err := reopened.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll})
+	require.NoError(t, err)
+	require.Len(t, events, 100)
+}
+
+func countRecords(t *testing.T, dir string) int {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, logFile))
+	require.NoError(t, err)
+	return bytes.Count(data, []byte("\n"))
+}
+
+func appendLog(t *testing.T, dir, data string) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(dir, logFile), os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+}
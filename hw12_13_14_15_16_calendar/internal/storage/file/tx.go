@@ -0,0 +1,201 @@
+package filestorage
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// WithinTx runs fn in a transaction of the memory storage and logs its writes as one
+// record just before the commit, so that a crash leaves either all of them on disk or none.
+// When the log write fails the transaction is rolled back. Writes wait for the transaction,
+// like they wait for each other.
+func (s *Storage) WithinTx(ctx context.Context, fn func(tx storage.Store) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	at := time.Now().UTC()
+	s.now = at
+	err := s.mem.WithinTx(ctx, func(memTx storage.Store) error {
+		tx := &txStore{tx: memTx}
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if len(tx.ops) == 0 {
+			return nil
+		}
+		return s.append(record{Op: opTx, At: at, Ops: tx.ops})
+	})
+	if err != nil {
+		return err
+	}
+	s.maybeCompact()
+	return nil
+}
+
+// txStore is the tx of WithinTx: it runs the operations on the memory transaction and
+// collects the records of the writes that succeed.
+type txStore struct {
+	tx  storage.Store
+	ops []record
+}
+
+// write runs a write on the memory transaction and keeps its record if it succeeds. The
+// record is encoded on commit, so it must not share slices with the caller.
+func (t *txStore) write(rec record, apply func() error) error {
+	if err := apply(); err != nil {
+		return err
+	}
+	t.ops = append(t.ops, rec)
+	return nil
+}
+
+// WithinTx runs fn in a nested transaction; its writes are logged with the outer one's if
+// fn succeeds.
+func (t *txStore) WithinTx(ctx context.Context, fn func(tx storage.Store) error) error {
+	return t.tx.(storage.Transactor).WithinTx(ctx, func(memTx storage.Store) error {
+		tx := &txStore{tx: memTx}
+		if err := fn(tx); err != nil {
+			return err
+		}
+		t.ops = append(t.ops, tx.ops...)
+		return nil
+	})
+}
+
+func (t *txStore) CreateEvent(ctx context.Context, event storage.Event) (id int, err error) {
+	err = t.write(record{Op: opCreateEvent, Event: &event}, func() error {
+		id, err = t.tx.CreateEvent(ctx, event)
+		return err
+	})
+	return id, err
+}
+
+func (t *txStore) GetEvent(ctx context.Context, id int) (storage.Event, error) {
+	return t.tx.GetEvent(ctx, id)
+}
+
+func (t *txStore) ListEvents(ctx context.Context, filter storage.Filter) ([]storage.Event, error) {
+	return t.tx.ListEvents(ctx, filter)
+}
+
+func (t *txStore) UpdateEvent(ctx context.Context, event storage.Event) error {
+	return t.write(record{Op: opUpdateEvent, Event: &event}, func() error {
+		return t.tx.UpdateEvent(ctx, event)
+	})
+}
+
+func (t *txStore) DeleteEvent(ctx context.Context, id int) error {
+	return t.write(record{Op: opDeleteEvent, ID: id}, func() error {
+		return t.tx.DeleteEvent(ctx, id)
+	})
+}
+
+func (t *txStore) CreateEvents(ctx context.Context, events []storage.Event) (ids []int, err error) {
+	err = t.write(record{Op: opCreateEvents, Events: slices.Clone(events)}, func() error {
+		ids, err = t.tx.CreateEvents(ctx, events)
+		return err
+	})
+	return ids, err
+}
+
+func (t *txStore) DeleteEvents(ctx context.Context, ids []int) error {
+	return t.write(record{Op: opDeleteEvents, IDs: slices.Clone(ids)}, func() error {
+		return t.tx.DeleteEvents(ctx, ids)
+	})
+}
+
+func (t *txStore) AddAttendee(ctx context.Context, attendee storage.Attendee) error {
+	return t.write(record{Op: opAddAttendee, Attendee: &attendee}, func() error {
+		return t.tx.AddAttendee(ctx, attendee)
+	})
+}
+
+func (t *txStore) ListAttendees(ctx context.Context, eventID int) ([]storage.Attendee, error) {
+	return t.tx.ListAttendees(ctx, eventID)
+}
+
+func (t *txStore) UpdateAttendee(ctx context.Context, attendee storage.Attendee) error {
+	return t.write(record{Op: opUpdateAttendee, Attendee: &attendee}, func() error {
+		return t.tx.UpdateAttendee(ctx, attendee)
+	})
+}
+
+func (t *txStore) RemoveAttendee(ctx context.Context, eventID, userID int) error {
+	return t.write(record{Op: opRemoveAttendee, ID: eventID, UserID: userID}, func() error {
+		return t.tx.RemoveAttendee(ctx, eventID, userID)
+	})
+}
+
+func (t *txStore) AddHistory(ctx context.Context, entry storage.HistoryEntry) error {
+	return t.write(record{Op: opAddHistory, Entry: &entry}, func() error {
+		return t.tx.AddHistory(ctx, entry)
+	})
+}
+
+func (t *txStore) ListHistory(ctx context.Context, eventID int) ([]storage.HistoryEntry, error) {
+	return t.tx.ListHistory(ctx, eventID)
+}
+
+func (t *txStore) RecreateEvent(ctx context.Context, event storage.Event) error {
+	return t.write(record{Op: opRecreateEvent, Event: &event}, func() error {
+		return t.tx.RecreateEvent(ctx, event)
+	})
+}
+
+func (t *txStore) ListDeletedEvents(ctx context.Context) ([]storage.Event, error) {
+	return t.tx.ListDeletedEvents(ctx)
+}
+
+func (t *txStore) UndeleteEvent(ctx context.Context, id int) error {
+	return t.write(record{Op: opUndeleteEvent, ID: id}, func() error {
+		return t.tx.UndeleteEvent(ctx, id)
+	})
+}
+
+func (t *txStore) PurgeDeletedEvents(ctx context.Context, before time.Time) (n int, err error) {
+	err = t.write(record{Op: opPurgeDeleted, Before: before}, func() error {
+		n, err = t.tx.PurgeDeletedEvents(ctx, before)
+		return err
+	})
+	return n, err
+}
+
+func (t *txStore) CreateCategory(ctx context.Context, category storage.Category) (id int, err error) {
+	err = t.write(record{Op: opCreateCategory, Category: &category}, func() error {
+		id, err = t.tx.CreateCategory(ctx, category)
+		return err
+	})
+	return id, err
+}
+
+func (t *txStore) ListCategories(ctx context.Context) ([]storage.Category, error) {
+	return t.tx.ListCategories(ctx)
+}
+
+func (t *txStore) UpdateCategory(ctx context.Context, category storage.Category) error {
+	return t.write(record{Op: opUpdateCategory, Category: &category}, func() error {
+		return t.tx.UpdateCategory(ctx, category)
+	})
+}
+
+func (t *txStore) DeleteCategory(ctx context.Context, id int) error {
+	return t.write(record{Op: opDeleteCategory, ID: id}, func() error {
+		return t.tx.DeleteCategory(ctx, id)
+	})
+}
+
+func (t *txStore) SetEventTags(ctx context.Context, eventID int, tags []string) error {
+	return t.write(record{Op: opSetEventTags, ID: eventID, Tags: slices.Clone(tags)}, func() error {
+		return t.tx.SetEventTags(ctx, eventID, tags)
+	})
+}
+
+func (t *txStore) SearchEvents(ctx context.Context, query storage.SearchQuery) ([]storage.SearchResult, error) {
+	return t.tx.SearchEvents(ctx, query)
+}
@@ -0,0 +1,320 @@
+package filestorage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	memorystorage "github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/memory"
+)
+
+// ErrCorrupt is returned by Open when the snapshot or the log cannot be read back.
+var ErrCorrupt = errors.New("file storage is corrupt")
+
+const (
+	logFile      = "wal.log"
+	snapshotFile = "snapshot.json"
+)
+
+const (
+	opCreateEvent    = "create_event"
+	opUpdateEvent    = "update_event"
+	opDeleteEvent    = "delete_event"
+	opCreateEvents   = "create_events"
+	opDeleteEvents   = "delete_events"
+	opAddAttendee    = "add_attendee"
+	opUpdateAttendee = "update_attendee"
+	opRemoveAttendee = "remove_attendee"
+	opAddHistory     = "add_history"
+	opRecreateEvent  = "recreate_event"
+	opUndeleteEvent  = "undelete_event"
+	opPurgeDeleted   = "purge_deleted"
+	opCreateCategory = "create_category"
+	opUpdateCategory = "update_category"
+	opDeleteCategory = "delete_category"
+	opSetEventTags   = "set_event_tags"
+	opTx             = "tx"
+)
+
+// record is a write in the log: the operation with its arguments. Replaying the records
+// in order on the snapshot they follow rebuilds the storage, IDs included.
+type record struct {
+	Seq      int64                 `json:"seq"`
+	Op       string                `json:"op"`
+	At       time.Time             `json:"at"`
+	ID       int                   `json:"id,omitempty"`
+	IDs      []int                 `json:"ids,omitempty"`
+	UserID   int                   `json:"userId,omitempty"`
+	Event    *storage.Event        `json:"event,omitempty"`
+	Events   []storage.Event       `json:"events,omitempty"`
+	Attendee *storage.Attendee     `json:"attendee,omitempty"`
+	Entry    *storage.HistoryEntry `json:"entry,omitempty"`
+	Category *storage.Category     `json:"category,omitempty"`
+	Tags     []string              `json:"tags,omitempty"`
+	Before   time.Time             `json:"before,omitempty"`
+	Ops      []record              `json:"ops,omitempty"` // the writes of a transaction
+}
+
+// snapshot is the content of the snapshot file: the storage after the write Seq.
+type snapshot struct {
+	Seq   int64               `json:"seq"`
+	State memorystorage.State `json:"state"`
+}
+
+// log appends a record to the log and syncs it; the caller must hold mu. When that fails
+// the memory is ahead of the disk, so the storage refuses further writes.
+func (s *Storage) log(rec record) error {
+	if err := s.append(rec); err != nil {
+		return err
+	}
+	s.maybeCompact()
+	return nil
+}
+
+// append writes a record to the log and syncs it, like log, but does not compact: the
+// memory may not hold the write yet.
+func (s *Storage) append(rec record) error {
+	rec.Seq = s.seq + 1
+	data, err := json.Marshal(rec)
+	if err == nil {
+		_, err = s.wal.Write(encodeLine(data))
+	}
+	if err == nil {
+		err = s.wal.Sync()
+	}
+	if err != nil {
+		s.err = fmt.Errorf("%w: log write failed: %w", ErrClosed, err)
+		return s.err
+	}
+	s.seq = rec.Seq
+	s.logged++
+	return nil
+}
+
+// maybeCompact compacts the log every compactEvery writes; the caller must hold mu.
+func (s *Storage) maybeCompact() {
+	if s.compactEvery > 0 && s.logged%s.compactEvery == 0 {
+		// The log still holds every write when compaction fails; it is retried after
+		// the next compactEvery writes.
+		s.compact() //nolint:errcheck
+	}
+}
+
+// encodeLine frames a record as "<crc32 in hex> <json>\n", so that a write torn by a
+// crash is recognized on replay.
+func encodeLine(data []byte) []byte {
+	line := make([]byte, 0, len(data)+10)
+	line = fmt.Appendf(line, "%08x ", crc32.ChecksumIEEE(data))
+	line = append(line, data...)
+	return append(line, '\n')
+}
+
+func decodeLine(line []byte) (record, error) {
+	var rec record
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	sum, data, ok := bytes.Cut(line, []byte(" "))
+	if !ok {
+		return rec, errors.New("missing checksum")
+	}
+	want, err := strconv.ParseUint(string(sum), 16, 32)
+	if err != nil {
+		return rec, fmt.Errorf("invalid checksum: %w", err)
+	}
+	if crc32.ChecksumIEEE(data) != uint32(want) {
+		return rec, errors.New("checksum mismatch")
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, err
+	}
+	return rec, nil
+}
+
+func (s *Storage) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, snapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("%w: snapshot: %w", ErrCorrupt, err)
+	}
+	s.mem.Load(snap.State)
+	s.seq = snap.Seq
+	return nil
+}
+
+// openLog opens the log and replays the records that follow the snapshot. A torn last
+// record, left by a crash during a write that never returned, is cut off.
+func (s *Storage) openLog() error {
+	f, err := os.OpenFile(filepath.Join(s.dir, logFile), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open log: %w", err)
+	}
+	if err := s.replay(f); err != nil {
+		f.Close()
+		return err
+	}
+	s.wal = f
+	return nil
+}
+
+func (s *Storage) replay(f *os.File) error {
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) == 0 {
+				return nil
+			}
+			return truncate(f, offset)
+		}
+		if err != nil {
+			return fmt.Errorf("read log: %w", err)
+		}
+		rec, err := decodeLine(line)
+		if err != nil {
+			if _, peekErr := r.Peek(1); errors.Is(peekErr, io.EOF) {
+				return truncate(f, offset)
+			}
+			return fmt.Errorf("%w: log at offset %d: %w", ErrCorrupt, offset, err)
+		}
+		offset += int64(len(line))
+
+		if rec.Seq <= s.seq {
+			continue // already in the snapshot
+		}
+		if rec.Seq != s.seq+1 {
+			return fmt.Errorf("%w: log record %d follows %d", ErrCorrupt, rec.Seq, s.seq)
+		}
+		if err := s.apply(rec); err != nil {
+			return fmt.Errorf("%w: replay record %d (%s): %w", ErrCorrupt, rec.Seq, rec.Op, err)
+		}
+		s.seq = rec.Seq
+		s.logged++
+	}
+}
+
+func truncate(f *os.File, size int64) error {
+	if err := f.Truncate(size); err != nil {
+		return fmt.Errorf("truncate torn log record: %w", err)
+	}
+	return f.Sync()
+}
+
+// apply repeats a logged write on the memory storage. Only successful writes are logged,
+// so an error means the log does not fit the snapshot.
+func (s *Storage) apply(rec record) error {
+	ctx := context.Background()
+	s.now = rec.At
+	var err error
+	switch rec.Op {
+	case opCreateEvent:
+		_, err = s.mem.CreateEvent(ctx, *rec.Event)
+	case opUpdateEvent:
+		err = s.mem.UpdateEvent(ctx, *rec.Event)
+	case opDeleteEvent:
+		err = s.mem.DeleteEvent(ctx, rec.ID)
+	case opCreateEvents:
+		_, err = s.mem.CreateEvents(ctx, rec.Events)
+	case opDeleteEvents:
+		err = s.mem.DeleteEvents(ctx, rec.IDs)
+	case opAddAttendee:
+		err = s.mem.AddAttendee(ctx, *rec.Attendee)
+	case opUpdateAttendee:
+		err = s.mem.UpdateAttendee(ctx, *rec.Attendee)
+	case opRemoveAttendee:
+		err = s.mem.RemoveAttendee(ctx, rec.ID, rec.UserID)
+	case opAddHistory:
+		err = s.mem.AddHistory(ctx, *rec.Entry)
+	case opRecreateEvent:
+		err = s.mem.RecreateEvent(ctx, *rec.Event)
+	case opUndeleteEvent:
+		err = s.mem.UndeleteEvent(ctx, rec.ID)
+	case opPurgeDeleted:
+		_, err = s.mem.PurgeDeletedEvents(ctx, rec.Before)
+	case opCreateCategory:
+		_, err = s.mem.CreateCategory(ctx, *rec.Category)
+	case opUpdateCategory:
+		err = s.mem.UpdateCategory(ctx, *rec.Category)
+	case opDeleteCategory:
+		err = s.mem.DeleteCategory(ctx, rec.ID)
+	case opSetEventTags:
+		err = s.mem.SetEventTags(ctx, rec.ID, rec.Tags)
+	case opTx:
+		// The writes of a transaction were logged only after all of them succeeded,
+		// so they are replayed one by one.
+		for _, op := range rec.Ops {
+			op.At = rec.At
+			if err = s.apply(op); err != nil {
+				break
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown operation %q", rec.Op)
+	}
+	return err
+}
+
+// compact writes the snapshot next to the old one, renames it into place and empties the
+// log; the caller must hold mu. A crash before the log is emptied is harmless: the records
+// the snapshot already contains are skipped on replay.
+func (s *Storage) compact() error {
+	path := filepath.Join(s.dir, snapshotFile)
+	data, err := json.Marshal(snapshot{Seq: s.seq, State: s.mem.State()})
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if err := writeFileSync(path+".tmp", data); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("replace snapshot: %w", err)
+	}
+	if err := syncDir(s.dir); err != nil {
+		return fmt.Errorf("sync storage directory: %w", err)
+	}
+	if err := truncate(s.wal, 0); err != nil {
+		return err
+	}
+	s.logged = 0
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
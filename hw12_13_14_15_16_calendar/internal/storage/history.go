@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrEventExists = errors.New("event already exists")
+
+type HistoryAction string
+
+const (
+	ActionCreated  HistoryAction = "created"
+	ActionUpdated  HistoryAction = "updated" // includes changes to the attendees
+	ActionDeleted  HistoryAction = "deleted"
+	ActionRestored HistoryAction = "restored"
+)
+
+// HistoryEntry records one write to an event: who made it, when, and the event before
+// and after it. Before is nil for created and restored events, After for deleted ones.
+type HistoryEntry struct {
+	ID          int
+	EventID     int
+	Action      HistoryAction
+	Actor       string // subject of the authenticated caller, "anonymous" without authentication
+	ActorUserID *int   // calendar user of the caller, when known
+	At          time.Time
+	Before      *Event
+	After       *Event
+}
+
+// FieldChange is a field that differs between the event before and after a write,
+// rendered as text; an empty value means the field was not set.
+type FieldChange struct {
+	Field         string
+	Before, After string
+}
+
+// Changes returns the fields changed by the write, in a fixed order.
+func (h HistoryEntry) Changes() []FieldChange {
+	before, after := eventFields(h.Before), eventFields(h.After)
+	var changes []FieldChange
+	for _, field := range fieldOrder {
+		if before[field] != after[field] {
+			changes = append(changes, FieldChange{Field: field, Before: before[field], After: after[field]})
+		}
+	}
+	return changes
+}
+
+var fieldOrder = []string{
+	"title", "description", "start", "end", "allDay", "timeZone", "clinic", "userId", "service", "attendees", "tags",
+}
+
+// eventFields renders the fields of an event the way the API shows them; nil has no fields.
+func eventFields(e *Event) map[string]string {
+	fields := make(map[string]string, len(fieldOrder))
+	if e == nil {
+		return fields
+	}
+	loc := e.Location()
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(loc).Format(time.RFC3339)
+	}
+	text := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	fields["title"] = e.Title
+	fields["description"] = e.Description
+	fields["start"] = formatTime(e.Start)
+	fields["end"] = formatTime(e.End)
+	fields["allDay"] = strconv.FormatBool(e.AllDay)
+	fields["timeZone"] = loc.String()
+	fields["clinic"] = text(e.Clinic)
+	if e.UserID != nil {
+		fields["userId"] = strconv.Itoa(*e.UserID)
+	}
+	fields["service"] = text(e.Service)
+
+	attendees := make([]string, len(e.Attendees))
+	for i, a := range e.Attendees {
+		attendees[i] = fmt.Sprintf("%d (%s, %s)", a.UserID, a.Role, a.Status)
+	}
+	sort.Strings(attendees)
+	fields["attendees"] = strings.Join(attendees, ", ")
+	fields["tags"] = strings.Join(e.Tags, ", ")
+	return fields
+}
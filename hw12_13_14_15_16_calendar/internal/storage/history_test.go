@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryEntry_Changes(t *testing.T) {
+	start := time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)
+	clinic := "Main street 1"
+	before := Event{ID: 1, Title: "Check-up", Start: &start, TimeZone: "Europe/Berlin", Clinic: &clinic}
+
+	moved := start.Add(time.Hour)
+	after := before
+	after.Start = &moved
+	after.Clinic = nil
+	after.Attendees = []Attendee{{EventID: 1, UserID: 3, Role: RoleRequired, Status: RSVPPending}}
+
+	require.Equal(t, []FieldChange{
+		{Field: "start", Before: "2024-03-11T10:00:00+01:00", After: "2024-03-11T11:00:00+01:00"},
+		{Field: "clinic", Before: "Main street 1", After: ""},
+		{Field: "attendees", Before: "", After: "3 (required, pending)"},
+	}, HistoryEntry{Action: ActionUpdated, Before: &before, After: &after}.Changes())
+
+	created := HistoryEntry{Action: ActionCreated, After: &before}.Changes()
+	require.Equal(t, FieldChange{Field: "title", After: "Check-up"}, created[0])
+	require.Empty(t, HistoryEntry{Action: ActionUpdated, Before: &before, After: &before}.Changes())
+}
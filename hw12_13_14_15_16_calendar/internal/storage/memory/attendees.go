@@ -0,0 +1,123 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// withDetails returns event with a copy of its attendee list and its tags; the caller
+// must hold mu. Attendee lists are kept sorted by user ID, matching the Postgres backend.
+func (s *Storage) withDetails(event storage.Event) storage.Event {
+	if list := s.attendees[event.ID]; len(list) > 0 {
+		event.Attendees = append([]storage.Attendee(nil), list...)
+	}
+	event.Tags = s.tagNames(event.ID)
+	return event
+}
+
+// AddAttendee invites a user to an existing event.
+func (s *Storage) AddAttendee(ctx context.Context, attendee storage.Attendee) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("context canceled before acquiring lock: %w", ctx.Err())
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
+	default:
+		if _, ok := s.events[attendee.EventID]; !ok {
+			return ErrNotFound
+		}
+		list := s.attendees[attendee.EventID]
+		i := sort.Search(len(list), func(i int) bool { return list[i].UserID >= attendee.UserID })
+		if i < len(list) && list[i].UserID == attendee.UserID {
+			return storage.ErrAttendeeExists
+		}
+		s.saveEvent(attendee.EventID)
+		s.attendees[attendee.EventID] = append(list[:i:i], append([]storage.Attendee{attendee}, list[i:]...)...)
+		return nil
+	}
+}
+
+// ListAttendees returns the attendees of an event ordered by user ID.
+func (s *Storage) ListAttendees(ctx context.Context, eventID int) ([]storage.Attendee, error) {
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("context canceled before acquiring lock: %w", ctx.Err())
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
+	default:
+		if _, ok := s.events[eventID]; !ok {
+			return nil, ErrNotFound
+		}
+		return append([]storage.Attendee{}, s.attendees[eventID]...), nil
+	}
+}
+
+// UpdateAttendee changes the role and RSVP status of an attendee.
+func (s *Storage) UpdateAttendee(ctx context.Context, attendee storage.Attendee) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("context canceled before acquiring lock: %w", ctx.Err())
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
+	default:
+		for i, a := range s.attendees[attendee.EventID] {
+			if a.UserID == attendee.UserID {
+				s.saveEvent(attendee.EventID)
+				s.attendees[attendee.EventID][i] = attendee
+				return nil
+			}
+		}
+		return storage.ErrAttendeeNotFound
+	}
+}
+
+// RemoveAttendee withdraws a user's invitation to an event.
+func (s *Storage) RemoveAttendee(ctx context.Context, eventID, userID int) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("context canceled before acquiring lock: %w", ctx.Err())
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
+	default:
+		list := s.attendees[eventID]
+		for i, a := range list {
+			if a.UserID == userID {
+				s.saveEvent(eventID)
+				s.attendees[eventID] = append(list[:i:i], list[i+1:]...)
+				return nil
+			}
+		}
+		return storage.ErrAttendeeNotFound
+	}
+}
@@ -0,0 +1,61 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CreateEvents stores all events under a single lock and returns their IDs in order.
+func (s *Storage) CreateEvents(ctx context.Context, events []storage.Event) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context canceled after acquiring lock: %w", err)
+	}
+
+	ids := make([]int, len(events))
+	for i, event := range events {
+		event.ID = s.nextID
+		s.nextID++
+		event.Attendees, event.Tags = nil, nil
+		s.saveEvent(event.ID)
+		s.events[event.ID] = event
+		s.index.add(event)
+		ids[i] = event.ID
+	}
+	return ids, nil
+}
+
+// DeleteEvents moves all events to the trash or, if any of them is missing, none.
+// Missing and repeated IDs are reported in a *storage.BatchError.
+func (s *Storage) DeleteEvents(ctx context.Context, ids []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled after acquiring lock: %w", err)
+	}
+
+	failed := make(map[int]error)
+	seen := make(map[int]bool, len(ids))
+	for i, id := range ids {
+		if _, ok := s.events[id]; !ok {
+			failed[i] = ErrNotFound
+		} else if seen[id] {
+			failed[i] = fmt.Errorf("%w: event %d", storage.ErrBatchDuplicate, id)
+		}
+		seen[id] = true
+	}
+	if len(failed) > 0 {
+		return &storage.BatchError{Items: failed}
+	}
+
+	now := s.now().UTC()
+	for _, id := range ids {
+		s.moveToTrash(id, now)
+	}
+	return nil
+}
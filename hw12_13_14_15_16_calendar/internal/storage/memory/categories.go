@@ -0,0 +1,161 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CreateCategory stores a new category and returns its ID.
+func (s *Storage) CreateCategory(ctx context.Context, category storage.Category) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.categoryByName(category.Name); ok {
+		return 0, storage.ErrCategoryExists
+	}
+	s.nextCategoryID++
+	category.ID = s.nextCategoryID
+	s.saveCategory(category.ID)
+	s.categories[category.ID] = category
+	return category.ID, nil
+}
+
+// ListCategories returns all categories ordered by name.
+func (s *Storage) ListCategories(ctx context.Context) ([]storage.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	categories := make([]storage.Category, 0, len(s.categories))
+	for _, category := range s.categories {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+// UpdateCategory renames or recolors a category. Renaming it renames the tag on all its
+// events.
+func (s *Storage) UpdateCategory(ctx context.Context, category storage.Category) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.categories[category.ID]; !ok {
+		return storage.ErrCategoryNotFound
+	}
+	if other, ok := s.categoryByName(category.Name); ok && other.ID != category.ID {
+		return storage.ErrCategoryExists
+	}
+	s.saveCategory(category.ID)
+	s.categories[category.ID] = category
+	return nil
+}
+
+// DeleteCategory removes a category and its tag from all events.
+func (s *Storage) DeleteCategory(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.categories[id]; !ok {
+		return storage.ErrCategoryNotFound
+	}
+	s.saveCategory(id)
+	delete(s.categories, id)
+	for eventID, set := range s.tags {
+		if set[id] {
+			s.saveEvent(eventID)
+			delete(set, id)
+		}
+	}
+	return nil
+}
+
+// SetEventTags replaces the tags of an event. All tags must name existing categories.
+func (s *Storage) SetEventTags(ctx context.Context, eventID int, tags []string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[eventID]; !ok {
+		return ErrNotFound
+	}
+	ids := s.knownCategories(tags)
+	names := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		names[tag] = true
+	}
+	if len(ids) != len(names) {
+		return storage.ErrCategoryNotFound
+	}
+	s.saveEvent(eventID)
+	s.setTags(eventID, ids)
+	return nil
+}
+
+// categoryByName looks up a category; the caller must hold mu.
+func (s *Storage) categoryByName(name string) (storage.Category, bool) {
+	for _, category := range s.categories {
+		if category.Name == name {
+			return category, true
+		}
+	}
+	return storage.Category{}, false
+}
+
+// knownCategories returns the IDs of the existing categories among the names, without
+// repetitions; the caller must hold mu.
+func (s *Storage) knownCategories(names []string) []int {
+	var ids []int
+	seen := make(map[int]bool, len(names))
+	for _, name := range names {
+		if category, ok := s.categoryByName(name); ok && !seen[category.ID] {
+			seen[category.ID] = true
+			ids = append(ids, category.ID)
+		}
+	}
+	return ids
+}
+
+// setTags replaces the category set of an event; the caller must hold mu.
+func (s *Storage) setTags(eventID int, categoryIDs []int) {
+	if len(categoryIDs) == 0 {
+		delete(s.tags, eventID)
+		return
+	}
+	set := make(map[int]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		set[id] = true
+	}
+	s.tags[eventID] = set
+}
+
+// tagNames returns the sorted category names of an event; the caller must hold mu.
+func (s *Storage) tagNames(eventID int) []string {
+	var names []string
+	for id := range s.tags[eventID] {
+		names = append(names, s.categories[id].Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,91 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// AddHistory appends an entry to the event history.
+func (s *Storage) AddHistory(ctx context.Context, entry storage.HistoryEntry) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextHistoryID++
+	entry.ID = s.nextHistoryID
+	entry.Before, entry.After = snapshot(entry.Before), snapshot(entry.After)
+	s.history = append(s.history, entry)
+	return nil
+}
+
+// ListHistory returns the history of an event, oldest first. The history outlives the
+// event, so a deleted event still has one.
+func (s *Storage) ListHistory(ctx context.Context, eventID int) ([]storage.HistoryEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []storage.HistoryEntry
+	for _, entry := range s.history {
+		if entry.EventID == eventID {
+			entry.Before, entry.After = snapshot(entry.Before), snapshot(entry.After)
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// RecreateEvent stores a deleted event again under its ID, with its attendees and the
+// tags whose categories still exist.
+func (s *Storage) RecreateEvent(ctx context.Context, event storage.Event) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.events[event.ID]; ok {
+		return storage.ErrEventExists
+	}
+	if _, ok := s.trash[event.ID]; ok {
+		return storage.ErrEventExists
+	}
+	s.saveEvent(event.ID)
+	if len(event.Attendees) > 0 {
+		list := make([]storage.Attendee, len(event.Attendees))
+		for i, a := range event.Attendees {
+			a.EventID = event.ID
+			list[i] = a
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].UserID < list[j].UserID })
+		s.attendees[event.ID] = list
+	}
+	s.setTags(event.ID, s.knownCategories(event.Tags))
+	event.Attendees, event.Tags, event.DeletedAt = nil, nil, nil
+	s.events[event.ID] = event
+	s.index.add(event)
+	if event.ID >= s.nextID {
+		s.nextID = event.ID + 1
+	}
+	return nil
+}
+
+// snapshot copies an event so that the history does not share its attendee list.
+func snapshot(event *storage.Event) *storage.Event {
+	if event == nil {
+		return nil
+	}
+	c := *event
+	c.Attendees = append([]storage.Attendee(nil), event.Attendees...)
+	return &c
+}
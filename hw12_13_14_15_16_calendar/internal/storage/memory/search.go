@@ -0,0 +1,220 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// Ranking weights of title and description matches, as the A and B weights of Postgres
+// ts_rank.
+const (
+	titleWeight       = 1.0
+	descriptionWeight = 0.4
+)
+
+// fragmentWords is the length of the description fragment of a search result.
+const fragmentWords = 30
+
+// searchIndex is an inverted index of the words in the titles and descriptions of the live
+// events. Description positions follow the title positions after a gap, so that a phrase
+// never spans both.
+type searchIndex struct {
+	postings map[string]map[int][]int // ascending positions by event ID by word
+	words    map[int][]string         // distinct words by event ID
+	titleLen map[int]int              // number of title words by event ID
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings: make(map[string]map[int][]int),
+		words:    make(map[int][]string),
+		titleLen: make(map[int]int),
+	}
+}
+
+// add indexes an event, replacing its previous entry.
+func (x *searchIndex) add(event storage.Event) {
+	x.remove(event.ID)
+	title, description := storage.SearchWords(event.Title), storage.SearchWords(event.Description)
+	positions := make(map[string][]int)
+	for i, word := range title {
+		positions[word] = append(positions[word], i)
+	}
+	for i, word := range description {
+		positions[word] = append(positions[word], len(title)+1+i)
+	}
+
+	for word, list := range positions {
+		if x.postings[word] == nil {
+			x.postings[word] = make(map[int][]int)
+		}
+		x.postings[word][event.ID] = list
+		x.words[event.ID] = append(x.words[event.ID], word)
+	}
+	x.titleLen[event.ID] = len(title)
+}
+
+func (x *searchIndex) remove(id int) {
+	for _, word := range x.words[id] {
+		delete(x.postings[word], id)
+		if len(x.postings[word]) == 0 {
+			delete(x.postings, word)
+		}
+	}
+	delete(x.words, id)
+	delete(x.titleLen, id)
+}
+
+// search returns the rank of every event in which all phrases occur. Each occurrence of a
+// phrase adds the weight of the field it occurs in.
+func (x *searchIndex) search(phrases [][]string) map[int]float64 {
+	ranks := make(map[int]float64)
+	for id := range x.postings[phrases[0][0]] {
+		var rank float64
+		for _, phrase := range phrases {
+			starts := x.phraseStarts(id, phrase)
+			if len(starts) == 0 {
+				rank = 0
+				break
+			}
+			for _, pos := range starts {
+				if pos < x.titleLen[id] {
+					rank += titleWeight
+				} else {
+					rank += descriptionWeight
+				}
+			}
+		}
+		if rank > 0 {
+			ranks[id] = rank
+		}
+	}
+	return ranks
+}
+
+// phraseStarts returns the positions at which the phrase occurs in an event.
+func (x *searchIndex) phraseStarts(id int, phrase []string) []int {
+	var starts []int
+	for _, pos := range x.postings[phrase[0]][id] {
+		found := true
+		for k, word := range phrase[1:] {
+			list := x.postings[word][id]
+			i := sort.SearchInts(list, pos+k+1)
+			if i == len(list) || list[i] != pos+k+1 {
+				found = false
+				break
+			}
+		}
+		if found {
+			starts = append(starts, pos)
+		}
+	}
+	return starts
+}
+
+// SearchEvents returns the live events matching the query, highest rank first.
+func (s *Storage) SearchEvents(ctx context.Context, query storage.SearchQuery) ([]storage.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	words := make(map[string]bool)
+	for _, phrase := range query.Phrases {
+		for _, word := range phrase {
+			words[word] = true
+		}
+	}
+	var results []storage.SearchResult
+	for id, rank := range s.index.search(query.Phrases) {
+		event := s.withDetails(s.events[id])
+		if !query.Filter.Matches(event) {
+			continue
+		}
+		results = append(results, storage.SearchResult{
+			Event:       event,
+			Rank:        rank,
+			Title:       highlight(event.Title, words, 0),
+			Description: highlight(event.Description, words, fragmentWords),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return eventLess(results[i].Event, results[j].Event)
+	})
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
+
+// highlight marks the words of text that are among the given words. With maxWords > 0,
+// longer texts are cut to that many words, starting shortly before the first match.
+func highlight(text string, words map[string]bool, maxWords int) string {
+	type span struct{ start, end int }
+	var spans []span
+	first := -1
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			i += size
+			continue
+		}
+		start := i
+		for i < len(text) {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			i += size
+		}
+		if first < 0 && words[strings.ToLower(text[start:i])] {
+			first = len(spans)
+		}
+		spans = append(spans, span{start, i})
+	}
+
+	from, to := 0, len(spans)
+	if maxWords > 0 && len(spans) > maxWords {
+		from = max(0, min(first-maxWords/4, len(spans)-maxWords))
+		to = from + maxWords
+	}
+	if from == to {
+		return text
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	last := 0
+	if from > 0 {
+		last = spans[from].start
+	}
+	for _, sp := range spans[from:to] {
+		b.WriteString(text[last:sp.start])
+		if word := text[sp.start:sp.end]; words[strings.ToLower(word)] {
+			b.WriteString(storage.HighlightStart + word + storage.HighlightStop)
+		} else {
+			b.WriteString(word)
+		}
+		last = sp.end
+	}
+	if to < len(spans) {
+		b.WriteString("…")
+	} else {
+		b.WriteString(text[last:])
+	}
+	return b.String()
+}
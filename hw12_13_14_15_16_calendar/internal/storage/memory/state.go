@@ -0,0 +1,111 @@
+package memorystorage
+
+import (
+	"sort"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// State is a copy of the whole content of a storage, for persisting it elsewhere.
+type State struct {
+	Events         []storage.Event // live events with their attendees, by ID
+	Trash          []storage.Event // deleted events with their attendees, by ID
+	Categories     []storage.Category
+	Tags           map[int][]int // category IDs by event ID
+	History        []storage.HistoryEntry
+	NextID         int
+	NextCategoryID int
+	NextHistoryID  int
+}
+
+// State returns a copy of the content of the storage.
+func (s *Storage) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state()
+}
+
+func (s *Storage) state() State {
+	st := State{
+		Events:         make([]storage.Event, 0, len(s.events)),
+		Trash:          make([]storage.Event, 0, len(s.trash)),
+		Categories:     make([]storage.Category, 0, len(s.categories)),
+		Tags:           make(map[int][]int, len(s.tags)),
+		History:        make([]storage.HistoryEntry, len(s.history)),
+		NextID:         s.nextID,
+		NextCategoryID: s.nextCategoryID,
+		NextHistoryID:  s.nextHistoryID,
+	}
+	for id, event := range s.events {
+		event.Attendees = append([]storage.Attendee(nil), s.attendees[id]...)
+		st.Events = append(st.Events, event)
+	}
+	for _, event := range s.trash {
+		event.Attendees = append([]storage.Attendee(nil), event.Attendees...)
+		st.Trash = append(st.Trash, event)
+	}
+	for _, category := range s.categories {
+		st.Categories = append(st.Categories, category)
+	}
+	for eventID, set := range s.tags {
+		ids := make([]int, 0, len(set))
+		for id := range set {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		st.Tags[eventID] = ids
+	}
+	for i, entry := range s.history {
+		entry.Before, entry.After = snapshot(entry.Before), snapshot(entry.After)
+		st.History[i] = entry
+	}
+	sort.Slice(st.Events, func(i, j int) bool { return st.Events[i].ID < st.Events[j].ID })
+	sort.Slice(st.Trash, func(i, j int) bool { return st.Trash[i].ID < st.Trash[j].ID })
+	sort.Slice(st.Categories, func(i, j int) bool { return st.Categories[i].ID < st.Categories[j].ID })
+	return st
+}
+
+// Load replaces the content of the storage with a copy of st.
+func (s *Storage) Load(st State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load(st)
+}
+
+func (s *Storage) load(st State) {
+	s.events = make(map[int]storage.Event, len(st.Events))
+	s.attendees = make(map[int][]storage.Attendee)
+	s.trash = make(map[int]storage.Event, len(st.Trash))
+	s.categories = make(map[int]storage.Category, len(st.Categories))
+	s.tags = make(map[int]map[int]bool, len(st.Tags))
+	s.index = newSearchIndex()
+	s.history = make([]storage.HistoryEntry, len(st.History))
+	s.nextID, s.nextCategoryID, s.nextHistoryID = st.NextID, st.NextCategoryID, st.NextHistoryID
+	if s.nextID < 1 {
+		s.nextID = 1
+	}
+
+	for _, event := range st.Events {
+		if len(event.Attendees) > 0 {
+			s.attendees[event.ID] = append([]storage.Attendee(nil), event.Attendees...)
+		}
+		event.Attendees, event.Tags = nil, nil
+		s.events[event.ID] = event
+		s.index.add(event)
+	}
+	for _, event := range st.Trash {
+		event.Attendees = append([]storage.Attendee(nil), event.Attendees...)
+		event.Tags = nil
+		s.trash[event.ID] = event
+	}
+	for _, category := range st.Categories {
+		s.categories[category.ID] = category
+	}
+	for eventID, ids := range st.Tags {
+		s.setTags(eventID, ids)
+	}
+	for i, entry := range st.History {
+		entry.Before, entry.After = snapshot(entry.Before), snapshot(entry.After)
+		s.history[i] = entry
+	}
+}
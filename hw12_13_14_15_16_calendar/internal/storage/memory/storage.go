@@ -2,34 +2,61 @@ package memorystorage
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
 )
 
-var ErrNotFound = errors.New("event not found")
+var ErrNotFound = storage.ErrEventNotFound
 
 type Storage struct {
-	mu     sync.RWMutex
-	events map[int]storage.Event
-	nextID int
+	mu        sync.RWMutex
+	events    map[int]storage.Event
+	attendees map[int][]storage.Attendee // by event ID
+	trash     map[int]storage.Event      // deleted events with their attendees
+	nextID    int
+
+	categories     map[int]storage.Category
+	tags           map[int]map[int]bool // category IDs by event ID
+	nextCategoryID int
+
+	index *searchIndex // of the live events
+
+	history       []storage.HistoryEntry // append-only
+	nextHistoryID int
+
+	now func() time.Time // stamps deletions
+
+	undo *undoLog // on the tx of WithinTx
 }
 
 func New() *Storage {
+	return NewWithClock(time.Now)
+}
+
+// NewWithClock creates a storage that takes the deletion time of events from now.
+func NewWithClock(now func() time.Time) *Storage {
 	return &Storage{
-		events: make(map[int]storage.Event),
-		nextID: 1,
+		events:    make(map[int]storage.Event),
+		attendees: make(map[int][]storage.Attendee),
+		trash:     make(map[int]storage.Event),
+		nextID:    1,
+
+		categories: make(map[int]storage.Category),
+		tags:       make(map[int]map[int]bool),
+		index:      newSearchIndex(),
+		now:        now,
 	}
 }
 
-func (s *Storage) CreateEvent(ctx context.Context, event storage.Event) error {
+func (s *Storage) CreateEvent(ctx context.Context, event storage.Event) (int, error) {
 	// Check context before acquiring lock
 	select {
 	case <-ctx.Done():
-		return fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
+		return 0, fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
 	default:
 	}
 
@@ -39,15 +66,18 @@ func (s *Storage) CreateEvent(ctx context.Context, event storage.Event) error {
 	// Check context again after acquiring lock
 	select {
 	case <-ctx.Done():
-		return fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
+		return 0, fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
 	default:
 		// Simulate slow operation for demonstration
 		// time.Sleep(10 * time.Millisecond)
 
 		event.ID = s.nextID
 		s.nextID++
+		event.Attendees, event.Tags = nil, nil
+		s.saveEvent(event.ID)
 		s.events[event.ID] = event
-		return nil
+		s.index.add(event)
+		return event.ID, nil
 	}
 }
 
@@ -69,18 +99,13 @@ func (s *Storage) GetEvent(ctx context.Context, id int) (storage.Event, error) {
 		if !ok {
 			return storage.Event{}, ErrNotFound
 		}
-		return event, nil
+		return s.withDetails(event), nil
 	}
 }
 
-// ListEvents returns events, optionally filtered by time period.
-// PeriodAll returns all events, other periods filter relative to current time.
-func (s *Storage) ListEvents(ctx context.Context, period storage.Period) ([]storage.Event, error) {
-	// period := PeriodAll // default
-	// if len(periods) > 0 {
-	// 	period = periods[0]
-	// }
-
+// ListEvents returns the events matching the filter. PeriodAll returns all events,
+// other periods return events overlapping the day/week/month around filter.Now.
+func (s *Storage) ListEvents(ctx context.Context, filter storage.Filter) ([]storage.Event, error) {
 	select {
 	case <-ctx.Done():
 		return nil, fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
@@ -94,7 +119,6 @@ func (s *Storage) ListEvents(ctx context.Context, period storage.Period) ([]stor
 	case <-ctx.Done():
 		return nil, fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
 	default:
-		now := time.Now() // Base period calculations on current time
 		result := make([]storage.Event, 0, len(s.events))
 
 		for _, event := range s.events {
@@ -102,64 +126,36 @@ func (s *Storage) ListEvents(ctx context.Context, period storage.Period) ([]stor
 			case <-ctx.Done():
 				return nil, fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
 			default:
-				if period == storage.PeriodAll {
-					result = append(result, event)
-					continue
-				}
-
-				if event.Start == nil {
-					fmt.Printf("[WARN] Skipping event ID %d: no Start time set\n", event.ID)
-					continue
-				}
-
-				if matchesPeriod(*event.Start, now, period) {
+				if event = s.withDetails(event); filter.Matches(event) {
 					result = append(result, event)
-				} else {
-					fmt.Printf("[WARN] Skipping event ID %d: Start time %v does not match period %v\n",
-						event.ID, event.Start.Format(time.RFC3339), period)
 				}
 			}
 		}
+		sortEvents(result)
 		return result, nil
 	}
 }
 
-// matchesPeriod helper remains the same as previous implementation.
-func matchesPeriod(eventTime, baseTime time.Time, period storage.Period) bool {
-	switch period {
-	case storage.PeriodAll:
-		return true // Include all events when PeriodAll is specified.
-	case storage.PeriodDay:
-		return sameDay(eventTime, baseTime)
-	case storage.PeriodWeek:
-		return sameWeek(eventTime, baseTime)
-	case storage.PeriodMonth:
-		return sameMonth(eventTime, baseTime)
-	default:
-		return false
-	}
-}
-
-// sameDay/sameWeek/sameMonth helpers.
-func sameDay(a, b time.Time) bool {
-	y1, m1, d1 := a.Date()
-	y2, m2, d2 := b.Date()
-	return y1 == y2 && m1 == m2 && d1 == d2
+// sortEvents orders events like the Postgres backend: by start, then ID, events
+// without a start last.
+func sortEvents(events []storage.Event) {
+	sort.Slice(events, func(i, j int) bool { return eventLess(events[i], events[j]) })
 }
 
-func sameWeek(a, b time.Time) bool {
-	a = a.AddDate(0, 0, -int(a.Weekday()))
-	b = b.AddDate(0, 0, -int(b.Weekday()))
-	return sameDay(a, b)
-}
-
-func sameMonth(a, b time.Time) bool {
-	y1, m1, _ := a.Date()
-	y2, m2, _ := b.Date()
-	return y1 == y2 && m1 == m2
+func eventLess(x, y storage.Event) bool {
+	a, b := x.Start, y.Start
+	switch {
+	case a == nil || b == nil:
+		if (a == nil) != (b == nil) {
+			return b == nil
+		}
+	case !a.Equal(*b):
+		return a.Before(*b)
+	}
+	return x.ID < y.ID
 }
 
-// DeleteEvent removes an event by ID. Returns ErrNotFound if event doesn't exist.
+// DeleteEvent moves an event to the trash. Returns ErrNotFound if event doesn't exist.
 func (s *Storage) DeleteEvent(ctx context.Context, id int) error {
 	// Check context before acquiring lock
 	select {
@@ -184,7 +180,7 @@ func (s *Storage) DeleteEvent(ctx context.Context, id int) error {
 		// Simulate slow operation for demonstration
 		// time.Sleep(10 * time.Millisecond)
 
-		delete(s.events, id)
+		s.moveToTrash(id, s.now().UTC())
 		return nil
 	}
 }
@@ -198,6 +194,10 @@ func (s *Storage) ClearAll(ctx context.Context) error {
 		return fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
 	default:
 		s.events = make(map[int]storage.Event)
+		s.attendees = make(map[int][]storage.Attendee)
+		s.trash = make(map[int]storage.Event)
+		s.tags = make(map[int]map[int]bool)
+		s.index = newSearchIndex()
 		s.nextID = 1
 		return nil
 	}
@@ -211,11 +211,16 @@ func (s *Storage) UpdateEvent(ctx context.Context, event storage.Event) error {
 	case <-ctx.Done():
 		return fmt.Errorf("context canceled after acquiring lock: %w", ctx.Err())
 	default:
-		if _, ok := s.events[event.ID]; !ok {
-			return fmt.Errorf("event with ID %d not found", event.ID)
+		old, ok := s.events[event.ID]
+		if !ok {
+			return ErrNotFound
 		}
 
+		event.Attendees, event.Tags = nil, nil // managed with the attendee and tag methods
+		event.UID, event.ResourceName = old.UID, old.ResourceName
+		s.saveEvent(event.ID)
 		s.events[event.ID] = event
+		s.index.add(event)
 		return nil
 	}
 }
@@ -3,24 +3,34 @@ package memorystorage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/storagetest"
 )
 
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(*testing.T) storage.Store { return New() })
+}
+
 func TestCreateAndGetEvent(t *testing.T) {
 	store := New()
 	event := storage.Event{
 		Title:       "Test Event",
 		Description: "A test event",
-		AllDay:      1,
+		AllDay:      true,
 	}
-	err := store.CreateEvent(context.Background(), event)
+	id, err := store.CreateEvent(context.Background(), event)
 	if err != nil {
 		t.Fatalf("CreateEvent failed: %v", err)
 	}
+	if id != 1 {
+		t.Errorf("expected CreateEvent to return ID 1, got %d", id)
+	}
 
 	// The event should have ID 1
 	got, err := store.GetEvent(context.Background(), 1)
@@ -35,12 +45,12 @@ func TestCreateAndGetEvent(t *testing.T) {
 func TestListEvents(t *testing.T) {
 	store := New()
 	for i := 0; i < 3; i++ {
-		event := storage.Event{Title: "Event", Description: "Desc", AllDay: float64(i)}
-		if err := store.CreateEvent(context.Background(), event); err != nil {
+		event := storage.Event{Title: "Event", Description: "Desc", AllDay: i%2 == 0}
+		if _, err := store.CreateEvent(context.Background(), event); err != nil {
 			t.Fatalf("CreateEvent failed: %v", err)
 		}
 	}
-	events, err := store.ListEvents(context.Background(), storage.PeriodAll)
+	events, err := store.ListEvents(context.Background(), storage.Filter{Period: storage.PeriodAll})
 	if err != nil {
 		t.Fatalf("ListEvents failed: %v", err)
 	}
@@ -62,7 +72,7 @@ func TestStorage_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Immediate cancellation
 
-	err := s.CreateEvent(ctx, storage.Event{})
+	_, err := s.CreateEvent(ctx, storage.Event{})
 	if !errors.Is(err, context.Canceled) {
 		t.Errorf("expected context cancel error, got %v", err)
 	}
@@ -87,7 +97,7 @@ func TestDeleteEvent(t *testing.T) {
 	ctx := context.Background()
 
 	// Create test event
-	err := s.CreateEvent(ctx, storage.Event{Title: "Meeting"})
+	_, err := s.CreateEvent(ctx, storage.Event{Title: "Meeting"})
 	require.NoError(t, err)
 
 	// Delete existing event
@@ -108,3 +118,317 @@ func TestDeleteEvent(t *testing.T) {
 	err = s.DeleteEvent(cancelCtx, 1)
 	require.ErrorIs(t, err, context.Canceled)
 }
+
+func TestAttendees(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	_, err := store.CreateEvent(ctx, storage.Event{Title: "Surgery"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.AddAttendee(ctx, storage.Attendee{
+		EventID: 1, UserID: 9, Role: storage.RoleOptional, Status: storage.RSVPPending,
+	}))
+	require.NoError(t, store.AddAttendee(ctx, storage.Attendee{
+		EventID: 1, UserID: 3, Role: storage.RoleOrganizer, Status: storage.RSVPAccepted,
+	}))
+	require.ErrorIs(t, store.AddAttendee(ctx, storage.Attendee{EventID: 1, UserID: 3}), storage.ErrAttendeeExists)
+	require.ErrorIs(t, store.AddAttendee(ctx, storage.Attendee{EventID: 2, UserID: 3}), ErrNotFound)
+
+	require.NoError(t, store.UpdateAttendee(ctx, storage.Attendee{
+		EventID: 1, UserID: 9, Role: storage.RoleOptional, Status: storage.RSVPDeclined,
+	}))
+
+	event, err := store.GetEvent(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []storage.Attendee{
+		{EventID: 1, UserID: 3, Role: storage.RoleOrganizer, Status: storage.RSVPAccepted},
+		{EventID: 1, UserID: 9, Role: storage.RoleOptional, Status: storage.RSVPDeclined},
+	}, event.Attendees)
+
+	events, err := store.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll})
+	require.NoError(t, err)
+	require.Len(t, events[0].Attendees, 2)
+
+	require.NoError(t, store.RemoveAttendee(ctx, 1, 3))
+	require.ErrorIs(t, store.RemoveAttendee(ctx, 1, 3), storage.ErrAttendeeNotFound)
+	attendees, err := store.ListAttendees(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, attendees, 1)
+
+	require.NoError(t, store.DeleteEvent(ctx, 1))
+	_, err = store.ListAttendees(ctx, 1)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBatch(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	ids, err := s.CreateEvents(ctx, []storage.Event{{Title: "First"}, {Title: "Second"}, {Title: "Third"}})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, ids)
+
+	// A missing or repeated ID aborts the whole batch.
+	err = s.DeleteEvents(ctx, []int{1, 99, 2, 2})
+	var batchErr *storage.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Items, 2)
+	require.ErrorIs(t, batchErr.Items[1], ErrNotFound)
+	require.Error(t, batchErr.Items[3])
+	events, err := s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	require.NoError(t, s.DeleteEvents(ctx, []int{1, 3}))
+	events, err = s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "Second", events[0].Title)
+}
+
+func TestTrash(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	id, err := s.CreateEvent(ctx, storage.Event{Title: "Trash"})
+	require.NoError(t, err)
+	require.NoError(t, s.AddAttendee(ctx, storage.Attendee{EventID: id, UserID: 4}))
+	require.NoError(t, s.DeleteEvent(ctx, id))
+
+	_, err = s.GetEvent(ctx, id)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, s.DeleteEvent(ctx, id), ErrNotFound)
+	require.ErrorIs(t, s.RemoveAttendee(ctx, id, 4), storage.ErrAttendeeNotFound)
+	require.ErrorIs(t, s.RecreateEvent(ctx, storage.Event{ID: id}), storage.ErrEventExists)
+
+	trash, err := s.ListDeletedEvents(ctx)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.NotNil(t, trash[0].DeletedAt)
+	require.Len(t, trash[0].Attendees, 1)
+
+	require.NoError(t, s.UndeleteEvent(ctx, id))
+	require.ErrorIs(t, s.UndeleteEvent(ctx, id), ErrNotFound)
+	got, err := s.GetEvent(ctx, id)
+	require.NoError(t, err)
+	require.Nil(t, got.DeletedAt)
+	require.Len(t, got.Attendees, 1)
+
+	require.NoError(t, s.DeleteEvents(ctx, []int{id}))
+	n, err := s.PurgeDeletedEvents(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = s.PurgeDeletedEvents(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	trash, err = s.ListDeletedEvents(ctx)
+	require.NoError(t, err)
+	require.Empty(t, trash)
+}
+
+func TestCategories(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	urgent, err := s.CreateCategory(ctx, storage.Category{Name: "urgent", Color: "#ff0000"})
+	require.NoError(t, err)
+	_, err = s.CreateCategory(ctx, storage.Category{Name: "follow-up"})
+	require.NoError(t, err)
+	_, err = s.CreateCategory(ctx, storage.Category{Name: "urgent"})
+	require.ErrorIs(t, err, storage.ErrCategoryExists)
+
+	categories, err := s.ListCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	require.Equal(t, "follow-up", categories[0].Name)
+
+	service := "x-ray"
+	id, err := s.CreateEvent(ctx, storage.Event{Title: "Scan", Service: &service})
+	require.NoError(t, err)
+	_, err = s.CreateEvent(ctx, storage.Event{Title: "Other"})
+	require.NoError(t, err)
+	require.ErrorIs(t, s.SetEventTags(ctx, id, []string{"urgent", "missing"}), storage.ErrCategoryNotFound)
+	require.NoError(t, s.SetEventTags(ctx, id, []string{"urgent", "follow-up", "urgent"}))
+	require.ErrorIs(t, s.SetEventTags(ctx, 99, nil), ErrNotFound)
+
+	got, err := s.GetEvent(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"follow-up", "urgent"}, got.Tags)
+
+	events, err := s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll, Tags: []string{"urgent"}})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	events, err = s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll, Service: "x-ray"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	require.NoError(t, s.UpdateCategory(ctx, storage.Category{ID: urgent, Name: "emergency"}))
+	require.ErrorIs(t, s.UpdateCategory(ctx, storage.Category{ID: urgent, Name: "follow-up"}), storage.ErrCategoryExists)
+	require.ErrorIs(t, s.UpdateCategory(ctx, storage.Category{ID: 99, Name: "x"}), storage.ErrCategoryNotFound)
+	got, err = s.GetEvent(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"emergency", "follow-up"}, got.Tags)
+
+	require.NoError(t, s.DeleteCategory(ctx, urgent))
+	require.ErrorIs(t, s.DeleteCategory(ctx, urgent), storage.ErrCategoryNotFound)
+	got, err = s.GetEvent(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"follow-up"}, got.Tags)
+}
+
+func TestSearchEvents(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	day := func(d int) *time.Time {
+		t := time.Date(2024, 3, d, 9, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	for _, event := range []storage.Event{
+		{Title: "Flu shot", Description: "Seasonal flu vaccination for adults.", Start: day(4)},
+		{Title: "Check-up", Description: "Bring the results of the last flu test.", Start: day(5)},
+		{Title: "Dental cleaning", Description: "Shot of anaesthetic if needed.", Start: day(6)},
+	} {
+		_, err := s.CreateEvent(ctx, event)
+		require.NoError(t, err)
+	}
+	all := storage.Filter{Period: storage.PeriodAll}
+	search := func(q storage.SearchQuery) []int {
+		if q.Filter.Period == "" {
+			q.Filter = all
+		}
+		results, err := s.SearchEvents(ctx, q)
+		require.NoError(t, err)
+		ids := make([]int, len(results))
+		for i, r := range results {
+			ids[i] = r.Event.ID
+		}
+		return ids
+	}
+
+	// Title matches rank above description matches.
+	require.Equal(t, []int{1, 2}, search(storage.SearchQuery{Phrases: [][]string{{"flu"}}}))
+	require.Equal(t, []int{1, 3}, search(storage.SearchQuery{Phrases: [][]string{{"shot"}}}))
+	require.Equal(t, []int{1}, search(storage.SearchQuery{Phrases: [][]string{{"flu", "shot"}}}))
+	require.Empty(t, search(storage.SearchQuery{Phrases: [][]string{{"shot", "flu"}}}))
+	require.Equal(t, []int{2}, search(storage.SearchQuery{Phrases: [][]string{{"flu"}, {"results"}}}))
+	// Phrases don't span the title and the description.
+	require.Empty(t, search(storage.SearchQuery{Phrases: [][]string{{"cleaning", "shot"}}}))
+
+	from := *day(5)
+	require.Equal(t, []int{2}, search(storage.SearchQuery{
+		Phrases: [][]string{{"flu"}},
+		Filter:  storage.Filter{Period: storage.PeriodAll, From: from},
+	}))
+	require.Equal(t, []int{1}, search(storage.SearchQuery{Phrases: [][]string{{"flu"}}, Limit: 1}))
+
+	results, err := s.SearchEvents(ctx, storage.SearchQuery{Phrases: [][]string{{"flu"}}, Filter: all})
+	require.NoError(t, err)
+	require.Equal(t, "<b>Flu</b> shot", results[0].Title)
+	require.Equal(t, "Seasonal <b>flu</b> vaccination for adults.", results[0].Description)
+
+	// The index follows updates and deletions.
+	require.NoError(t, s.UpdateEvent(ctx, storage.Event{ID: 1, Title: "Vaccination", Start: day(4)}))
+	require.NoError(t, s.DeleteEvent(ctx, 2))
+	require.Empty(t, search(storage.SearchQuery{Phrases: [][]string{{"flu"}}}))
+	require.NoError(t, s.UndeleteEvent(ctx, 2))
+	require.Equal(t, []int{2}, search(storage.SearchQuery{Phrases: [][]string{{"flu"}}}))
+}
+
+func TestHighlightFragment(t *testing.T) {
+	words := make([]string, 50)
+	for i := range words {
+		words[i] = fmt.Sprintf("w%d", i)
+	}
+	words[40] = "match"
+	got := highlight(strings.Join(words, " "), map[string]bool{"match": true}, 10)
+	require.Equal(t, "…w38 w39 <b>match</b> w41 w42 w43 w44 w45 w46 w47…", got)
+}
+
+func TestWithinTx_Rollback(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	_, err := s.CreateEvents(ctx, []storage.Event{{Title: "Flu shot"}, {Title: "Check-up"}, {Title: "Dental"}})
+	require.NoError(t, err)
+	require.NoError(t, s.AddAttendee(ctx, storage.Attendee{EventID: 1, UserID: 7, Status: storage.RSVPPending}))
+	_, err = s.CreateCategory(ctx, storage.Category{Name: "vaccination"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetEventTags(ctx, 1, []string{"vaccination"}))
+	require.NoError(t, s.DeleteEvent(ctx, 3))
+	require.NoError(t, s.AddHistory(ctx, storage.HistoryEntry{EventID: 3, Action: storage.ActionDeleted}))
+	before := s.State()
+	search := func() []storage.SearchResult {
+		results, err := s.SearchEvents(ctx, storage.SearchQuery{
+			Phrases: [][]string{{"flu"}}, Filter: storage.Filter{Period: storage.PeriodAll},
+		})
+		require.NoError(t, err)
+		return results
+	}
+	found := search()
+
+	errAbort := errors.New("abort")
+	err = s.WithinTx(ctx, func(tx storage.Store) error {
+		require.NoError(t, tx.UpdateEvent(ctx, storage.Event{ID: 1, Title: "Measles shot"}))
+		require.NoError(t, tx.UpdateAttendee(ctx, storage.Attendee{EventID: 1, UserID: 7, Status: storage.RSVPAccepted}))
+		require.NoError(t, tx.AddAttendee(ctx, storage.Attendee{EventID: 2, UserID: 8}))
+		require.NoError(t, tx.DeleteEvent(ctx, 1))
+		require.NoError(t, tx.UndeleteEvent(ctx, 3))
+		require.NoError(t, tx.RemoveAttendee(ctx, 2, 8))
+		require.NoError(t, tx.AddHistory(ctx, storage.HistoryEntry{EventID: 3, Action: storage.ActionRestored}))
+
+		// A committed nested transaction is rolled back with the outer one.
+		require.NoError(t, tx.(storage.Transactor).WithinTx(ctx, func(tx storage.Store) error {
+			require.NoError(t, tx.DeleteCategory(ctx, 1))
+			_, err := tx.CreateEvent(ctx, storage.Event{Title: "Flu follow-up"})
+			return err
+		}))
+		_, err := tx.PurgeDeletedEvents(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, tx.(*Storage).ClearAll(ctx))
+		_, err = tx.CreateEvent(ctx, storage.Event{Title: "After clearing"})
+		require.NoError(t, err)
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+	require.Equal(t, before, s.State())
+	require.Equal(t, found, search())
+
+	// A failed nested transaction leaves the writes of the outer one in place.
+	require.NoError(t, s.WithinTx(ctx, func(tx storage.Store) error {
+		require.NoError(t, tx.UpdateEvent(ctx, storage.Event{ID: 2, Title: "Annual check-up"}))
+		err := tx.(storage.Transactor).WithinTx(ctx, func(tx storage.Store) error {
+			require.NoError(t, tx.UpdateEvent(ctx, storage.Event{ID: 2, Title: "Skipped"}))
+			require.NoError(t, tx.SetEventTags(ctx, 2, []string{"vaccination"}))
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+		return nil
+	}))
+	event, err := s.GetEvent(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, "Annual check-up", event.Title)
+	require.Empty(t, event.Tags)
+}
+
+// BenchmarkWithinTx shows that the cost of a transaction does not grow with the storage.
+func BenchmarkWithinTx(b *testing.B) {
+	ctx := context.Background()
+	for _, size := range []int{1_000, 10_000, 100_000} {
+		b.Run(fmt.Sprintf("events=%d", size), func(b *testing.B) {
+			s := New()
+			_, err := s.CreateEvents(ctx, make([]storage.Event, size))
+			require.NoError(b, err)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				err := s.WithinTx(ctx, func(tx storage.Store) error {
+					return tx.UpdateEvent(ctx, storage.Event{ID: i%size + 1, Title: "Checkup"})
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
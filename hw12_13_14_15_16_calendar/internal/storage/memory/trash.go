@@ -0,0 +1,95 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// moveToTrash soft-deletes an existing event together with its attendees; the caller
+// must hold mu. The tags stay in place, so that deleting a category also untags the
+// events in the trash.
+func (s *Storage) moveToTrash(id int, at time.Time) {
+	s.saveEvent(id)
+	event := s.withDetails(s.events[id])
+	event.Tags = nil
+	event.DeletedAt = &at
+	s.trash[id] = event
+	delete(s.events, id)
+	delete(s.attendees, id)
+	s.index.remove(id)
+}
+
+// ListDeletedEvents returns the events in the trash, most recently deleted first.
+func (s *Storage) ListDeletedEvents(ctx context.Context) ([]storage.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := make([]storage.Event, 0, len(s.trash))
+	for _, event := range s.trash {
+		event.Attendees = append([]storage.Attendee(nil), event.Attendees...)
+		event.Tags = s.tagNames(event.ID)
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i].DeletedAt, events[j].DeletedAt
+		if !a.Equal(*b) {
+			return a.After(*b)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// UndeleteEvent takes an event out of the trash. Returns ErrNotFound if it is not there.
+func (s *Storage) UndeleteEvent(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.trash[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.saveEvent(id)
+	if len(event.Attendees) > 0 {
+		s.attendees[id] = event.Attendees
+	}
+	event.Attendees, event.DeletedAt = nil, nil
+	s.events[id] = event
+	s.index.add(event)
+	delete(s.trash, id)
+	return nil
+}
+
+// PurgeDeletedEvents permanently removes the events deleted before the given time and
+// returns how many were removed.
+func (s *Storage) PurgeDeletedEvents(ctx context.Context, before time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for id, event := range s.trash {
+		if event.DeletedAt.Before(before) {
+			s.saveEvent(id)
+			delete(s.trash, id)
+			delete(s.tags, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,156 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// WithinTx runs fn under the write lock on a storage that shares the content of s and
+// records how to undo its writes, which it replays if fn fails. A transaction thus costs
+// as much as the writes of fn, however large the storage. The lock is held until fn
+// returns, so transactions and other writes wait for each other and never conflict;
+// reads wait too.
+func (s *Storage) WithinTx(ctx context.Context, fn func(tx storage.Store) error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled before acquiring lock: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := s.begin()
+	if err := fn(tx); err != nil {
+		tx.rollback()
+		return err
+	}
+	s.commit(tx)
+	return nil
+}
+
+// undoLog restores what a transaction changed in the maps it shares with its storage.
+type undoLog struct {
+	steps      []func()
+	events     map[int]bool // IDs of the events whose state is saved
+	categories map[int]bool // IDs of the categories whose state is saved
+}
+
+// begin returns the tx for WithinTx; the caller must hold mu. The tx writes to the maps of
+// s, but keeps the counters and the history length to itself until the commit.
+func (s *Storage) begin() *Storage {
+	return &Storage{
+		events:    s.events,
+		attendees: s.attendees,
+		trash:     s.trash,
+		nextID:    s.nextID,
+
+		categories:     s.categories,
+		tags:           s.tags,
+		nextCategoryID: s.nextCategoryID,
+
+		index: s.index,
+
+		history:       s.history,
+		nextHistoryID: s.nextHistoryID,
+
+		now:  s.now,
+		undo: &undoLog{events: make(map[int]bool), categories: make(map[int]bool)},
+	}
+}
+
+// rollback undoes the writes of the tx s, which must not be used afterwards.
+func (s *Storage) rollback() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.undo.steps) - 1; i >= 0; i-- {
+		s.undo.steps[i]()
+	}
+}
+
+// commit takes over the content of tx, which must not be used afterwards; the caller must
+// hold mu. Within an outer transaction, the writes of tx stay undoable with it.
+func (s *Storage) commit(tx *Storage) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	s.events, s.attendees, s.trash, s.nextID = tx.events, tx.attendees, tx.trash, tx.nextID
+	s.categories, s.tags, s.nextCategoryID = tx.categories, tx.tags, tx.nextCategoryID
+	s.index = tx.index
+	s.history, s.nextHistoryID = tx.history, tx.nextHistoryID
+
+	if s.undo != nil {
+		s.undo.steps = append(s.undo.steps, tx.undo.steps...)
+		for id := range tx.undo.events {
+			s.undo.events[id] = true
+		}
+		for id := range tx.undo.categories {
+			s.undo.categories[id] = true
+		}
+	}
+}
+
+// saveEvent records how to restore the event, its attendees, trash entry, tags and search
+// entry as they were before the transaction changed them; the caller must hold mu and
+// call it before changing any of them. Outside a transaction it does nothing.
+func (s *Storage) saveEvent(id int) {
+	if s.undo == nil || s.undo.events[id] {
+		return
+	}
+	s.undo.events[id] = true
+
+	events, attendees, trash, tags, index := s.events, s.attendees, s.trash, s.tags, s.index
+	event, live := events[id]
+	list, listed := attendees[id]
+	list = append([]storage.Attendee(nil), list...)
+	deleted, trashed := trash[id]
+	deleted.Attendees = append([]storage.Attendee(nil), deleted.Attendees...)
+	set, tagged := tags[id]
+	set = copySet(set)
+
+	s.undo.steps = append(s.undo.steps, func() {
+		restore(events, id, event, live)
+		restore(attendees, id, list, listed)
+		restore(trash, id, deleted, trashed)
+		restore(tags, id, set, tagged)
+		if live {
+			index.add(event)
+		} else {
+			index.remove(id)
+		}
+	})
+}
+
+// saveCategory records how to restore the category as it was before the transaction
+// changed it, like saveEvent does for events.
+func (s *Storage) saveCategory(id int) {
+	if s.undo == nil || s.undo.categories[id] {
+		return
+	}
+	s.undo.categories[id] = true
+
+	categories := s.categories
+	category, ok := categories[id]
+	s.undo.steps = append(s.undo.steps, func() { restore(categories, id, category, ok) })
+}
+
+// restore puts value back under key in m, or deletes key if it was not there.
+func restore[K comparable, V any](m map[K]V, key K, value V, ok bool) {
+	if ok {
+		m[key] = value
+	} else {
+		delete(m, key)
+	}
+}
+
+func copySet(set map[int]bool) map[int]bool {
+	if set == nil {
+		return nil
+	}
+	c := make(map[int]bool, len(set))
+	for id := range set {
+		c[id] = true
+	}
+	return c
+}
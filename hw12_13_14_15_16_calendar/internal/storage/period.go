@@ -1,5 +1,7 @@
 package storage
 
+import "time"
+
 type Period string
 
 const (
@@ -8,3 +10,111 @@ const (
 	PeriodWeek  Period = "week"
 	PeriodMonth Period = "month"
 )
+
+// maxTime is the open upper end of a range filter.
+var maxTime = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// Filter selects the events returned by ListEvents.
+type Filter struct {
+	Period   Period
+	From, To time.Time      // explicit range [From, To), used instead of Period when either is set
+	Location *time.Location // zone of the day/week/month boundaries; nil means UTC
+	Now      time.Time      // reference time of the period; zero means time.Now()
+	UserIDs  []int          // only events owned or attended by any of the users
+	Clinic   string         // only events at the clinic
+	Service  string         // only events of the service
+	Tags     []string       // only events in any of the categories
+
+	ResourceName string // only events with the CalDAV resource name
+}
+
+// Bounds returns the half-open interval [from, to) covered by the range or the period in
+// the filter's zone. Weeks are ISO weeks starting on Monday. bounded is false for PeriodAll;
+// an unknown period yields an empty interval.
+func (f Filter) Bounds() (from, to time.Time, bounded bool) {
+	if !f.From.IsZero() || !f.To.IsZero() {
+		to = f.To
+		if to.IsZero() {
+			to = maxTime
+		}
+		return f.From, to, true
+	}
+
+	loc := f.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := f.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	y, m, d := now.In(loc).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+
+	switch f.Period {
+	case PeriodAll:
+		return time.Time{}, time.Time{}, false
+	case PeriodDay:
+		return day, day.AddDate(0, 0, 1), true
+	case PeriodWeek:
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return monday, monday.AddDate(0, 0, 7), true
+	case PeriodMonth:
+		first := time.Date(y, m, 1, 0, 0, 0, 0, loc)
+		return first, first.AddDate(0, 1, 0), true
+	default:
+		return day, day, true
+	}
+}
+
+// Matches reports whether the event overlaps the filter's period. Events without an end
+// (or ending before they start) are treated as a single instant; events without a start
+// only match PeriodAll. The user and tag conditions need event.Attendees and event.Tags
+// to be loaded.
+// The Postgres backend applies the same rules in SQL.
+func (f Filter) Matches(event Event) bool {
+	if len(f.UserIDs) > 0 && !involvesAny(event, f.UserIDs) {
+		return false
+	}
+	if f.Clinic != "" && (event.Clinic == nil || *event.Clinic != f.Clinic) {
+		return false
+	}
+	if f.Service != "" && (event.Service == nil || *event.Service != f.Service) {
+		return false
+	}
+	if len(f.Tags) > 0 && !taggedAny(event, f.Tags) {
+		return false
+	}
+	if f.ResourceName != "" && event.ResourceName != f.ResourceName {
+		return false
+	}
+
+	from, to, bounded := f.Bounds()
+	if !bounded {
+		return true
+	}
+	if event.Start == nil || !event.Start.Before(to) {
+		return false
+	}
+	return !event.Start.Before(from) || (event.End != nil && event.End.After(from))
+}
+
+func involvesAny(event Event, userIDs []int) bool {
+	for _, id := range userIDs {
+		if event.Involves(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func taggedAny(event Event, tags []string) bool {
+	for _, want := range tags {
+		for _, tag := range event.Tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterBounds(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	// Sunday 2024-03-31 23:30 UTC is already Monday 01:30 in Berlin (after the DST switch).
+	now := time.Date(2024, 3, 31, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		from, to time.Time
+	}{
+		{
+			name:   "day in UTC",
+			filter: Filter{Period: PeriodDay, Now: now},
+			from:   time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			to:     time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "day in caller zone",
+			filter: Filter{Period: PeriodDay, Location: berlin, Now: now},
+			from:   time.Date(2024, 4, 1, 0, 0, 0, 0, berlin),
+			to:     time.Date(2024, 4, 2, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:   "ISO week starts on Monday",
+			filter: Filter{Period: PeriodWeek, Now: now},
+			from:   time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC),
+			to:     time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "week in caller zone",
+			filter: Filter{Period: PeriodWeek, Location: berlin, Now: now},
+			from:   time.Date(2024, 4, 1, 0, 0, 0, 0, berlin),
+			to:     time.Date(2024, 4, 8, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:   "month in caller zone",
+			filter: Filter{Period: PeriodMonth, Location: berlin, Now: now},
+			from:   time.Date(2024, 4, 1, 0, 0, 0, 0, berlin),
+			to:     time.Date(2024, 5, 1, 0, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, bounded := tc.filter.Bounds()
+			if !bounded || !from.Equal(tc.from) || !to.Equal(tc.to) {
+				t.Errorf("got [%v, %v) bounded=%v, want [%v, %v)", from, to, bounded, tc.from, tc.to)
+			}
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	at := func(day, hour int) *time.Time {
+		v := time.Date(2024, 5, day, hour, 0, 0, 0, time.UTC)
+		return &v
+	}
+	day := Filter{Period: PeriodDay, Now: now}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  bool
+	}{
+		{"starts today", Event{Start: at(15, 9)}, true},
+		{"starts tomorrow", Event{Start: at(16, 0)}, false},
+		{"ended yesterday", Event{Start: at(14, 9), End: at(14, 10)}, false},
+		{"multi-day all-day event covering today", Event{Start: at(14, 0), End: at(17, 0), AllDay: true}, true},
+		{"no start", Event{}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := day.Matches(tc.event); got != tc.want {
+				t.Errorf("Matches() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if !(Filter{Period: PeriodAll}).Matches(Event{}) {
+		t.Error("PeriodAll must match events without a start")
+	}
+}
+
+func TestFilterRangeAndUser(t *testing.T) {
+	start := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	owner, guest, stranger := 1, 2, 3
+	event := Event{Start: &start, UserID: &owner, Attendees: []Attendee{{UserID: guest}}}
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	if !(Filter{From: from}).Matches(event) {
+		t.Error("open-ended range must match a later event")
+	}
+	if (Filter{To: from}).Matches(event) {
+		t.Error("range ending before the event must not match")
+	}
+	if !(Filter{Period: PeriodDay, Now: from, From: from}).Matches(event) {
+		t.Error("an explicit range must take precedence over the period")
+	}
+
+	for _, tc := range []struct {
+		user int
+		want bool
+	}{{owner, true}, {guest, true}, {stranger, false}} {
+		if got := (Filter{Period: PeriodAll, UserIDs: []int{tc.user}}).Matches(event); got != tc.want {
+			t.Errorf("user %d: Matches() = %v, want %v", tc.user, got, tc.want)
+		}
+	}
+}
+
+func TestFilterServiceAndTags(t *testing.T) {
+	service := "x-ray"
+	event := Event{Service: &service, Tags: []string{"follow-up", "urgent"}}
+
+	for _, tc := range []struct {
+		filter Filter
+		want   bool
+	}{
+		{Filter{Service: "x-ray"}, true},
+		{Filter{Service: "dental"}, false},
+		{Filter{Tags: []string{"vaccination", "urgent"}}, true},
+		{Filter{Tags: []string{"vaccination"}}, false},
+		{Filter{Service: "x-ray", Tags: []string{"follow-up"}}, true},
+	} {
+		tc.filter.Period = PeriodAll
+		if got := tc.filter.Matches(event); got != tc.want {
+			t.Errorf("%+v: Matches() = %v, want %v", tc.filter, got, tc.want)
+		}
+	}
+	if (Filter{Period: PeriodAll, Service: "x-ray"}).Matches(Event{}) {
+		t.Error("an event without a service must not match a service filter")
+	}
+}
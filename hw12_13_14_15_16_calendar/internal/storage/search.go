@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidSearch = errors.New("invalid search query")
+
+// Highlight markers put around the matched words of a SearchResult.
+const (
+	HighlightStart = "<b>"
+	HighlightStop  = "</b>"
+)
+
+// SearchQuery selects the events returned by SearchEvents.
+type SearchQuery struct {
+	Phrases [][]string // every phrase must occur in the title or the description; see ParseSearch
+	Filter  Filter     // further conditions, e.g. a date range; usually with PeriodAll
+	Limit   int        // maximum number of results
+}
+
+// SearchResult is an event found by SearchEvents. Rank orders the results, highest first;
+// its scale differs between the backends.
+type SearchResult struct {
+	Event       Event
+	Rank        float64
+	Title       string // the title with the matched words highlighted
+	Description string // a fragment of the description with the matched words highlighted
+}
+
+// ParseSearch splits a query into phrases. Text in double quotes is one phrase, whose words
+// must occur in order; every other word is a phrase of its own. Words are compared
+// case-insensitively and punctuation is ignored, as in SearchWords.
+func ParseSearch(text string) ([][]string, error) {
+	var phrases [][]string
+	for i, part := range strings.Split(text, `"`) {
+		words := SearchWords(part)
+		if i%2 == 1 {
+			if len(words) > 0 {
+				phrases = append(phrases, words)
+			}
+			continue
+		}
+		for _, word := range words {
+			phrases = append(phrases, []string{word})
+		}
+	}
+	if len(phrases) == 0 {
+		return nil, ErrInvalidSearch
+	}
+	return phrases, nil
+}
+
+// SearchWords splits text into lowercase words of letters and digits.
+func SearchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
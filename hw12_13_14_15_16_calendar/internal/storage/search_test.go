@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSearch(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want [][]string
+	}{
+		{"Flu shot", [][]string{{"flu"}, {"shot"}}},
+		{`"flu shot" booster`, [][]string{{"flu", "shot"}, {"booster"}}},
+		{`check-up "Dr. Weber`, [][]string{{"check"}, {"up"}, {"dr", "weber"}}},
+		{`"" x-ray`, [][]string{{"x"}, {"ray"}}},
+	} {
+		got, err := ParseSearch(tc.text)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseSearch(%q) = %v, %v; want %v", tc.text, got, err, tc.want)
+		}
+	}
+	for _, text := range []string{"", "  ", `"--"`} {
+		if _, err := ParseSearch(text); !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("ParseSearch(%q): expected ErrInvalidSearch, got %v", text, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// liveEvent restricts attendee statements to events that are not deleted.
+const liveEvent = `event_id IN (SELECT id FROM events WHERE deleted_at IS NULL)`
+
+// AddAttendee invites a user to an existing event.
+func (s *Storage) AddAttendee(ctx context.Context, attendee storage.Attendee) error {
+	result, err := s.conn.ExecContext(ctx, insertAttendeeSQL,
+		attendee.EventID, attendee.UserID, attendee.Role, attendee.Status)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return storage.ErrAttendeeExists
+		case pgForeignKeyViolation:
+			return ErrNotFound
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add attendee: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// ListAttendees returns the attendees of an event ordered by user ID.
+func (s *Storage) ListAttendees(ctx context.Context, eventID int) ([]storage.Attendee, error) {
+	var exists bool
+	if err := s.conn.QueryRowContext(ctx, eventExistsSQL, eventID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to list attendees: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	events := []storage.Event{{ID: eventID}}
+	if err := s.loadAttendees(ctx, s.conn, events); err != nil {
+		return nil, err
+	}
+	if len(events[0].Attendees) > 0 {
+		return events[0].Attendees, nil
+	}
+	return []storage.Attendee{}, nil
+}
+
+// loadAttendees fills in the attendees of all events with a single query.
+func (s *Storage) loadAttendees(ctx context.Context, db queryer, events []storage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID, ids := eventIndex(events)
+	rows, err := db.QueryContext(ctx, selectAttendeesSQL, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load attendees: %w", err)
+	}
+	defer rows.Close()
+	return scanAttendees(rows, byID)
+}
+
+// UpdateAttendee changes the role and RSVP status of an attendee.
+func (s *Storage) UpdateAttendee(ctx context.Context, attendee storage.Attendee) error {
+	result, err := s.conn.ExecContext(ctx, updateAttendeeSQL,
+		attendee.Role, attendee.Status, attendee.EventID, attendee.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update attendee: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrAttendeeNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// RemoveAttendee withdraws a user's invitation to an event.
+func (s *Storage) RemoveAttendee(ctx context.Context, eventID, userID int) error {
+	result, err := s.conn.ExecContext(ctx, removeAttendeeSQL, eventID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove attendee: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrAttendeeNotFound
+	}
+	return s.wrote(ctx, nil)
+}
@@ -0,0 +1,200 @@
+package postgresstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// copyThreshold is the batch size from which CreateEvents streams rows with COPY instead
+// of inserting them one by one.
+const copyThreshold = 50
+
+var copyColumns = []string{
+	"id", "title", "description", "start", "end", "allday", "time_zone", "clinic", "userid", "service",
+	"uid", "resource_name",
+}
+
+// copyLine finds the row of the COPY input that a Postgres error is about in the context of
+// the error, e.g. "COPY events, line 3, column title: ...".
+var copyLine = regexp.MustCompile(`^COPY \w+, line (\d+)`)
+
+// copyFailure reports the row of events that made a COPY fail as an item of a
+// *storage.BatchError. It returns nil if the error names no row.
+func copyFailure(err error, events []storage.Event) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	m := copyLine.FindStringSubmatch(pgErr.Where)
+	if m == nil {
+		return nil
+	}
+	line, _ := strconv.Atoi(m[1])
+	if line < 1 || line > len(events) {
+		return nil
+	}
+	return &storage.BatchError{Items: map[int]error{line - 1: err}}
+}
+
+// CreateEvents inserts all events in a single transaction and returns their IDs in order.
+// Large batches reserve the IDs from the sequence first and are loaded with COPY, on the
+// connection of the transaction when called within WithinTx. A failed COPY reports the
+// failing event like the inserts do, from the error or, if it names no row, by inserting
+// the events one by one.
+func (s *Storage) CreateEvents(ctx context.Context, events []storage.Event) ([]int, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	if len(events) >= copyThreshold {
+		ids, err := s.copyEvents(ctx, events)
+		if err == nil {
+			return ids, s.wrote(ctx, nil)
+		}
+		if batchErr := copyFailure(err, events); batchErr != nil {
+			return nil, batchErr
+		}
+	}
+	return s.insertEvents(ctx, events)
+}
+
+// insertEvents inserts the events one by one in a single transaction.
+func (s *Storage) insertEvents(ctx context.Context, events []storage.Event) ([]int, error) {
+	ids := make([]int, len(events))
+	err := s.inTx(ctx, nil, func(tx *sql.Tx) error {
+		for i, event := range events {
+			err := tx.QueryRowContext(ctx, insertEventSQL,
+				event.Title, event.Description, event.Start, event.End, event.AllDay, timeZone(event),
+				event.Clinic, event.UserID, event.Service, event.UID, event.ResourceName).Scan(&ids[i])
+			if err != nil {
+				return &storage.BatchError{Items: map[int]error{i: err}}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, s.wrote(ctx, nil)
+}
+
+// copyEvents loads events with COPY in a transaction of its own or, within WithinTx, in a
+// savepoint of the outer transaction.
+func (s *Storage) copyEvents(ctx context.Context, events []storage.Event) (ids []int, err error) {
+	if s.tx != nil {
+		err = s.inTx(ctx, nil, func(*sql.Tx) error {
+			ids, err = copyRows(ctx, s.txConn, events)
+			return err
+		})
+	} else {
+		err = runTx(ctx, s.db, nil, func(conn *sql.Conn, _ *sql.Tx) error {
+			ids, err = copyRows(ctx, conn, events)
+			return err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// copyRows reserves IDs for events and copies them into the events table through the pgx
+// connection under conn, in the transaction open on it.
+func copyRows(ctx context.Context, conn *sql.Conn, events []storage.Event) ([]int, error) {
+	ids := make([]int, 0, len(events))
+	err := conn.Raw(func(driverConn interface{}) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		rows, err := pgxConn.Query(ctx, reserveIDsSQL, len(events))
+		if err != nil {
+			return fmt.Errorf("failed to reserve ids: %w", err)
+		}
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to reserve ids: %w", err)
+		}
+
+		data := make([][]interface{}, len(events))
+		for i, event := range events {
+			data[i] = []interface{}{
+				ids[i], event.Title, event.Description, event.Start, event.End, event.AllDay, timeZone(event),
+				event.Clinic, event.UserID, event.Service, event.UID, event.ResourceName,
+			}
+		}
+		if _, err := pgxConn.CopyFrom(ctx, pgx.Identifier{"events"}, copyColumns, pgx.CopyFromRows(data)); err != nil {
+			return fmt.Errorf("failed to copy events: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// DeleteEvents moves all events to the trash in a single transaction or, if any of them
+// is missing, none. Missing and repeated IDs are reported in a *storage.BatchError.
+func (s *Storage) DeleteEvents(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := s.inTx(ctx, nil, func(tx *sql.Tx) error {
+		deleted, err := deletedIDs(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+		return batchDeleteError(ids, deleted)
+	})
+	return s.wrote(ctx, err)
+}
+
+func deletedIDs(ctx context.Context, tx *sql.Tx, ids []int) (map[int]bool, error) {
+	rows, err := tx.QueryContext(ctx, deleteEventsSQL, intArray(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete events: %w", err)
+	}
+	defer rows.Close()
+	deleted := make(map[int]bool, len(ids))
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		deleted[id] = true
+	}
+	return deleted, rows.Err()
+}
+
+// batchDeleteError reports the IDs that were repeated or not deleted, or returns nil.
+func batchDeleteError(ids []int, deleted map[int]bool) error {
+	failed := make(map[int]error)
+	seen := make(map[int]bool, len(ids))
+	for i, id := range ids {
+		switch {
+		case seen[id]:
+			failed[i] = fmt.Errorf("%w: event %d", storage.ErrBatchDuplicate, id)
+		case !deleted[id]:
+			failed[i] = ErrNotFound
+		}
+		seen[id] = true
+	}
+	if len(failed) > 0 {
+		return &storage.BatchError{Items: failed}
+	}
+	return nil
+}
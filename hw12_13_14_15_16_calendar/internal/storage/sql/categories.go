@@ -0,0 +1,123 @@
+package postgresstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CreateCategory stores a new category and returns its ID.
+func (s *Storage) CreateCategory(ctx context.Context, category storage.Category) (int, error) {
+	var id int
+	err := s.conn.QueryRowContext(ctx, insertCategorySQL, category.Name, category.Color).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, storage.ErrCategoryExists
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to create category: %w", err)
+	}
+	return id, nil
+}
+
+// ListCategories returns all categories ordered by name.
+func (s *Storage) ListCategories(ctx context.Context) ([]storage.Category, error) {
+	rows, err := s.conn.QueryContext(ctx, selectCategoriesSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+	return scanCategories(rows)
+}
+
+// UpdateCategory renames or recolors a category. Renaming it renames the tag on all its
+// events.
+func (s *Storage) UpdateCategory(ctx context.Context, category storage.Category) error {
+	result, err := s.conn.ExecContext(ctx, updateCategorySQL, category.Name, category.Color, category.ID)
+	if isUniqueViolation(err) {
+		return storage.ErrCategoryExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrCategoryNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// DeleteCategory removes a category and, by cascade, its tag from all events.
+func (s *Storage) DeleteCategory(ctx context.Context, id int) error {
+	result, err := s.conn.ExecContext(ctx, deleteCategorySQL, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return storage.ErrCategoryNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// SetEventTags replaces the tags of an event in a single transaction. All tags must name
+// existing categories.
+func (s *Storage) SetEventTags(ctx context.Context, eventID int, tags []string) error {
+	err := s.inTx(ctx, nil, func(tx *sql.Tx) error {
+		// Locking the event row keeps concurrent taggings of the event in order.
+		var id int
+		err := tx.QueryRowContext(ctx, lockEventSQL, eventID).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock event: %w", err)
+		}
+
+		var missing bool
+		if err := tx.QueryRowContext(ctx, missingTagsSQL, tags).Scan(&missing); err != nil {
+			return fmt.Errorf("failed to check categories: %w", err)
+		}
+		if missing {
+			return storage.ErrCategoryNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, clearTagsSQL, eventID); err != nil {
+			return fmt.Errorf("failed to clear tags: %w", err)
+		}
+		return insertTags(ctx, tx, eventID, tags)
+	})
+	return s.wrote(ctx, err)
+}
+
+// insertTags tags an event with the existing categories among the names.
+func insertTags(ctx context.Context, tx *sql.Tx, eventID int, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, insertTagsSQL, eventID, tags); err != nil {
+		return fmt.Errorf("failed to tag event: %w", err)
+	}
+	return nil
+}
+
+// loadTags fills in the tags of all events with a single query.
+func (s *Storage) loadTags(ctx context.Context, db queryer, events []storage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID, ids := eventIndex(events)
+	rows, err := db.QueryContext(ctx, selectTagsSQL, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load tags: %w", err)
+	}
+	defer rows.Close()
+	return scanTags(rows, byID)
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,48 @@
+package postgresstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// changesChannel is the NOTIFY channel of the events_notify and attendees_notify triggers.
+const changesChannel = "calendar_events"
+
+// ListenChanges calls handle for every change announced by the database triggers, made by
+// any client, until ctx is done or the connection fails. Changes made while no listener is
+// connected are not replayed.
+func (s *Storage) ListenChanges(ctx context.Context, handle func(storage.Change)) error {
+	return listenChanges(ctx, s.dsn, handle)
+}
+
+// listenChanges listens on a connection of its own, which stays busy while it waits.
+func listenChanges(ctx context.Context, dsn string, handle func(storage.Change)) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect listener: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+changesChannel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var payload struct {
+			Type storage.ChangeType `json:"type"`
+			ID   int                `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid change notification %q: %w", notification.Payload, err)
+		}
+		handle(storage.Change{Type: payload.Type, EventID: payload.ID, At: time.Now()})
+	}
+}
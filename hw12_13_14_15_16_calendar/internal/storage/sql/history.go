@@ -0,0 +1,87 @@
+package postgresstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// AddHistory appends an entry to the event_history table.
+func (s *Storage) AddHistory(ctx context.Context, entry storage.HistoryEntry) error {
+	before, err := snapshotJSON(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := snapshotJSON(entry.After)
+	if err != nil {
+		return err
+	}
+	_, err = s.conn.ExecContext(ctx, insertHistorySQL,
+		entry.EventID, entry.Action, entry.Actor, entry.ActorUserID, entry.At, before, after)
+	if err != nil {
+		return fmt.Errorf("failed to add history: %w", err)
+	}
+	return nil
+}
+
+// ListHistory returns the history of an event, oldest first.
+func (s *Storage) ListHistory(ctx context.Context, eventID int) ([]storage.HistoryEntry, error) {
+	rows, err := s.conn.QueryContext(ctx, selectHistorySQL, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+	defer rows.Close()
+	return scanHistory(rows)
+}
+
+// RecreateEvent stores a deleted event again under its ID, with its attendees and the
+// tags whose categories still exist, in a single transaction.
+func (s *Storage) RecreateEvent(ctx context.Context, event storage.Event) error {
+	err := s.inTx(ctx, nil, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, recreateEventSQL,
+			event.ID, event.Title, event.Description, event.Start, event.End, event.AllDay, timeZone(event),
+			event.Clinic, event.UserID, event.Service, event.UID, event.ResourceName)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return storage.ErrEventExists
+		}
+		if err != nil {
+			return fmt.Errorf("failed to recreate event: %w", err)
+		}
+		for _, a := range event.Attendees {
+			if _, err := tx.ExecContext(ctx, recreateAttendeeSQL, event.ID, a.UserID, a.Role, a.Status); err != nil {
+				return fmt.Errorf("failed to recreate attendee: %w", err)
+			}
+		}
+		return insertTags(ctx, tx, event.ID, event.Tags)
+	})
+	return s.wrote(ctx, err)
+}
+
+// snapshotJSON encodes an event snapshot; nil stays NULL.
+func snapshotJSON(event *storage.Event) (interface{}, error) {
+	if event == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return string(data), nil
+}
+
+func parseSnapshot(data []byte) (*storage.Event, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var event storage.Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,261 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// Names of the statements every connection of a PoolStorage prepares when it opens.
+const (
+	stmtInsertEvent     = "insert_event"
+	stmtSelectEvent     = "select_event"
+	stmtUpdateEvent     = "update_event"
+	stmtDeleteEvent     = "delete_event"
+	stmtEventExists     = "event_exists"
+	stmtSelectAttendees = "select_attendees"
+	stmtSelectTags      = "select_tags"
+)
+
+// readStatements are prepared on the primary and on the replicas, writeStatements on the
+// primary only. Other statements go through the statement cache of pgx, which prepares them
+// on first use on each connection.
+var (
+	readStatements = map[string]string{
+		stmtSelectEvent:     selectEventSQL,
+		stmtEventExists:     eventExistsSQL,
+		stmtSelectAttendees: selectAttendeesSQL,
+		stmtSelectTags:      selectTagsSQL,
+	}
+	writeStatements = map[string]string{
+		stmtInsertEvent: insertEventSQL,
+		stmtUpdateEvent: updateEventSQL,
+		stmtDeleteEvent: deleteEventSQL,
+	}
+)
+
+// PoolStorage keeps the calendar in Postgres through a pgx connection pool, without the
+// database/sql layer. Operations on several events send their statements to the server in
+// one round trip with pgx.Batch.
+type PoolStorage struct {
+	pool     *pgxpool.Pool
+	conn     pgxConn // pool, or tx on the PoolStorage that WithinTx passes to its function
+	tx       pgx.Tx
+	dsn      string // for the change listener, which needs a dedicated connection
+	replicas *replicaSet[*pgxpool.Pool]
+}
+
+// pgxQueryer is implemented by *pgxpool.Pool and pgx.Tx.
+type pgxQueryer interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// pgxConn is implemented by *pgxpool.Pool and pgx.Tx, whose BeginFunc starts a savepoint.
+type pgxConn interface {
+	pgxQueryer
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+}
+
+// NewPool returns a storage on pools for the primary and the replicas. The pools connect on
+// first use, so that the database need not be up yet; an error means a malformed DSN.
+func NewPool(cfg config.PostgresConfig) (*PoolStorage, error) {
+	pool, err := openPool(cfg.DSN, readStatements, writeStatements)
+	if err != nil {
+		return nil, err
+	}
+	replicas := make([]*pgxpool.Pool, 0, len(cfg.Replicas))
+	for _, dsn := range cfg.Replicas {
+		replica, err := openPool(dsn, readStatements)
+		if err != nil {
+			pool.Close()
+			for _, r := range replicas {
+				r.Close()
+			}
+			return nil, err
+		}
+		replicas = append(replicas, replica)
+	}
+	queryRow := func(ctx context.Context, db *pgxpool.Pool, query string) rowScanner {
+		return db.QueryRow(ctx, query)
+	}
+	return &PoolStorage{
+		pool:     pool,
+		conn:     pool,
+		dsn:      cfg.DSN,
+		replicas: newReplicaSet(pool, replicas, cfg.MaxReplicaLag, cfg.ReplicaCheckInterval, queryRow),
+	}, nil
+}
+
+// openPool configures a pool whose connections prepare the statements when they open.
+func openPool(dsn string, statements ...map[string]string) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DSN: %w", err)
+	}
+	cfg.LazyConnect = true
+	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		for _, set := range statements {
+			for name, sql := range set {
+				if _, err := conn.Prepare(ctx, name, sql); err != nil {
+					return fmt.Errorf("failed to prepare %s: %w", name, err)
+				}
+			}
+		}
+		return nil
+	}
+	return pgxpool.ConnectConfig(context.Background(), cfg)
+}
+
+// Close stops the replica checks and closes the pools.
+func (s *PoolStorage) Close() error {
+	s.replicas.close()
+	s.pool.Close()
+	for _, r := range s.replicas.members {
+		r.db.Close()
+	}
+	return nil
+}
+
+func (s *PoolStorage) CreateEvent(ctx context.Context, event storage.Event) (int, error) {
+	var id int
+	err := s.conn.QueryRow(ctx, stmtInsertEvent, newEventArgs(event)...).Scan(&id)
+	return id, s.wrote(ctx, err)
+}
+
+// eventArgs returns the fields that updateEventSQL sets, its arguments before the ID.
+func eventArgs(event storage.Event) []interface{} {
+	return []interface{}{
+		event.Title, event.Description, event.Start, event.End, event.AllDay, timeZone(event),
+		event.Clinic, event.UserID, event.Service,
+	}
+}
+
+// newEventArgs returns the arguments of insertEventSQL.
+func newEventArgs(event storage.Event) []interface{} {
+	return append(eventArgs(event), event.UID, event.ResourceName)
+}
+
+// GetEvent reads from a replica if the caller may; see replicaSet.read.
+func (s *PoolStorage) GetEvent(ctx context.Context, id int) (event storage.Event, err error) {
+	err = s.read(ctx, func(db pgxQueryer) error {
+		event, err = getPoolEvent(ctx, db, id)
+		return err
+	})
+	return event, err
+}
+
+func getPoolEvent(ctx context.Context, db pgxQueryer, id int) (storage.Event, error) {
+	event, err := scanEvent(db.QueryRow(ctx, stmtSelectEvent, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return event, ErrNotFound
+	}
+	if err != nil {
+		return event, err
+	}
+	events := []storage.Event{event}
+	err = loadPoolDetails(ctx, db, events)
+	return events[0], err
+}
+
+// ListEvents returns the events matching the filter, like Storage.ListEvents. It reads from
+// a replica if the caller may; see replicaSet.read.
+func (s *PoolStorage) ListEvents(ctx context.Context, filter storage.Filter) (events []storage.Event, err error) {
+	query, args := listEventsSQL(filter)
+	err = s.read(ctx, func(db pgxQueryer) error {
+		events, err = queryPoolEvents(ctx, db, query, args...)
+		return err
+	})
+	return events, err
+}
+
+// queryPoolEvents runs a query selecting eventColumns and loads the details of the events.
+func queryPoolEvents(ctx context.Context, db pgxQueryer, query string, args ...interface{}) ([]storage.Event, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	events, err := scanEvents(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := loadPoolDetails(ctx, db, events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// loadPoolDetails fills in the attendees and tags of the events, sending both queries in
+// one batch.
+func loadPoolDetails(ctx context.Context, db pgxQueryer, events []storage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	byID, ids := eventIndex(events)
+	batch := &pgx.Batch{}
+	batch.Queue(stmtSelectAttendees, ids)
+	batch.Queue(stmtSelectTags, ids)
+	results := db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	rows, err := results.Query()
+	if err != nil {
+		return fmt.Errorf("failed to load attendees: %w", err)
+	}
+	err = scanAttendees(rows, byID)
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to load attendees: %w", err)
+	}
+
+	if rows, err = results.Query(); err != nil {
+		return fmt.Errorf("failed to load tags: %w", err)
+	}
+	err = scanTags(rows, byID)
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to load tags: %w", err)
+	}
+	return results.Close()
+}
+
+// DeleteEvent moves an event to the trash by setting deleted_at.
+// Returns ErrNotFound if event doesn't exist or is already deleted.
+func (s *PoolStorage) DeleteEvent(ctx context.Context, id int) error {
+	tag, err := s.conn.Exec(ctx, stmtDeleteEvent, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// UpdateEvent updates an existing event by ID.
+// It returns ErrNotFound if the event doesn't exist or is deleted.
+func (s *PoolStorage) UpdateEvent(ctx context.Context, event storage.Event) error {
+	tag, err := s.conn.Exec(ctx, stmtUpdateEvent, append(eventArgs(event), event.ID)...)
+	if err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// ListenChanges calls handle for every change announced by the database triggers, like
+// Storage.ListenChanges.
+func (s *PoolStorage) ListenChanges(ctx context.Context, handle func(storage.Change)) error {
+	return listenChanges(ctx, s.dsn, handle)
+}
@@ -0,0 +1,91 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// AddAttendee invites a user to an existing event.
+func (s *PoolStorage) AddAttendee(ctx context.Context, attendee storage.Attendee) error {
+	tag, err := s.conn.Exec(ctx, insertAttendeeSQL,
+		attendee.EventID, attendee.UserID, attendee.Role, attendee.Status)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return storage.ErrAttendeeExists
+		case pgForeignKeyViolation:
+			return ErrNotFound
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add attendee: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// ListAttendees returns the attendees of an event ordered by user ID. The check that the
+// event exists and the query of its attendees go in one batch.
+func (s *PoolStorage) ListAttendees(ctx context.Context, eventID int) ([]storage.Attendee, error) {
+	batch := &pgx.Batch{}
+	batch.Queue(stmtEventExists, eventID)
+	batch.Queue(stmtSelectAttendees, intArray([]int{eventID}))
+	results := s.conn.SendBatch(ctx, batch)
+	defer results.Close()
+
+	var exists bool
+	if err := results.QueryRow().Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to list attendees: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	rows, err := results.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attendees: %w", err)
+	}
+	events := []storage.Event{{ID: eventID}}
+	err = scanAttendees(rows, map[int]*storage.Event{eventID: &events[0]})
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attendees: %w", err)
+	}
+	if len(events[0].Attendees) > 0 {
+		return events[0].Attendees, nil
+	}
+	return []storage.Attendee{}, nil
+}
+
+// UpdateAttendee changes the role and RSVP status of an attendee.
+func (s *PoolStorage) UpdateAttendee(ctx context.Context, attendee storage.Attendee) error {
+	tag, err := s.conn.Exec(ctx, updateAttendeeSQL,
+		attendee.Role, attendee.Status, attendee.EventID, attendee.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to update attendee: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrAttendeeNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// RemoveAttendee withdraws a user's invitation to an event.
+func (s *PoolStorage) RemoveAttendee(ctx context.Context, eventID, userID int) error {
+	tag, err := s.conn.Exec(ctx, removeAttendeeSQL, eventID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove attendee: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrAttendeeNotFound
+	}
+	return s.wrote(ctx, nil)
+}
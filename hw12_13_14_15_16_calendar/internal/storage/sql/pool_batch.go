@@ -0,0 +1,122 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CreateEvents inserts all events in a single transaction and returns their IDs in order.
+// Small batches send their inserts in one pgx.Batch; large ones reserve the IDs from the
+// sequence first and are loaded with COPY. A failed COPY reports the failing event like
+// Storage.CreateEvents.
+func (s *PoolStorage) CreateEvents(ctx context.Context, events []storage.Event) ([]int, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	var ids []int
+	if len(events) >= copyThreshold {
+		err := s.conn.BeginFunc(ctx, func(tx pgx.Tx) (err error) {
+			ids, err = copyPoolEvents(ctx, tx, events)
+			return err
+		})
+		if err == nil {
+			return ids, s.wrote(ctx, nil)
+		}
+		if batchErr := copyFailure(err, events); batchErr != nil {
+			return nil, batchErr
+		}
+	}
+
+	err := s.conn.BeginFunc(ctx, func(tx pgx.Tx) (err error) {
+		ids, err = insertPoolEvents(ctx, tx, events)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, s.wrote(ctx, nil)
+}
+
+func insertPoolEvents(ctx context.Context, tx pgx.Tx, events []storage.Event) ([]int, error) {
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		batch.Queue(stmtInsertEvent, newEventArgs(event)...)
+	}
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	ids := make([]int, len(events))
+	for i := range events {
+		if err := results.QueryRow().Scan(&ids[i]); err != nil {
+			return nil, &storage.BatchError{Items: map[int]error{i: err}}
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("failed to insert batch: %w", err)
+	}
+	return ids, nil
+}
+
+func copyPoolEvents(ctx context.Context, tx pgx.Tx, events []storage.Event) ([]int, error) {
+	rows, err := tx.Query(ctx, reserveIDsSQL, len(events))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve ids: %w", err)
+	}
+	ids := make([]int, 0, len(events))
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to reserve ids: %w", err)
+	}
+
+	data := make([][]interface{}, len(events))
+	for i, event := range events {
+		data[i] = append([]interface{}{ids[i]}, newEventArgs(event)...)
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"events"}, copyColumns, pgx.CopyFromRows(data)); err != nil {
+		return nil, fmt.Errorf("failed to copy events: %w", err)
+	}
+	return ids, nil
+}
+
+// DeleteEvents moves all events to the trash in a single statement or, if any of them is
+// missing, none. Missing and repeated IDs are reported in a *storage.BatchError.
+func (s *PoolStorage) DeleteEvents(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := s.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, deleteEventsSQL, intArray(ids))
+		if err != nil {
+			return fmt.Errorf("failed to delete events: %w", err)
+		}
+		deleted := make(map[int]bool, len(ids))
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			deleted[id] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to delete events: %w", err)
+		}
+		return batchDeleteError(ids, deleted)
+	})
+	if err != nil {
+		return err
+	}
+	return s.wrote(ctx, nil)
+}
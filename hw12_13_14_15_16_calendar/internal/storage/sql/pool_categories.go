@@ -0,0 +1,96 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// CreateCategory stores a new category and returns its ID.
+func (s *PoolStorage) CreateCategory(ctx context.Context, category storage.Category) (int, error) {
+	var id int
+	err := s.conn.QueryRow(ctx, insertCategorySQL, category.Name, category.Color).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, storage.ErrCategoryExists
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to create category: %w", err)
+	}
+	return id, nil
+}
+
+// ListCategories returns all categories ordered by name.
+func (s *PoolStorage) ListCategories(ctx context.Context) ([]storage.Category, error) {
+	rows, err := s.conn.Query(ctx, selectCategoriesSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+	return scanCategories(rows)
+}
+
+// UpdateCategory renames or recolors a category. Renaming it renames the tag on all its
+// events.
+func (s *PoolStorage) UpdateCategory(ctx context.Context, category storage.Category) error {
+	tag, err := s.conn.Exec(ctx, updateCategorySQL, category.Name, category.Color, category.ID)
+	if isUniqueViolation(err) {
+		return storage.ErrCategoryExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrCategoryNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// DeleteCategory removes a category and, by cascade, its tag from all events.
+func (s *PoolStorage) DeleteCategory(ctx context.Context, id int) error {
+	tag, err := s.conn.Exec(ctx, deleteCategorySQL, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrCategoryNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// SetEventTags replaces the tags of an event in a single transaction. All tags must name
+// existing categories.
+func (s *PoolStorage) SetEventTags(ctx context.Context, eventID int, tags []string) error {
+	err := s.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		// Locking the event row keeps concurrent taggings of the event in order.
+		var id int
+		err := tx.QueryRow(ctx, lockEventSQL, eventID).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock event: %w", err)
+		}
+
+		var missing bool
+		if err := tx.QueryRow(ctx, missingTagsSQL, tags).Scan(&missing); err != nil {
+			return fmt.Errorf("failed to check categories: %w", err)
+		}
+		if missing {
+			return storage.ErrCategoryNotFound
+		}
+
+		batch := &pgx.Batch{}
+		batch.Queue(clearTagsSQL, eventID)
+		if len(tags) > 0 {
+			batch.Queue(insertTagsSQL, eventID, tags)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("failed to tag event: %w", err)
+		}
+		return nil
+	})
+	return s.wrote(ctx, err)
+}
@@ -0,0 +1,64 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// AddHistory appends an entry to the event_history table.
+func (s *PoolStorage) AddHistory(ctx context.Context, entry storage.HistoryEntry) error {
+	before, err := snapshotJSON(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := snapshotJSON(entry.After)
+	if err != nil {
+		return err
+	}
+	_, err = s.conn.Exec(ctx, insertHistorySQL,
+		entry.EventID, entry.Action, entry.Actor, entry.ActorUserID, entry.At, before, after)
+	if err != nil {
+		return fmt.Errorf("failed to add history: %w", err)
+	}
+	return nil
+}
+
+// ListHistory returns the history of an event, oldest first.
+func (s *PoolStorage) ListHistory(ctx context.Context, eventID int) ([]storage.HistoryEntry, error) {
+	rows, err := s.conn.Query(ctx, selectHistorySQL, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+	defer rows.Close()
+	return scanHistory(rows)
+}
+
+// RecreateEvent stores a deleted event again under its ID, with its attendees and the
+// tags whose categories still exist, in a single transaction and a single batch.
+func (s *PoolStorage) RecreateEvent(ctx context.Context, event storage.Event) error {
+	err := s.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		batch.Queue(recreateEventSQL, append([]interface{}{event.ID}, newEventArgs(event)...)...)
+		for _, a := range event.Attendees {
+			batch.Queue(recreateAttendeeSQL, event.ID, a.UserID, a.Role, a.Status)
+		}
+		if len(event.Tags) > 0 {
+			batch.Queue(insertTagsSQL, event.ID, event.Tags)
+		}
+		err := tx.SendBatch(ctx, batch).Close()
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return storage.ErrEventExists
+		}
+		if err != nil {
+			return fmt.Errorf("failed to recreate event: %w", err)
+		}
+		return nil
+	})
+	return s.wrote(ctx, err)
+}
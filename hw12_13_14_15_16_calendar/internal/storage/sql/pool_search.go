@@ -0,0 +1,32 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// SearchEvents returns the live events matching the query, highest rank first, like
+// Storage.SearchEvents.
+func (s *PoolStorage) SearchEvents(ctx context.Context, query storage.SearchQuery) ([]storage.SearchResult, error) {
+	text, args := searchSQL(query)
+	rows, err := s.conn.Query(ctx, text, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	results, err := scanSearchResults(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	events := resultEvents(results)
+	if err := loadPoolDetails(ctx, s.conn, events); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Event = events[i]
+	}
+	return results, nil
+}
@@ -0,0 +1,36 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// ListDeletedEvents returns the events in the trash, most recently deleted first.
+func (s *PoolStorage) ListDeletedEvents(ctx context.Context) ([]storage.Event, error) {
+	return queryPoolEvents(ctx, s.conn, selectDeletedSQL)
+}
+
+// UndeleteEvent takes an event out of the trash. Returns ErrNotFound if it is not there.
+func (s *PoolStorage) UndeleteEvent(ctx context.Context, id int) error {
+	tag, err := s.conn.Exec(ctx, undeleteEventSQL, id)
+	if err != nil {
+		return fmt.Errorf("failed to undelete event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// PurgeDeletedEvents permanently removes the events deleted before the given time, with
+// their attendees, and returns how many were removed.
+func (s *PoolStorage) PurgeDeletedEvents(ctx context.Context, before time.Time) (int, error) {
+	tag, err := s.conn.Exec(ctx, purgeDeletedSQL, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge events: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,45 @@
+package postgresstorage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// WithinTx runs fn in a serializable transaction, retried when it conflicts with another
+// one, like Storage.WithinTx. Called on the tx of an outer WithinTx, it runs fn in a
+// savepoint of that transaction.
+func (s *PoolStorage) WithinTx(ctx context.Context, fn func(tx storage.Store) error) error {
+	if s.tx != nil {
+		return s.tx.BeginFunc(ctx, func(tx pgx.Tx) error { return fn(s.withTx(tx)) })
+	}
+	err := retryTx(ctx, func() error {
+		return s.pool.BeginTxFunc(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}, func(tx pgx.Tx) error {
+			return fn(s.withTx(tx))
+		})
+	})
+	return s.replicas.wrote(ctx, err)
+}
+
+// withTx returns a storage whose operations run in tx.
+func (s *PoolStorage) withTx(tx pgx.Tx) *PoolStorage {
+	return &PoolStorage{pool: s.pool, conn: tx, tx: tx, dsn: s.dsn, replicas: s.replicas}
+}
+
+// read runs query in the transaction of s or, outside one, where replicaSet.read routes it.
+func (s *PoolStorage) read(ctx context.Context, query func(db pgxQueryer) error) error {
+	if s.tx != nil {
+		return query(s.tx)
+	}
+	return s.replicas.read(ctx, func(db *pgxpool.Pool) error { return query(db) })
+}
+
+// wrote is replicaSet.wrote for writes outside a transaction, like Storage.wrote.
+func (s *PoolStorage) wrote(ctx context.Context, err error) error {
+	if s.tx != nil {
+		return err
+	}
+	return s.replicas.wrote(ctx, err)
+}
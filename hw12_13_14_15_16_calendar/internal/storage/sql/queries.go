@@ -0,0 +1,63 @@
+package postgresstorage
+
+// The statements shared by both drivers. Statements built from a filter are assembled where
+// they are used.
+const (
+	eventColumns = `id, title, description, start, "end", allday, time_zone, clinic, userid, service, deleted_at,
+	uid, resource_name`
+
+	// The UID and the resource name are set on insert only; updates keep them.
+	insertEventSQL = `INSERT INTO events
+	(title, description, start, "end", allday, time_zone, clinic, userid, service, uid, resource_name)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	selectEventSQL = `SELECT ` + eventColumns + ` FROM events WHERE id = $1 AND deleted_at IS NULL`
+	updateEventSQL = `UPDATE events
+              SET title = $1,
+                  description = $2,
+                  start = $3,
+                  "end" = $4,
+                  allday = $5,
+                  time_zone = $6,
+                  clinic = $7,
+                  userid = $8,
+                  service = $9
+              WHERE id = $10 AND deleted_at IS NULL`
+	deleteEventSQL  = `UPDATE events SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`
+	deleteEventsSQL = `UPDATE events SET deleted_at = now() WHERE id = ANY($1::int[]) AND deleted_at IS NULL RETURNING id`
+	reserveIDsSQL   = `SELECT nextval(pg_get_serial_sequence('events', 'id')) FROM generate_series(1, $1)`
+	eventExistsSQL  = `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`
+
+	selectAttendeesSQL = `SELECT event_id, user_id, role, status FROM attendees
+	WHERE event_id = ANY($1::int[]) ORDER BY event_id, user_id`
+	insertAttendeeSQL = `INSERT INTO attendees (event_id, user_id, role, status)
+	SELECT $1, $2, $3, $4 WHERE EXISTS (SELECT 1 FROM events WHERE id = $1 AND deleted_at IS NULL)`
+	updateAttendeeSQL = `UPDATE attendees SET role = $1, status = $2 WHERE event_id = $3 AND user_id = $4 AND ` + liveEvent
+	removeAttendeeSQL = `DELETE FROM attendees WHERE event_id = $1 AND user_id = $2 AND ` + liveEvent
+
+	selectTagsSQL = `SELECT ec.event_id, c.name FROM event_categories ec JOIN categories c ON c.id = ec.category_id
+	WHERE ec.event_id = ANY($1::int[]) ORDER BY ec.event_id, c.name`
+	insertCategorySQL   = `INSERT INTO categories (name, color) VALUES ($1, $2) RETURNING id`
+	selectCategoriesSQL = `SELECT id, name, color FROM categories ORDER BY name`
+	updateCategorySQL   = `UPDATE categories SET name = $1, color = $2 WHERE id = $3`
+	deleteCategorySQL   = `DELETE FROM categories WHERE id = $1`
+	lockEventSQL        = `SELECT id FROM events WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	missingTagsSQL      = `SELECT EXISTS (SELECT 1 FROM unnest($1::text[]) AS t(name)
+	WHERE t.name NOT IN (SELECT name FROM categories))`
+	clearTagsSQL  = `DELETE FROM event_categories WHERE event_id = $1`
+	insertTagsSQL = `INSERT INTO event_categories (event_id, category_id)
+	SELECT $1, id FROM categories WHERE name = ANY($2::text[])`
+
+	insertHistorySQL = `INSERT INTO event_history (event_id, action, actor, actor_user_id, at, before, after)
+	VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7::jsonb)`
+	selectHistorySQL = `SELECT id, event_id, action, actor, actor_user_id, at, before, after
+	FROM event_history WHERE event_id = $1 ORDER BY id`
+	recreateEventSQL = `INSERT INTO events
+	(id, title, description, start, "end", allday, time_zone, clinic, userid, service, uid, resource_name)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	recreateAttendeeSQL = `INSERT INTO attendees (event_id, user_id, role, status) VALUES ($1, $2, $3, $4)`
+
+	selectDeletedSQL = `SELECT ` + eventColumns + `
+	FROM events WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id`
+	undeleteEventSQL = `UPDATE events SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	purgeDeletedSQL  = `DELETE FROM events WHERE deleted_at < $1`
+)
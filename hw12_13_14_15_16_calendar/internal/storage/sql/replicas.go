@@ -0,0 +1,202 @@
+package postgresstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/session"
+)
+
+// unreachedLSN is a session token no replica satisfies, so that reads go to the primary.
+const unreachedLSN = "FFFFFFFF/FFFFFFFF"
+
+// replicaStatusQuery returns the WAL position a replica has replayed and how far behind the
+// primary it is. A replica that has replayed all it received is not behind, however long
+// ago the primary last wrote; on a primary the position is NULL.
+const replicaStatusQuery = `SELECT pg_last_wal_replay_lsn()::text,
+	CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END::float8`
+
+// queryRowFunc runs a query returning one row on a connection handle of type DB.
+type queryRowFunc[DB any] func(ctx context.Context, db DB, query string) rowScanner
+
+// replicaSet routes the reads of a storage between the primary and its replicas. DB is the
+// connection handle of the driver: *sql.DB or *pgxpool.Pool.
+type replicaSet[DB any] struct {
+	primary  DB
+	members  []*replica[DB]
+	maxLag   time.Duration
+	queryRow queryRowFunc[DB]
+	next     atomic.Uint32      // replica to try first, for round robin
+	stop     context.CancelFunc // stops the replica checks
+	checked  chan struct{}      // closed when the replica checks have stopped
+}
+
+// replica is a read-only copy of the primary, fed by streaming replication.
+type replica[DB any] struct {
+	db    DB
+	state atomic.Pointer[replicaState]
+}
+
+// replicaState is what the last check of a replica found.
+type replicaState struct {
+	healthy  bool
+	lag      time.Duration
+	replayed uint64 // WAL position replayed, at least
+}
+
+// newReplicaSet starts checking the replicas every interval, if there are any.
+func newReplicaSet[DB any](
+	primary DB, replicas []DB, maxLag, interval time.Duration, queryRow queryRowFunc[DB],
+) *replicaSet[DB] {
+	rs := &replicaSet[DB]{primary: primary, maxLag: maxLag, queryRow: queryRow}
+	for _, db := range replicas {
+		rs.members = append(rs.members, newReplica(db))
+	}
+	if len(rs.members) > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		rs.stop, rs.checked = cancel, make(chan struct{})
+		go rs.checkReplicas(ctx, interval)
+	}
+	return rs
+}
+
+func newReplica[DB any](db DB) *replica[DB] {
+	r := &replica[DB]{db: db}
+	r.state.Store(&replicaState{})
+	return r
+}
+
+// close stops the replica checks; the caller closes the connections.
+func (rs *replicaSet[DB]) close() {
+	if rs.stop != nil {
+		rs.stop()
+		<-rs.checked
+	}
+}
+
+// check queries the replica's status.
+func (r *replica[DB]) check(ctx context.Context, queryRow queryRowFunc[DB]) {
+	var (
+		lsn sql.NullString
+		lag float64
+	)
+	state := &replicaState{}
+	if err := queryRow(ctx, r.db, replicaStatusQuery).Scan(&lsn, &lag); err == nil && lsn.Valid {
+		if replayed, err := parseLSN(lsn.String); err == nil {
+			state = &replicaState{healthy: true, lag: time.Duration(lag * float64(time.Second)), replayed: replayed}
+		}
+	}
+	r.state.Store(state)
+}
+
+// fail marks the replica unhealthy until its next check.
+func (r *replica[DB]) fail() {
+	r.state.Store(&replicaState{})
+}
+
+// serves reports whether the replica may serve a read that must see the WAL position need.
+func (r *replica[DB]) serves(need uint64, maxLag time.Duration) bool {
+	state := r.state.Load()
+	return state.healthy && state.lag <= maxLag && state.replayed >= need
+}
+
+// checkReplicas checks the replicas right away and then every interval until ctx is done.
+func (rs *replicaSet[DB]) checkReplicas(ctx context.Context, interval time.Duration) {
+	defer close(rs.checked)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, r := range rs.members {
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			r.check(checkCtx, rs.queryRow)
+			cancel()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replicaFor returns a replica the caller may read from, or nil for the primary. Only
+// callers with a session read from replicas: work of the service itself, such as following
+// the change feed, must see the primary's current state. A replica qualifies if it is
+// healthy, lags by at most maxLag and has replayed the session's last write.
+func (rs *replicaSet[DB]) replicaFor(ctx context.Context) *replica[DB] {
+	sess := session.FromContext(ctx)
+	if sess == nil || len(rs.members) == 0 {
+		return nil
+	}
+	var need uint64
+	if token := sess.Token(); token != "" {
+		lsn, err := parseLSN(token)
+		if err != nil {
+			return nil
+		}
+		need = lsn
+	}
+	first := int(rs.next.Add(1))
+	for i := range rs.members {
+		r := rs.members[(first+i)%len(rs.members)]
+		if r.serves(need, rs.maxLag) {
+			return r
+		}
+	}
+	return nil
+}
+
+// read runs query against a replica the caller may read from, or against the primary. If
+// the replica fails, it is not used until its next check and the query runs on the primary.
+func (rs *replicaSet[DB]) read(ctx context.Context, query func(db DB) error) error {
+	r := rs.replicaFor(ctx)
+	if r == nil {
+		return query(rs.primary)
+	}
+	err := query(r.db)
+	if err == nil || errors.Is(err, ErrNotFound) || ctx.Err() != nil {
+		return err
+	}
+	r.fail()
+	return query(rs.primary)
+}
+
+// wrote advances the caller's session past a write that returned err, if it succeeded, so
+// that the caller's next reads wait for a replica that has replayed it. If the primary's
+// position cannot be read, the session's reads go to the primary.
+func (rs *replicaSet[DB]) wrote(ctx context.Context, err error) error {
+	sess := session.FromContext(ctx)
+	if err != nil || sess == nil || len(rs.members) == 0 {
+		return err
+	}
+	var lsn string
+	if err := rs.queryRow(ctx, rs.primary, `SELECT pg_current_wal_lsn()::text`).Scan(&lsn); err != nil {
+		lsn = unreachedLSN
+	}
+	sess.Advance(lsn)
+	return nil
+}
+
+// parseLSN parses a WAL position written as two hexadecimal halves, like "16/B374D848".
+func parseLSN(s string) (uint64, error) {
+	hi, lo, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid WAL position %q", s)
+	}
+	h, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid WAL position %q: %w", s, err)
+	}
+	l, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid WAL position %q: %w", s, err)
+	}
+	return h<<32 | l, nil
+}
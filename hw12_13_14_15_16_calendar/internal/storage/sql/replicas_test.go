@@ -0,0 +1,160 @@
+package postgresstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/session"
+)
+
+func TestParseLSN(t *testing.T) {
+	lsn, err := parseLSN("16/B374D848")
+	if err != nil {
+		t.Fatalf("parseLSN returned error: %v", err)
+	}
+	if lsn != 0x16_B374D848 {
+		t.Errorf("expected 0x16B374D848, got %#x", lsn)
+	}
+	if last, _ := parseLSN(unreachedLSN); last != 1<<64-1 {
+		t.Errorf("expected the unreached position to be the largest, got %#x", last)
+	}
+	for _, bad := range []string{"", "16", "16/", "x/1", "1/100000000"} {
+		if _, err := parseLSN(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+// testReplicaSet returns a set of replicas in the given states, named by their index, that
+// are not checked.
+func testReplicaSet(states ...replicaState) *replicaSet[string] {
+	rs := &replicaSet[string]{primary: "primary", maxLag: time.Second}
+	for i := range states {
+		r := newReplica(strconv.Itoa(i))
+		r.state.Store(&states[i])
+		rs.members = append(rs.members, r)
+	}
+	return rs
+}
+
+func TestReplicaFor(t *testing.T) {
+	caughtUp := replicaState{healthy: true, replayed: 0x200}
+	behind := replicaState{healthy: true, replayed: 0x100}
+	lagging := replicaState{healthy: true, lag: 2 * time.Second, replayed: 0x300}
+	down := replicaState{}
+
+	tests := []struct {
+		name    string
+		states  []replicaState
+		session *session.Session
+		want    string // the replica's index, or "primary"
+	}{
+		{"no session", []replicaState{caughtUp}, nil, "primary"},
+		{"no token", []replicaState{behind}, session.New(""), "0"},
+		{"caught up", []replicaState{behind, caughtUp}, session.New("0/200"), "1"},
+		{"none caught up", []replicaState{behind}, session.New("0/200"), "primary"},
+		{"too far behind", []replicaState{lagging}, session.New(""), "primary"},
+		{"unhealthy", []replicaState{down}, session.New(""), "primary"},
+		{"bad token", []replicaState{caughtUp}, session.New("garbage"), "primary"},
+		{"unreached", []replicaState{caughtUp}, session.New(unreachedLSN), "primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := testReplicaSet(tt.states...)
+			ctx := context.Background()
+			if tt.session != nil {
+				ctx = session.NewContext(ctx, tt.session)
+			}
+			var got string
+			if err := rs.read(ctx, func(db string) error { got = db; return nil }); err != nil {
+				t.Fatalf("read returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected the read to go to %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReadFallsBackToPrimary(t *testing.T) {
+	rs := testReplicaSet(replicaState{healthy: true})
+	ctx := session.NewContext(context.Background(), session.New(""))
+
+	var used []string
+	err := rs.read(ctx, func(db string) error {
+		used = append(used, db)
+		if db != "primary" {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read returned error: %v", err)
+	}
+	if fmt.Sprint(used) != "[0 primary]" {
+		t.Errorf("expected the replica and then the primary, got %v", used)
+	}
+	if rs.replicaFor(ctx) != nil {
+		t.Error("expected the failed replica to be skipped until its next check")
+	}
+
+	// A missing event is an answer, not a failure of the replica.
+	rs = testReplicaSet(replicaState{healthy: true})
+	used = nil
+	err = rs.read(ctx, func(db string) error {
+		used = append(used, db)
+		return ErrNotFound
+	})
+	if !errors.Is(err, ErrNotFound) || len(used) != 1 {
+		t.Errorf("expected ErrNotFound from the replica alone, got %v after %v", err, used)
+	}
+}
+
+// lsnRow scans a WAL position or fails.
+type lsnRow struct {
+	lsn string
+	err error
+}
+
+func (r lsnRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*string) = r.lsn
+	return nil
+}
+
+func TestWrote(t *testing.T) {
+	failed := errors.New("failed")
+	tests := []struct {
+		name     string
+		replicas int
+		row      lsnRow
+		err      error
+		want     string
+	}{
+		{"advances", 1, lsnRow{lsn: "0/200"}, nil, "0/200"},
+		{"no replicas", 0, lsnRow{lsn: "0/200"}, nil, "0/100"},
+		{"failed write", 1, lsnRow{lsn: "0/200"}, failed, "0/100"},
+		{"unknown position", 1, lsnRow{err: failed}, nil, unreachedLSN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := testReplicaSet(make([]replicaState, tt.replicas)...)
+			rs.queryRow = func(context.Context, string, string) rowScanner { return tt.row }
+			sess := session.New("0/100")
+
+			err := rs.wrote(session.NewContext(context.Background(), sess), tt.err)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected error %v, got %v", tt.err, err)
+			}
+			if got := sess.Token(); got != tt.want {
+				t.Errorf("expected token %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
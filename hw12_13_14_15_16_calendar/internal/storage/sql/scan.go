@@ -0,0 +1,156 @@
+package postgresstorage
+
+import (
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// rowScanner is implemented by the rows of both drivers: *sql.Row, *sql.Rows, pgx.Row and
+// pgx.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// rowIterator is implemented by *sql.Rows and pgx.Rows. The caller closes the rows.
+type rowIterator interface {
+	rowScanner
+	Next() bool
+	Err() error
+}
+
+// scanEvent reads the eventColumns of a row.
+func scanEvent(row rowScanner) (storage.Event, error) {
+	var event storage.Event
+	err := row.Scan(
+		&event.ID,
+		&event.Title,
+		&event.Description,
+		&event.Start,
+		&event.End,
+		&event.AllDay,
+		&event.TimeZone,
+		&event.Clinic,
+		&event.UserID,
+		&event.Service,
+		&event.DeletedAt,
+		&event.UID,
+		&event.ResourceName)
+	return event, err
+}
+
+// scanEvents reads rows of eventColumns.
+func scanEvents(rows rowIterator) ([]storage.Event, error) {
+	var events []storage.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// eventIndex returns the events by ID and their IDs as an argument for $n::int[].
+func eventIndex(events []storage.Event) (map[int]*storage.Event, string) {
+	byID := make(map[int]*storage.Event, len(events))
+	ids := make([]int, len(events))
+	for i := range events {
+		byID[events[i].ID] = &events[i]
+		ids[i] = events[i].ID
+	}
+	return byID, intArray(ids)
+}
+
+// scanAttendees adds the rows of selectAttendeesSQL to the events.
+func scanAttendees(rows rowIterator, byID map[int]*storage.Event) error {
+	for rows.Next() {
+		var a storage.Attendee
+		if err := rows.Scan(&a.EventID, &a.UserID, &a.Role, &a.Status); err != nil {
+			return err
+		}
+		if ev, ok := byID[a.EventID]; ok {
+			ev.Attendees = append(ev.Attendees, a)
+		}
+	}
+	return rows.Err()
+}
+
+// scanTags adds the rows of selectTagsSQL to the events.
+func scanTags(rows rowIterator, byID map[int]*storage.Event) error {
+	for rows.Next() {
+		var (
+			eventID int
+			name    string
+		)
+		if err := rows.Scan(&eventID, &name); err != nil {
+			return err
+		}
+		if ev, ok := byID[eventID]; ok {
+			ev.Tags = append(ev.Tags, name)
+		}
+	}
+	return rows.Err()
+}
+
+// scanCategories reads the rows of selectCategoriesSQL.
+func scanCategories(rows rowIterator) ([]storage.Category, error) {
+	categories := []storage.Category{}
+	for rows.Next() {
+		var c storage.Category
+		if err := rows.Scan(&c.ID, &c.Name, &c.Color); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// scanHistory reads the rows of selectHistorySQL.
+func scanHistory(rows rowIterator) ([]storage.HistoryEntry, error) {
+	var entries []storage.HistoryEntry
+	for rows.Next() {
+		var (
+			entry         storage.HistoryEntry
+			before, after []byte
+			err           error
+		)
+		if err := rows.Scan(&entry.ID, &entry.EventID, &entry.Action, &entry.Actor, &entry.ActorUserID,
+			&entry.At, &before, &after); err != nil {
+			return nil, err
+		}
+		if entry.Before, err = parseSnapshot(before); err != nil {
+			return nil, err
+		}
+		if entry.After, err = parseSnapshot(after); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// scanSearchResults reads the rows of a search statement, without the events' details.
+func scanSearchResults(rows rowIterator) ([]storage.SearchResult, error) {
+	var results []storage.SearchResult
+	for rows.Next() {
+		var (
+			r   storage.SearchResult
+			err error
+		)
+		if r.Event, err = scanEvent(withColumns{rows, []interface{}{&r.Rank, &r.Title, &r.Description}}); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// withColumns scans the columns following eventColumns into extra.
+type withColumns struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+func (w withColumns) Scan(dest ...interface{}) error {
+	return w.row.Scan(append(dest, w.extra...)...)
+}
@@ -0,0 +1,98 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// titleHeadline and descriptionHeadline configure ts_headline for the title, which is highlighted whole, and
+// for the fragment of the description.
+var (
+	titleHeadline = fmt.Sprintf(`HighlightAll=true, StartSel="%s", StopSel="%s"`,
+		storage.HighlightStart, storage.HighlightStop)
+	descriptionHeadline = fmt.Sprintf(`MaxWords=30, MinWords=15, StartSel="%s", StopSel="%s"`,
+		storage.HighlightStart, storage.HighlightStop)
+)
+
+// SearchEvents returns the live events matching the query, highest rank first. The GIN
+// index on the generated search column finds the candidates and ts_rank orders them.
+func (s *Storage) SearchEvents(ctx context.Context, query storage.SearchQuery) ([]storage.SearchResult, error) {
+	text, args := searchSQL(query)
+	rows, err := s.conn.QueryContext(ctx, text, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	defer rows.Close()
+	results, err := scanSearchResults(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	events := resultEvents(results)
+	if err := s.loadDetails(ctx, s.conn, events); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Event = events[i]
+	}
+	return results, nil
+}
+
+// searchSQL returns the statement finding and ranking the events that match the query.
+func searchSQL(query storage.SearchQuery) (string, []interface{}) {
+	where, args := filterWhere(query.Filter)
+	args = append(args, tsQuery(query.Phrases), titleHeadline, descriptionHeadline)
+	n := len(args)
+	where = append(where, `search @@ q`)
+	text := fmt.Sprintf(`SELECT %s, ts_rank(search, q) AS rank,
+	ts_headline('simple', title, q, $%d), ts_headline('simple', description, q, $%d)
+	FROM events, to_tsquery('simple', $%d) AS q
+	WHERE %s ORDER BY rank DESC, start, id`,
+		eventColumns, n-1, n, n-2, strings.Join(where, ` AND `))
+	if query.Limit > 0 {
+		text += fmt.Sprintf(` LIMIT %d`, query.Limit)
+	}
+	return text, args
+}
+
+// resultEvents returns the events of the results, to load their details.
+func resultEvents(results []storage.SearchResult) []storage.Event {
+	events := make([]storage.Event, len(results))
+	for i := range results {
+		events[i] = results[i].Event
+	}
+	return events
+}
+
+// tsQuery renders the phrases as a tsquery that needs all of them. The words of a phrase
+// must follow each other within the title (weight A) or within the description (B).
+func tsQuery(phrases [][]string) string {
+	terms := make([]string, len(phrases))
+	for i, phrase := range phrases {
+		if len(phrase) == 1 {
+			terms[i] = quoteLexeme(phrase[0], "")
+			continue
+		}
+		var fields [2]string
+		for j, weight := range []string{"A", "B"} {
+			words := make([]string, len(phrase))
+			for k, word := range phrase {
+				words[k] = quoteLexeme(word, weight)
+			}
+			fields[j] = "(" + strings.Join(words, " <-> ") + ")"
+		}
+		terms[i] = "(" + fields[0] + " | " + fields[1] + ")"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// quoteLexeme quotes a word of storage.SearchWords, which has no quotes or backslashes.
+func quoteLexeme(word, weight string) string {
+	if weight != "" {
+		return "'" + word + "':" + weight
+	}
+	return "'" + word + "'"
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	// Import pgx driver for database/sql usage with Postgres storage.
 	_ "github.com/jackc/pgx/v4/stdlib"
@@ -13,12 +15,18 @@ import (
 )
 
 var (
-	ErrNotFound      = errors.New("event not found")
+	ErrNotFound      = storage.ErrEventNotFound
 	ErrContextCancel = errors.New("operation canceled")
 )
 
+// Storage keeps the calendar in Postgres through database/sql and the pgx stdlib driver.
 type Storage struct {
-	db *sql.DB
+	db       *sql.DB
+	conn     sqlConn // db, or tx on the Storage that WithinTx passes to its function
+	tx       *sql.Tx
+	txConn   *sql.Conn // the connection tx runs on, for COPY
+	dsn      string    // for the change listener, which needs a dedicated connection
+	replicas *replicaSet[*sql.DB]
 }
 
 func New(cfg config.PostgresConfig) *Storage {
@@ -26,92 +34,164 @@ func New(cfg config.PostgresConfig) *Storage {
 	if err != nil {
 		panic(err) // or return error
 	}
-	return &Storage{db: db}
-}
-
-func (s *Storage) CreateEvent(ctx context.Context, event storage.Event) error {
-	query := `INSERT INTO events (title, description, start, "end", allday, clinic, userid, service)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err := s.db.ExecContext(ctx, query,
-		event.Title, event.Description, event.Start, event.End, event.AllDay, event.Clinic, event.UserID, event.Service)
-	return err
-}
-
-func (s *Storage) GetEvent(ctx context.Context, id int) (storage.Event, error) {
-	var event storage.Event
-	query := `SELECT id, title, description, start, "end", allday, clinic, userid, service FROM events WHERE id = $1`
-	err := s.db.QueryRowContext(ctx, query, id).Scan(
-		&event.ID,
-		&event.Title,
-		&event.Description,
-		&event.Start,
-		&event.End,
-		&event.AllDay,
-		&event.Clinic,
-		&event.UserID,
-		&event.Service)
-	return event, err
+	replicas := make([]*sql.DB, len(cfg.Replicas))
+	for i, dsn := range cfg.Replicas {
+		if replicas[i], err = sql.Open("pgx", dsn); err != nil {
+			panic(err)
+		}
+	}
+	queryRow := func(ctx context.Context, db *sql.DB, query string) rowScanner {
+		return db.QueryRowContext(ctx, query)
+	}
+	return &Storage{
+		db:       db,
+		conn:     db,
+		dsn:      cfg.DSN,
+		replicas: newReplicaSet(db, replicas, cfg.MaxReplicaLag, cfg.ReplicaCheckInterval, queryRow),
+	}
 }
 
-func (s *Storage) ListEvents(ctx context.Context, period storage.Period) ([]storage.Event, error) {
-	query := `SELECT id, title, description, start, "end", allday, clinic, userid, service FROM events`
-	var whereClause string
+// Close stops the replica checks and closes the connections.
+func (s *Storage) Close() error {
+	s.replicas.close()
+	errs := []error{s.db.Close()}
+	for _, r := range s.replicas.members {
+		errs = append(errs, r.db.Close())
+	}
+	return errors.Join(errs...)
+}
 
-	switch period {
-	case storage.PeriodDay:
-		whereClause = ` WHERE date_trunc('day', start) = date_trunc('day', CURRENT_DATE)`
-	case storage.PeriodWeek:
-		whereClause = ` WHERE date_trunc('week', start) = date_trunc('week', CURRENT_DATE)`
-	case storage.PeriodMonth:
-		whereClause = ` WHERE date_trunc('month', start) = date_trunc('month', CURRENT_DATE)`
-	case storage.PeriodAll:
-		// No WHERE clause needed
-	default:
-		// Optionally handle unknown periods (could return error instead)
-		whereClause = ` WHERE 1=0` // Returns no rows for unknown periods
+func (s *Storage) CreateEvent(ctx context.Context, event storage.Event) (int, error) {
+	var id int
+	err := s.conn.QueryRowContext(ctx, insertEventSQL,
+		event.Title, event.Description, event.Start, event.End, event.AllDay, timeZone(event),
+		event.Clinic, event.UserID, event.Service, event.UID, event.ResourceName).Scan(&id)
+	return id, s.wrote(ctx, err)
+}
+
+// GetEvent reads from a replica if the caller may; see replicaSet.read.
+func (s *Storage) GetEvent(ctx context.Context, id int) (event storage.Event, err error) {
+	err = s.read(ctx, func(db queryer) error {
+		event, err = s.getEvent(ctx, db, id)
+		return err
+	})
+	return event, err
+}
+
+func (s *Storage) getEvent(ctx context.Context, db queryer, id int) (storage.Event, error) {
+	event, err := scanEvent(db.QueryRowContext(ctx, selectEventSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return event, ErrNotFound
 	}
+	if err != nil {
+		return event, err
+	}
+	events := []storage.Event{event}
+	err = s.loadDetails(ctx, db, events)
+	return events[0], err
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// ListEvents returns the events matching the filter.
+// Deleted events are left out. Period boundaries are computed in Go
+// (storage.Filter.Bounds) so that both backends agree on the caller's zone and ISO weeks;
+// the overlap condition mirrors storage.Filter.Matches. It reads from a replica if the
+// caller may; see replicaSet.read.
+func (s *Storage) ListEvents(ctx context.Context, filter storage.Filter) (events []storage.Event, err error) {
+	query, args := listEventsSQL(filter)
+	err = s.read(ctx, func(db queryer) error {
+		events, err = s.queryEvents(ctx, db, query, args...)
+		return err
+	})
+	return events, err
+}
+
+// listEventsSQL returns the statement selecting the events that match the filter.
+func listEventsSQL(filter storage.Filter) (string, []interface{}) {
+	where, args := filterWhere(filter)
+	return `SELECT ` + eventColumns + ` FROM events WHERE ` + strings.Join(where, ` AND `) + ` ORDER BY start, id`, args
+}
 
-	if whereClause != "" {
-		query += whereClause
+// filterWhere translates a filter into conditions on live events, numbering its
+// arguments from $1.
+func filterWhere(filter storage.Filter) ([]string, []interface{}) {
+	where := []string{`deleted_at IS NULL`}
+	var args []interface{}
+	if from, to, bounded := filter.Bounds(); bounded {
+		args = append(args, from, to)
+		where = append(where, `start < $2 AND (start >= $1 OR "end" > $1)`)
+	}
+	if len(filter.UserIDs) > 0 {
+		args = append(args, intArray(filter.UserIDs))
+		n := len(args)
+		where = append(where, fmt.Sprintf(`(userid = ANY($%d::int[]) OR EXISTS (`+
+			`SELECT 1 FROM attendees a WHERE a.event_id = events.id AND a.user_id = ANY($%d::int[])))`, n, n))
+	}
+	if filter.Clinic != "" {
+		args = append(args, filter.Clinic)
+		where = append(where, fmt.Sprintf(`clinic = $%d`, len(args)))
+	}
+	if filter.Service != "" {
+		args = append(args, filter.Service)
+		where = append(where, fmt.Sprintf(`service = $%d`, len(args)))
+	}
+	if filter.ResourceName != "" {
+		args = append(args, filter.ResourceName)
+		where = append(where, fmt.Sprintf(`resource_name = $%d`, len(args)))
+	}
+	if len(filter.Tags) > 0 {
+		args = append(args, filter.Tags)
+		where = append(where, fmt.Sprintf(`EXISTS (SELECT 1 FROM event_categories ec `+
+			`JOIN categories c ON c.id = ec.category_id `+
+			`WHERE ec.event_id = events.id AND c.name = ANY($%d::text[]))`, len(args)))
 	}
+	return where, args
+}
 
-	rows, err := s.db.QueryContext(ctx, query)
+// queryEvents runs a query selecting eventColumns and loads the details of the events.
+func (s *Storage) queryEvents(
+	ctx context.Context, db queryer, query string, args ...interface{},
+) ([]storage.Event, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var events []storage.Event
-	for rows.Next() {
-		var event storage.Event
-		if err := rows.Scan(
-			&event.ID,
-			&event.Title,
-			&event.Description,
-			&event.Start,
-			&event.End,
-			&event.AllDay,
-			&event.Clinic,
-			&event.UserID,
-			&event.Service); err != nil {
-			return nil, err
-		}
-		events = append(events, event)
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadDetails(ctx, db, events); err != nil {
+		return nil, err
 	}
 	return events, nil
 }
 
-// DeleteEvent removes an event by ID. Returns ErrNotFound if event doesn't exist.
+// loadDetails fills in the attendees and tags of the events.
+func (s *Storage) loadDetails(ctx context.Context, db queryer, events []storage.Event) error {
+	if err := s.loadAttendees(ctx, db, events); err != nil {
+		return err
+	}
+	return s.loadTags(ctx, db, events)
+}
+
+// DeleteEvent moves an event to the trash by setting deleted_at.
+// Returns ErrNotFound if event doesn't exist or is already deleted.
 func (s *Storage) DeleteEvent(ctx context.Context, id int) error {
 	// Check context before starting operation
 	select {
 	case <-ctx.Done():
-		return ErrContextCancel
+		return fmt.Errorf("%w: %w", ErrContextCancel, ctx.Err())
 	default:
 	}
 
-	// Execute SQL delete operation
-	result, err := s.db.ExecContext(ctx,
-		`DELETE FROM events WHERE id = $1`, id)
+	// Execute SQL soft delete operation
+	result, err := s.conn.ExecContext(ctx, deleteEventSQL, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete event: %w", err)
 	}
@@ -126,25 +206,15 @@ func (s *Storage) DeleteEvent(ctx context.Context, id int) error {
 		return ErrNotFound
 	}
 
-	return nil
+	return s.wrote(ctx, nil)
 }
 
 // UpdateEvent updates an existing event by ID.
-// It returns ErrNotFound if the event doesn't exist.
+// It returns ErrNotFound if the event doesn't exist or is deleted.
 func (s *Storage) UpdateEvent(ctx context.Context, event storage.Event) error {
-	query := `UPDATE events
-              SET title = $1,
-                  description = $2,
-                  start = $3,
-                  "end" = $4,
-                  allday = $5,
-                  clinic = $6,
-                  userid = $7,
-                  service = $8
-              WHERE id = $9`
-	result, err := s.db.ExecContext(ctx, query,
+	result, err := s.conn.ExecContext(ctx, updateEventSQL,
 		event.Title, event.Description, event.Start, event.End,
-		event.AllDay, event.Clinic, event.UserID, event.Service, event.ID)
+		event.AllDay, timeZone(event), event.Clinic, event.UserID, event.Service, event.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update event: %w", err)
 	}
@@ -152,5 +222,22 @@ func (s *Storage) UpdateEvent(ctx context.Context, event storage.Event) error {
 	if rowsAffected == 0 {
 		return ErrNotFound
 	}
-	return nil
+	return s.wrote(ctx, nil)
+}
+
+// timeZone returns the zone name stored for the event.
+func timeZone(event storage.Event) string {
+	if event.TimeZone == "" {
+		return "UTC"
+	}
+	return event.TimeZone
+}
+
+// intArray renders ids as a Postgres array literal for $n::int[] parameters.
+func intArray(ids []int) string {
+	items := make([]string, len(ids))
+	for i, id := range ids {
+		items[i] = strconv.Itoa(id)
+	}
+	return "{" + strings.Join(items, ",") + "}"
 }
@@ -3,17 +3,21 @@ package postgresstorage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgconn"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/pressly/goose/v3"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
 	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage/storagetest"
 	"gopkg.in/yaml.v3"
 )
 
@@ -80,13 +84,65 @@ func runGooseMigrations(dsn, migrationsPath string) error {
 //nolint:revive // temporary
 func countEvents(store *Storage, ctx context.Context) (int, error) {
 	var count int
-	row := store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events")
+	row := store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events WHERE deleted_at IS NULL")
 	if err := row.Scan(&count); err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
+// TestConformance runs the shared storage suite against the database in POSTGRES_DSN,
+// e.g. a local or throwaway instance with the migrations applied by the test.
+func TestConformance(t *testing.T) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		if os.Getenv("CI") == "" { // only show details locally
+			t.Skipf("Skipping SQL tests: could not run migrations (%v)", err)
+		}
+		t.Skip("Skipping PSQL tests: could not run migrations (details hidden in CI)")
+	}
+	store := New(cfg)
+	storagetest.Run(t, func(*testing.T) storage.Store { return store })
+}
+
+// TestPoolConformance runs the shared storage suite against PoolStorage.
+func TestPoolConformance(t *testing.T) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		t.Skip("Skipping PSQL tests: could not run migrations")
+	}
+	store, err := NewPool(cfg)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+	defer store.Close()
+	storagetest.Run(t, func(*testing.T) storage.Store { return store })
+}
+
+// BenchmarkDrivers compares the database/sql and the pgxpool storage on the database in
+// POSTGRES_DSN:
+//
+//	go test -run '^$' -bench Drivers ./internal/storage/sql
+func BenchmarkDrivers(b *testing.B) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		b.Skip("Skipping PSQL benchmarks: could not run migrations")
+	}
+	pool, err := NewPool(cfg)
+	if err != nil {
+		b.Fatalf("NewPool: %v", err)
+	}
+	defer pool.Close()
+	stdlib := New(cfg)
+	defer stdlib.Close()
+
+	b.Run("stdlib", func(b *testing.B) { storagetest.Bench(b, stdlib) })
+	b.Run("pgxpool", func(b *testing.B) { storagetest.Bench(b, pool) })
+}
+
 func TestCreateAndGetEvent(t *testing.T) {
 	cfg, migrationsPath := testConfig()
 	dsn := os.Getenv("POSTGRES_DSN")
@@ -110,16 +166,16 @@ func TestCreateAndGetEvent(t *testing.T) {
 	event := storage.Event{
 		Title:       "Test Event",
 		Description: "A test event",
-		AllDay:      2, // meaning 2 hours
+		TimeZone:    "UTC",
 	}
 
 	start := time.Now()
-	end := start.Add(time.Duration(event.AllDay) * time.Hour)
+	end := start.Add(2 * time.Hour)
 
 	event.Start = &start
 	event.End = &end
 
-	err = store.CreateEvent(ctx, event)
+	_, err = store.CreateEvent(ctx, event)
 	if err != nil {
 		t.Fatalf("CreateEvent failed: %v", err)
 	}
@@ -141,7 +197,7 @@ func TestCreateAndGetEvent(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetEvent failed: %v", err)
 	}
-	if got.Title != event.Title || got.Description != event.Description || got.AllDay != event.AllDay {
+	if got.Title != event.Title || got.Description != event.Description || got.TimeZone != event.TimeZone {
 		t.Errorf("GetEvent returned wrong data: got %+v, want %+v", got, event)
 	}
 	_, err = store.db.ExecContext(ctx, "DELETE FROM events WHERE id = $1", id)
@@ -162,3 +218,414 @@ func assertEventCountUnchanged(ctx context.Context, t *testing.T, store *Storage
 		t.Errorf("Expected event count to be unchanged after test, before=%d after=%d", countBefore, countAfter)
 	}
 }
+
+func TestAttendees(t *testing.T) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		t.Skip("Skipping PSQL tests: could not run migrations")
+	}
+	store := New(cfg)
+	ctx := context.Background()
+	countBefore, err := countEvents(store, ctx)
+	if err != nil {
+		t.Fatalf("Failed to count events before: %v", err)
+	}
+
+	if _, err := store.CreateEvent(ctx, storage.Event{Title: "Attendees", Description: "test"}); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	var id int
+	if err := store.db.QueryRowContext(ctx, "SELECT id FROM events ORDER BY id DESC LIMIT 1").Scan(&id); err != nil {
+		t.Fatalf("Failed to get last inserted id: %v", err)
+	}
+
+	for _, uid := range []int{9, 3} {
+		a := storage.Attendee{EventID: id, UserID: uid, Role: storage.RoleRequired, Status: storage.RSVPPending}
+		if err := store.AddAttendee(ctx, a); err != nil {
+			t.Fatalf("AddAttendee failed: %v", err)
+		}
+	}
+	dup := storage.Attendee{EventID: id, UserID: 3, Role: storage.RoleRequired, Status: storage.RSVPPending}
+	if err := store.AddAttendee(ctx, dup); !errors.Is(err, storage.ErrAttendeeExists) {
+		t.Errorf("expected ErrAttendeeExists, got %v", err)
+	}
+	if err := store.UpdateAttendee(ctx, storage.Attendee{
+		EventID: id, UserID: 9, Role: storage.RoleRequired, Status: storage.RSVPAccepted,
+	}); err != nil {
+		t.Fatalf("UpdateAttendee failed: %v", err)
+	}
+
+	events, err := store.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	for _, ev := range events {
+		if ev.ID != id {
+			continue
+		}
+		if len(ev.Attendees) != 2 || ev.Attendees[0].UserID != 3 || ev.Attendees[1].Status != storage.RSVPAccepted {
+			t.Errorf("unexpected attendees: %+v", ev.Attendees)
+		}
+	}
+
+	// Deleting the event cascades to its attendees.
+	if err := store.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	if _, err := store.ListAttendees(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	assertEventCountUnchanged(ctx, t, store, countBefore)
+}
+
+func TestBatch(t *testing.T) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		t.Skip("Skipping PSQL tests: could not run migrations")
+	}
+	store := New(cfg)
+	ctx := context.Background()
+	countBefore, err := countEvents(store, ctx)
+	if err != nil {
+		t.Fatalf("Failed to count events before: %v", err)
+	}
+
+	// Below and above copyThreshold, so that both the INSERT and the COPY paths run.
+	for _, n := range []int{3, copyThreshold + 1} {
+		events := make([]storage.Event, n)
+		for i := range events {
+			events[i] = storage.Event{Title: fmt.Sprintf("Batch %d", i), Description: "test"}
+		}
+		ids, err := store.CreateEvents(ctx, events)
+		if err != nil {
+			t.Fatalf("CreateEvents(%d) failed: %v", n, err)
+		}
+		if len(ids) != n {
+			t.Fatalf("got %d ids, want %d", len(ids), n)
+		}
+		got, err := store.GetEvent(ctx, ids[n-1])
+		if err != nil || got.Title != events[n-1].Title {
+			t.Errorf("GetEvent(%d) = %+v, %v", ids[n-1], got, err)
+		}
+
+		var batchErr *storage.BatchError
+		if err := store.DeleteEvents(ctx, append([]int{ids[0]}, ids[0])); !errors.As(err, &batchErr) {
+			t.Errorf("expected BatchError for a repeated id, got %v", err)
+		}
+		if err := store.DeleteEvents(ctx, ids); err != nil {
+			t.Fatalf("DeleteEvents failed: %v", err)
+		}
+	}
+
+	// Within WithinTx, COPY runs on the connection of the transaction and is undone with it.
+	errRollback := errors.New("roll back")
+	err = store.WithinTx(ctx, func(tx storage.Store) error {
+		events := make([]storage.Event, copyThreshold)
+		for i := range events {
+			events[i] = storage.Event{Title: fmt.Sprintf("Copied %d", i), Description: "test"}
+		}
+		ids, err := tx.CreateEvents(ctx, events)
+		if err != nil {
+			return err
+		}
+		if got, err := tx.GetEvent(ctx, ids[len(ids)-1]); err != nil || got.Title != events[len(ids)-1].Title {
+			t.Errorf("GetEvent(%d) in the transaction = %+v, %v", ids[len(ids)-1], got, err)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Errorf("WithinTx: got %v, want %v", err, errRollback)
+	}
+
+	long := storage.Event{Title: "This title is much longer than forty characters", Description: "test"}
+	var batchErr *storage.BatchError
+	if _, err := store.CreateEvents(ctx, []storage.Event{{Title: "Valid"}, long}); !errors.As(err, &batchErr) ||
+		batchErr.Items[1] == nil {
+		t.Errorf("expected BatchError for item 1, got %v", err)
+	}
+
+	// A failed COPY reports the failing event too, with both drivers.
+	pool, err := NewPool(cfg)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+	defer pool.Close()
+	events := make([]storage.Event, copyThreshold)
+	for i := range events {
+		events[i] = storage.Event{Title: fmt.Sprintf("Copied %d", i), Description: "test"}
+	}
+	events[copyThreshold-3] = long
+	for _, s := range []storage.Store{store, pool} {
+		var batchErr *storage.BatchError
+		if _, err := s.CreateEvents(ctx, events); !errors.As(err, &batchErr) ||
+			len(batchErr.Items) != 1 || batchErr.Items[copyThreshold-3] == nil {
+			t.Errorf("%T: expected BatchError for item %d, got %v", s, copyThreshold-3, err)
+		}
+	}
+	assertEventCountUnchanged(ctx, t, store, countBefore)
+}
+
+func TestCopyFailure(t *testing.T) {
+	events := make([]storage.Event, 5)
+	pgErr := &pgconn.PgError{Code: "22001", Where: `COPY events, line 3, column title: "This title is much longer"`}
+	var batchErr *storage.BatchError
+	if err := copyFailure(fmt.Errorf("failed to copy events: %w", pgErr), events); !errors.As(err, &batchErr) ||
+		len(batchErr.Items) != 1 || !errors.Is(batchErr.Items[2], pgErr) {
+		t.Errorf("expected BatchError for item 2, got %v", err)
+	}
+
+	for _, err := range []error{
+		errors.New("connection reset"),
+		&pgconn.PgError{Code: "57014"},
+		&pgconn.PgError{Code: "22001", Where: "COPY events, line 9, column title"},
+	} {
+		if got := copyFailure(err, events); got != nil {
+			t.Errorf("copyFailure(%v) = %v, want nil", err, got)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		t.Skip("Skipping PSQL tests: could not run migrations")
+	}
+	store := New(cfg)
+	ctx := context.Background()
+	countBefore, err := countEvents(store, ctx)
+	if err != nil {
+		t.Fatalf("Failed to count events before: %v", err)
+	}
+
+	id, err := store.CreateEvent(ctx, storage.Event{Title: "History", Description: "test"})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	event, err := store.GetEvent(ctx, id)
+	if err != nil {
+		t.Fatalf("GetEvent failed: %v", err)
+	}
+	event.Attendees = []storage.Attendee{{EventID: id, UserID: 4, Role: storage.RoleOptional, Status: storage.RSVPAccepted}}
+	uid := 7
+	for _, entry := range []storage.HistoryEntry{
+		{EventID: id, Action: storage.ActionCreated, Actor: "front-desk", ActorUserID: &uid, At: time.Now(), After: &event},
+		{EventID: id, Action: storage.ActionDeleted, Actor: "anonymous", At: time.Now(), Before: &event},
+	} {
+		if err := store.AddHistory(ctx, entry); err != nil {
+			t.Fatalf("AddHistory failed: %v", err)
+		}
+	}
+	entries, err := store.ListHistory(ctx, id)
+	if err != nil {
+		t.Fatalf("ListHistory failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Before != nil || entries[1].After != nil ||
+		entries[1].Before.Attendees[0].UserID != 4 || *entries[0].ActorUserID != uid {
+		t.Errorf("unexpected history %+v", entries)
+	}
+	if _, err := store.db.ExecContext(ctx, "DELETE FROM event_history WHERE event_id = $1", id); err == nil {
+		t.Error("expected the history to be append-only")
+	}
+
+	if err := store.RecreateEvent(ctx, event); !errors.Is(err, storage.ErrEventExists) {
+		t.Errorf("expected ErrEventExists, got %v", err)
+	}
+	if err := store.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	if err := store.RecreateEvent(ctx, event); err != nil {
+		t.Fatalf("RecreateEvent failed: %v", err)
+	}
+	if got, err := store.GetEvent(ctx, id); err != nil || len(got.Attendees) != 1 {
+		t.Errorf("GetEvent after RecreateEvent: %+v, %v", got, err)
+	}
+	if err := store.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	assertEventCountUnchanged(ctx, t, store, countBefore)
+}
+
+func TestTrash(t *testing.T) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		t.Skip("Skipping PSQL tests: could not run migrations")
+	}
+	store := New(cfg)
+	ctx := context.Background()
+
+	id, err := store.CreateEvent(ctx, storage.Event{Title: "Trash", Description: "test"})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	attendee := storage.Attendee{EventID: id, UserID: 4, Role: storage.RoleRequired, Status: storage.RSVPPending}
+	if err := store.AddAttendee(ctx, attendee); err != nil {
+		t.Fatalf("AddAttendee failed: %v", err)
+	}
+	if err := store.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+
+	if _, err := store.GetEvent(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a deleted event to be hidden, got %v", err)
+	}
+	if err := store.DeleteEvent(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a second delete to fail, got %v", err)
+	}
+	if err := store.UpdateEvent(ctx, storage.Event{ID: id, Title: "Trash"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected updating a deleted event to fail, got %v", err)
+	}
+	attendee.UserID = 5
+	if err := store.AddAttendee(ctx, attendee); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected inviting to a deleted event to fail, got %v", err)
+	}
+	if _, err := store.ListAttendees(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ListAttendees of a deleted event to fail, got %v", err)
+	}
+
+	trash, err := store.ListDeletedEvents(ctx)
+	if err != nil {
+		t.Fatalf("ListDeletedEvents failed: %v", err)
+	}
+	if len(trash) == 0 || trash[0].ID != id || trash[0].DeletedAt == nil || len(trash[0].Attendees) != 1 {
+		t.Errorf("unexpected trash %+v", trash)
+	}
+
+	if err := store.UndeleteEvent(ctx, id); err != nil {
+		t.Fatalf("UndeleteEvent failed: %v", err)
+	}
+	if err := store.UndeleteEvent(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected undeleting a live event to fail, got %v", err)
+	}
+	if got, err := store.GetEvent(ctx, id); err != nil || got.DeletedAt != nil || len(got.Attendees) != 1 {
+		t.Errorf("GetEvent after UndeleteEvent: %+v, %v", got, err)
+	}
+
+	if err := store.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	if n, err := store.PurgeDeletedEvents(ctx, time.Now().Add(time.Hour)); err != nil || n < 1 {
+		t.Fatalf("PurgeDeletedEvents = %d, %v", n, err)
+	}
+	if err := store.UndeleteEvent(ctx, id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a purged event to be gone, got %v", err)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		t.Skip("Skipping PSQL tests: could not run migrations")
+	}
+	store := New(cfg)
+	ctx := context.Background()
+	countBefore, err := countEvents(store, ctx)
+	if err != nil {
+		t.Fatalf("Failed to count events before: %v", err)
+	}
+
+	name := fmt.Sprintf("test-%d", time.Now().UnixNano())
+	catID, err := store.CreateCategory(ctx, storage.Category{Name: name, Color: "#00ff00"})
+	if err != nil {
+		t.Fatalf("CreateCategory failed: %v", err)
+	}
+	defer store.DeleteCategory(ctx, catID) //nolint:errcheck
+	if _, err := store.CreateCategory(ctx, storage.Category{Name: name}); !errors.Is(err, storage.ErrCategoryExists) {
+		t.Errorf("expected ErrCategoryExists, got %v", err)
+	}
+
+	service := name
+	id, err := store.CreateEvent(ctx, storage.Event{Title: "Tagged", Description: "test", Service: &service})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	defer store.db.ExecContext(ctx, "DELETE FROM events WHERE id = $1", id) //nolint:errcheck
+
+	err = store.SetEventTags(ctx, id, []string{name, "no such category"})
+	if !errors.Is(err, storage.ErrCategoryNotFound) {
+		t.Errorf("expected ErrCategoryNotFound, got %v", err)
+	}
+	if err := store.SetEventTags(ctx, id, []string{name}); err != nil {
+		t.Fatalf("SetEventTags failed: %v", err)
+	}
+	for _, filter := range []storage.Filter{
+		{Period: storage.PeriodAll, Tags: []string{name}},
+		{Period: storage.PeriodAll, Service: name},
+	} {
+		events, err := store.ListEvents(ctx, filter)
+		if err != nil || len(events) != 1 || len(events[0].Tags) != 1 || events[0].Tags[0] != name {
+			t.Errorf("ListEvents(%+v) = %+v, %v", filter, events, err)
+		}
+	}
+
+	if err := store.DeleteCategory(ctx, catID); err != nil {
+		t.Fatalf("DeleteCategory failed: %v", err)
+	}
+	if got, err := store.GetEvent(ctx, id); err != nil || len(got.Tags) != 0 {
+		t.Errorf("GetEvent after DeleteCategory: %+v, %v", got, err)
+	}
+	if _, err := store.db.ExecContext(ctx, "DELETE FROM events WHERE id = $1", id); err != nil {
+		t.Fatalf("Failed to delete inserted event: %v", err)
+	}
+	assertEventCountUnchanged(ctx, t, store, countBefore)
+}
+
+func TestTSQuery(t *testing.T) {
+	got := tsQuery([][]string{{"flu", "shot"}, {"booster"}})
+	want := `(('flu':A <-> 'shot':A) | ('flu':B <-> 'shot':B)) & 'booster'`
+	if got != want {
+		t.Errorf("tsQuery() = %s, want %s", got, want)
+	}
+}
+
+func TestSearchEvents(t *testing.T) {
+	cfg, migrationsPath := testConfig()
+	cfg.DSN = os.Getenv("POSTGRES_DSN")
+	if err := runGooseMigrations(cfg.DSN, migrationsPath); err != nil {
+		t.Skip("Skipping PSQL tests: could not run migrations")
+	}
+	store := New(cfg)
+	ctx := context.Background()
+	countBefore, err := countEvents(store, ctx)
+	if err != nil {
+		t.Fatalf("Failed to count events before: %v", err)
+	}
+
+	word := fmt.Sprintf("w%d", time.Now().UnixNano())
+	var ids []int
+	for _, event := range []storage.Event{
+		{Title: "Flu shot " + word, Description: "Seasonal vaccination."},
+		{Title: "Check-up", Description: "Bring the flu shot card " + word},
+		{Title: "Shot flu " + word, Description: "Reversed."},
+	} {
+		id, err := store.CreateEvent(ctx, event)
+		if err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	defer func() {
+		for _, id := range ids {
+			store.db.ExecContext(ctx, "DELETE FROM events WHERE id = $1", id) //nolint:errcheck
+		}
+		assertEventCountUnchanged(ctx, t, store, countBefore)
+	}()
+
+	results, err := store.SearchEvents(ctx, storage.SearchQuery{
+		Phrases: [][]string{{"flu", "shot"}, {word}},
+		Filter:  storage.Filter{Period: storage.PeriodAll},
+	})
+	if err != nil {
+		t.Fatalf("SearchEvents failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Event.ID != ids[0] || results[1].Event.ID != ids[1] {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if !strings.Contains(results[0].Title, "<b>Flu</b>") || !strings.Contains(results[1].Description, "<b>flu</b>") {
+		t.Errorf("unexpected highlights %q, %q", results[0].Title, results[1].Description)
+	}
+}
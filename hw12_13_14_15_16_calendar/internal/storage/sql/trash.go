@@ -0,0 +1,40 @@
+package postgresstorage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// ListDeletedEvents returns the events in the trash, most recently deleted first.
+func (s *Storage) ListDeletedEvents(ctx context.Context) ([]storage.Event, error) {
+	return s.queryEvents(ctx, s.conn, selectDeletedSQL)
+}
+
+// UndeleteEvent takes an event out of the trash. Returns ErrNotFound if it is not there.
+func (s *Storage) UndeleteEvent(ctx context.Context, id int) error {
+	result, err := s.conn.ExecContext(ctx, undeleteEventSQL, id)
+	if err != nil {
+		return fmt.Errorf("failed to undelete event: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return s.wrote(ctx, nil)
+}
+
+// PurgeDeletedEvents permanently removes the events deleted before the given time, with
+// their attendees, and returns how many were removed.
+func (s *Storage) PurgeDeletedEvents(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.conn.ExecContext(ctx, purgeDeletedSQL, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge events: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,129 @@
+package postgresstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+
+	// maxTxAttempts bounds how often WithinTx runs a transaction that conflicts with others.
+	maxTxAttempts = 10
+	// txRetryDelay is the pause before the second attempt; it grows with every attempt.
+	txRetryDelay = 5 * time.Millisecond
+)
+
+// sqlConn is implemented by *sql.DB and *sql.Tx.
+type sqlConn interface {
+	queryer
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// WithinTx runs fn in a serializable transaction, retried when it conflicts with another
+// one; see storage.Transactor. The reads of tx go to the primary. Called on the tx of an
+// outer WithinTx, it runs fn in a savepoint of that transaction.
+func (s *Storage) WithinTx(ctx context.Context, fn func(tx storage.Store) error) error {
+	if s.tx != nil {
+		return s.inTx(ctx, nil, func(*sql.Tx) error { return fn(s) })
+	}
+	err := retryTx(ctx, func() error {
+		return runTx(ctx, s.db, &sql.TxOptions{Isolation: sql.LevelSerializable}, func(conn *sql.Conn, tx *sql.Tx) error {
+			return fn(&Storage{db: s.db, conn: tx, tx: tx, txConn: conn, dsn: s.dsn, replicas: s.replicas})
+		})
+	})
+	return s.replicas.wrote(ctx, err)
+}
+
+// inTx runs fn in a new transaction or, on the tx of WithinTx, in a savepoint, so that a
+// failed operation leaves the outer transaction usable.
+func (s *Storage) inTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	if s.tx != nil {
+		if _, err := s.tx.ExecContext(ctx, `SAVEPOINT nested`); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		if err := fn(s.tx); err != nil {
+			if _, rbErr := s.tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT nested`); rbErr != nil {
+				return fmt.Errorf("%w (rollback to savepoint: %w)", err, rbErr)
+			}
+			return err
+		}
+		if _, err := s.tx.ExecContext(ctx, `RELEASE SAVEPOINT nested`); err != nil {
+			return fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		return nil
+	}
+	return runTx(ctx, s.db, opts, func(_ *sql.Conn, tx *sql.Tx) error { return fn(tx) })
+}
+
+// runTx runs fn in a transaction on a connection of its own, which fn may also use
+// directly (e.g. for COPY), and commits if fn succeeds.
+func runTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(conn *sql.Conn, tx *sql.Tx) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := fn(conn, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// read runs query in the transaction of s or, outside one, where replicaSet.read routes it.
+func (s *Storage) read(ctx context.Context, query func(db queryer) error) error {
+	if s.tx != nil {
+		return query(s.tx)
+	}
+	return s.replicas.read(ctx, func(db *sql.DB) error { return query(db) })
+}
+
+// wrote is replicaSet.wrote for writes outside a transaction. The writes of a transaction
+// are tracked by WithinTx once it commits.
+func (s *Storage) wrote(ctx context.Context, err error) error {
+	if s.tx != nil {
+		return err
+	}
+	return s.replicas.wrote(ctx, err)
+}
+
+// retryTx runs a transaction again while it fails to serialize or deadlocks, at most
+// maxTxAttempts times in all.
+func retryTx(ctx context.Context, run func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if attempt == maxTxAttempts || !isTxConflict(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		}
+	}
+}
+
+// isTxConflict reports whether a transaction failed because of concurrent ones, so that
+// running it again may succeed.
+func isTxConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) &&
+		(pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected)
+}
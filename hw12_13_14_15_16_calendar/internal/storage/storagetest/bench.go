@@ -0,0 +1,92 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// benchBatch is the number of events written by CreateEvents and listed by ListEvents.
+const benchBatch = 20
+
+// Bench measures the common operations of a store, so that backends and drivers can be
+// compared on the same workload.
+func Bench(b *testing.B, s storage.Store) {
+	b.Helper()
+	ctx := context.Background()
+	// The listed events have a clinic of their own, which the writes below do not add to.
+	listClinic, clinic := unique("bench"), unique("bench")
+
+	events := make([]storage.Event, benchBatch)
+	for i := range events {
+		events[i] = newEvent(listClinic, "bench", at(1+i%28, 10))
+	}
+	ids, err := s.CreateEvents(ctx, events)
+	if err != nil {
+		b.Fatalf("CreateEvents: %v", err)
+	}
+	for userID := 1; userID <= 3; userID++ {
+		attendee := storage.Attendee{
+			EventID: ids[0], UserID: userID, Role: storage.RoleRequired, Status: storage.RSVPAccepted,
+		}
+		if err := s.AddAttendee(ctx, attendee); err != nil {
+			b.Fatalf("AddAttendee: %v", err)
+		}
+	}
+	for i := range events {
+		events[i].Clinic = &clinic
+	}
+
+	b.Run("CreateEvent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := s.CreateEvent(ctx, events[0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("CreateEvents", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := s.CreateEvents(ctx, events); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetEvent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := s.GetEvent(ctx, ids[0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ListEvents", func(b *testing.B) {
+		filter := storage.Filter{Period: storage.PeriodAll, Clinic: listClinic}
+		for i := 0; i < b.N; i++ {
+			if _, err := s.ListEvents(ctx, filter); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("UpdateEvent", func(b *testing.B) {
+		event := events[1]
+		event.ID, event.Clinic = ids[1], &listClinic
+		for i := 0; i < b.N; i++ {
+			if err := s.UpdateEvent(ctx, event); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("DeleteEvents", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			created, err := s.CreateEvents(ctx, events)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.StartTimer()
+			if err := s.DeleteEvents(ctx, created); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,797 @@
+// Package storagetest is the conformance suite of the storage backends. Every backend runs
+// it from its tests, so that all of them are verified the same way.
+//
+// The suite does not need an empty store: each test works with its own events, told apart
+// by a clinic no other test uses, and with categories of its own.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/storage"
+)
+
+// missingID is an event and category ID that no test creates.
+const missingID = math.MaxInt32
+
+// Run runs the suite against the stores returned by newStore, which is called once per test.
+func Run(t *testing.T, newStore func(t *testing.T) storage.Store) {
+	t.Helper()
+	tests := []struct {
+		name string
+		test func(t *testing.T, s storage.Store)
+	}{
+		{"CRUD", testCRUD},
+		{"ResourceNames", testResourceNames},
+		{"NotFound", testNotFound},
+		{"Periods", testPeriods},
+		{"Attendees", testAttendees},
+		{"Batch", testBatch},
+		{"Trash", testTrash},
+		{"History", testHistory},
+		{"Categories", testCategories},
+		{"Search", testSearch},
+		{"Concurrency", testConcurrency},
+		{"Transactions", testTransactions},
+		{"ContextCancellation", testContextCancellation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+var counter atomic.Int64
+
+// unique returns a name that no other test, in this run or an earlier one, uses.
+func unique(prefix string) string {
+	return fmt.Sprintf("%s-%s-%d", prefix, strconv.FormatInt(time.Now().UnixNano(), 36), counter.Add(1))
+}
+
+// at returns a time in September 2031 UTC; the 1st is a Monday.
+func at(day, hour int) *time.Time {
+	tm := time.Date(2031, 9, day, hour, 0, 0, 0, time.UTC)
+	return &tm
+}
+
+func newEvent(clinic, title string, start *time.Time) storage.Event {
+	return storage.Event{Title: title, Start: start, TimeZone: "Europe/Berlin", Clinic: &clinic}
+}
+
+func create(ctx context.Context, t *testing.T, s storage.Store, event storage.Event) int {
+	t.Helper()
+	id, err := s.CreateEvent(ctx, event)
+	if err != nil {
+		t.Fatalf("CreateEvent(%q): %v", event.Title, err)
+	}
+	return id
+}
+
+// titles lists the events matching the filter and returns their titles in order.
+func titles(ctx context.Context, t *testing.T, s storage.Store, filter storage.Filter) string {
+	t.Helper()
+	events, err := s.ListEvents(ctx, filter)
+	if err != nil {
+		t.Fatalf("ListEvents(%+v): %v", filter, err)
+	}
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event.Title
+	}
+	return fmt.Sprint(names)
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// checkEvent compares the stored fields of two events.
+func checkEvent(t *testing.T, got, want storage.Event) {
+	t.Helper()
+	if got.ID != want.ID || got.Title != want.Title || got.Description != want.Description ||
+		!equalTime(got.Start, want.Start) || !equalTime(got.End, want.End) || got.AllDay != want.AllDay ||
+		got.TimeZone != want.TimeZone || !equalPtr(got.Clinic, want.Clinic) ||
+		!equalPtr(got.UserID, want.UserID) || !equalPtr(got.Service, want.Service) {
+		t.Errorf("got event %+v, want %+v", got, want)
+	}
+}
+
+func testCRUD(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	clinic, service, owner := unique("clinic"), "Vaccination", 7
+	event := storage.Event{
+		Title:       "Flu shot",
+		Description: "Seasonal vaccination",
+		Start:       at(1, 10),
+		End:         at(1, 11),
+		TimeZone:    "Europe/Berlin",
+		Clinic:      &clinic,
+		UserID:      &owner,
+		Service:     &service,
+	}
+	event.ID = create(ctx, t, s, event)
+	if event.ID <= 0 {
+		t.Fatalf("CreateEvent returned ID %d", event.ID)
+	}
+	if other := create(ctx, t, s, newEvent(clinic, "Other", nil)); other == event.ID {
+		t.Fatalf("two events got ID %d", other)
+	}
+
+	got, err := s.GetEvent(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetEvent: %v", err)
+	}
+	checkEvent(t, got, event)
+
+	event.Title, event.Description = "Flu shot (moved)", ""
+	event.Start, event.End, event.AllDay, event.UserID = at(2, 0), at(3, 0), true, nil
+	if err := s.UpdateEvent(ctx, event); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	got, err = s.GetEvent(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetEvent after update: %v", err)
+	}
+	checkEvent(t, got, event)
+
+	if err := s.DeleteEvent(ctx, event.ID); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	if _, err := s.GetEvent(ctx, event.ID); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("GetEvent after delete: got %v, want ErrEventNotFound", err)
+	}
+	if got := titles(ctx, t, s, storage.Filter{Period: storage.PeriodAll, Clinic: clinic}); got != "[Other]" {
+		t.Errorf("ListEvents after delete: got %s, want [Other]", got)
+	}
+}
+
+// testResourceNames checks the UID and the resource name of events created by CalDAV
+// clients: they are stored, kept by updates and found by the resource name filter.
+func testResourceNames(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	clinic, name := unique("clinic"), unique("resource")+".ics"
+	create(ctx, t, s, newEvent(clinic, "Other", nil))
+	event := newEvent(clinic, "Synced", at(1, 10))
+	event.UID, event.ResourceName = "4f1c@client.example", name
+	event.ID = create(ctx, t, s, event)
+
+	event.Title, event.UID, event.ResourceName = "Synced (moved)", "", ""
+	if err := s.UpdateEvent(ctx, event); err != nil {
+		t.Fatalf("UpdateEvent: %v", err)
+	}
+	events, err := s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll, ResourceName: name})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != event.ID {
+		t.Fatalf("ListEvents by resource name: got %+v, want event %d", events, event.ID)
+	}
+	if got := events[0]; got.Title != "Synced (moved)" || got.UID != "4f1c@client.example" || got.ResourceName != name {
+		t.Errorf("got %q with UID %q and resource name %q after update, want the names kept",
+			got.Title, got.UID, got.ResourceName)
+	}
+}
+
+func testNotFound(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	deleted := create(ctx, t, s, newEvent(unique("clinic"), "Deleted", nil))
+	if err := s.DeleteEvent(ctx, deleted); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+
+	// A deleted event is as gone as one that never existed.
+	for _, id := range []int{missingID, deleted} {
+		attendee := storage.Attendee{EventID: id, UserID: 1, Role: storage.RoleRequired, Status: storage.RSVPPending}
+		errs := make(map[string]error)
+		_, errs["GetEvent"] = s.GetEvent(ctx, id)
+		errs["UpdateEvent"] = s.UpdateEvent(ctx, storage.Event{ID: id, Title: "Ghost"})
+		errs["DeleteEvent"] = s.DeleteEvent(ctx, id)
+		errs["AddAttendee"] = s.AddAttendee(ctx, attendee)
+		_, errs["ListAttendees"] = s.ListAttendees(ctx, id)
+		errs["SetEventTags"] = s.SetEventTags(ctx, id, nil)
+		for name, err := range errs {
+			if !errors.Is(err, storage.ErrEventNotFound) {
+				t.Errorf("%s(%d): got %v, want ErrEventNotFound", name, id, err)
+			}
+		}
+	}
+
+	if err := s.UndeleteEvent(ctx, missingID); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("UndeleteEvent: got %v, want ErrEventNotFound", err)
+	}
+	err := s.UpdateCategory(ctx, storage.Category{ID: missingID, Name: unique("ghost")})
+	if !errors.Is(err, storage.ErrCategoryNotFound) {
+		t.Errorf("UpdateCategory: got %v, want ErrCategoryNotFound", err)
+	}
+	if err := s.DeleteCategory(ctx, missingID); !errors.Is(err, storage.ErrCategoryNotFound) {
+		t.Errorf("DeleteCategory: got %v, want ErrCategoryNotFound", err)
+	}
+}
+
+func testPeriods(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	clinic := unique("clinic")
+	lateAugust := time.Date(2031, 8, 31, 22, 0, 0, 0, time.UTC)
+	october := time.Date(2031, 10, 5, 10, 0, 0, 0, time.UTC)
+	overnight := newEvent(clinic, "overnight", &lateAugust)
+	overnight.End = at(1, 1)
+	for _, event := range []storage.Event{
+		newEvent(clinic, "no start", nil),
+		newEvent(clinic, "next month", &october),
+		newEvent(clinic, "later this month", at(20, 10)),
+		newEvent(clinic, "wednesday", at(3, 10)),
+		newEvent(clinic, "monday", at(1, 10)),
+		overnight,
+	} {
+		create(ctx, t, s, event)
+	}
+
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	tests := []struct {
+		name   string
+		filter storage.Filter
+		want   string
+	}{
+		{"day", storage.Filter{Period: storage.PeriodDay}, "[overnight monday]"},
+		{"week", storage.Filter{Period: storage.PeriodWeek}, "[overnight monday wednesday]"},
+		{"month", storage.Filter{Period: storage.PeriodMonth}, "[overnight monday wednesday later this month]"},
+		{
+			"all", storage.Filter{Period: storage.PeriodAll},
+			"[overnight monday wednesday later this month next month no start]",
+		},
+		{"range", storage.Filter{From: *at(15, 0), To: *at(30, 0)}, "[later this month]"},
+		{"open range", storage.Filter{From: *at(3, 0)}, "[wednesday later this month next month]"},
+		{"day in zone", storage.Filter{Period: storage.PeriodDay, Location: losAngeles}, "[monday]"},
+	}
+	for _, tt := range tests {
+		tt.filter.Clinic, tt.filter.Now = clinic, *at(1, 12)
+		if got := titles(ctx, t, s, tt.filter); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testAttendees(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	clinic := unique("clinic")
+	id := create(ctx, t, s, newEvent(clinic, "Check-up", at(1, 10)))
+
+	organizer := storage.Attendee{EventID: id, UserID: 5, Role: storage.RoleOrganizer, Status: storage.RSVPAccepted}
+	guest := storage.Attendee{EventID: id, UserID: 3, Role: storage.RoleRequired, Status: storage.RSVPPending}
+	for _, a := range []storage.Attendee{organizer, guest} {
+		if err := s.AddAttendee(ctx, a); err != nil {
+			t.Fatalf("AddAttendee(%d): %v", a.UserID, err)
+		}
+	}
+	if err := s.AddAttendee(ctx, guest); !errors.Is(err, storage.ErrAttendeeExists) {
+		t.Errorf("AddAttendee twice: got %v, want ErrAttendeeExists", err)
+	}
+
+	attendees, err := s.ListAttendees(ctx, id)
+	if err != nil {
+		t.Fatalf("ListAttendees: %v", err)
+	}
+	if fmt.Sprint(attendees) != fmt.Sprint([]storage.Attendee{guest, organizer}) {
+		t.Errorf("ListAttendees: got %v, want them ordered by user ID", attendees)
+	}
+
+	guest.Status = storage.RSVPAccepted
+	if err := s.UpdateAttendee(ctx, guest); err != nil {
+		t.Fatalf("UpdateAttendee: %v", err)
+	}
+	event, err := s.GetEvent(ctx, id)
+	if err != nil {
+		t.Fatalf("GetEvent: %v", err)
+	}
+	if fmt.Sprint(event.Attendees) != fmt.Sprint([]storage.Attendee{guest, organizer}) {
+		t.Errorf("GetEvent: got attendees %v", event.Attendees)
+	}
+
+	stranger := storage.Attendee{EventID: id, UserID: 9, Role: storage.RoleOptional, Status: storage.RSVPDeclined}
+	if err := s.UpdateAttendee(ctx, stranger); !errors.Is(err, storage.ErrAttendeeNotFound) {
+		t.Errorf("UpdateAttendee of a stranger: got %v, want ErrAttendeeNotFound", err)
+	}
+	filter := storage.Filter{Period: storage.PeriodAll, Clinic: clinic, UserIDs: []int{3}}
+	if got := titles(ctx, t, s, filter); got != "[Check-up]" {
+		t.Errorf("ListEvents of an attendee: got %s", got)
+	}
+
+	if err := s.RemoveAttendee(ctx, id, 3); err != nil {
+		t.Fatalf("RemoveAttendee: %v", err)
+	}
+	if err := s.RemoveAttendee(ctx, id, 3); !errors.Is(err, storage.ErrAttendeeNotFound) {
+		t.Errorf("RemoveAttendee twice: got %v, want ErrAttendeeNotFound", err)
+	}
+	if got := titles(ctx, t, s, filter); got != "[]" {
+		t.Errorf("ListEvents of a removed attendee: got %s", got)
+	}
+}
+
+func testBatch(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	clinic := unique("clinic")
+	ids, err := s.CreateEvents(ctx, []storage.Event{
+		newEvent(clinic, "first", at(1, 10)),
+		newEvent(clinic, "second", at(1, 11)),
+		newEvent(clinic, "third", at(1, 12)),
+	})
+	if err != nil {
+		t.Fatalf("CreateEvents: %v", err)
+	}
+	if len(ids) != 3 || ids[0] == ids[1] || ids[1] == ids[2] || ids[0] == ids[2] {
+		t.Fatalf("CreateEvents returned IDs %v", ids)
+	}
+	for i, title := range []string{"first", "second", "third"} {
+		if event, err := s.GetEvent(ctx, ids[i]); err != nil || event.Title != title {
+			t.Errorf("GetEvent(%d): got %q, %v, want %q", ids[i], event.Title, err, title)
+		}
+	}
+
+	// A failing batch deletes nothing.
+	var batchErr *storage.BatchError
+	err = s.DeleteEvents(ctx, []int{ids[0], missingID})
+	if !errors.As(err, &batchErr) || !errors.Is(batchErr.Items[1], storage.ErrEventNotFound) {
+		t.Errorf("DeleteEvents with a missing event: got %v", err)
+	}
+	err = s.DeleteEvents(ctx, []int{ids[0], ids[0]})
+	if !errors.As(err, &batchErr) || !errors.Is(batchErr.Items[1], storage.ErrBatchDuplicate) {
+		t.Errorf("DeleteEvents with a repeated event: got %v", err)
+	}
+	filter := storage.Filter{Period: storage.PeriodAll, Clinic: clinic}
+	if got := titles(ctx, t, s, filter); got != "[first second third]" {
+		t.Errorf("ListEvents after failed batches: got %s", got)
+	}
+
+	if err := s.DeleteEvents(ctx, ids[:2]); err != nil {
+		t.Fatalf("DeleteEvents: %v", err)
+	}
+	if got := titles(ctx, t, s, filter); got != "[third]" {
+		t.Errorf("ListEvents after DeleteEvents: got %s", got)
+	}
+}
+
+// inTrash returns the event with the ID from the trash.
+func inTrash(ctx context.Context, t *testing.T, s storage.Store, id int) (storage.Event, bool) {
+	t.Helper()
+	events, err := s.ListDeletedEvents(ctx)
+	if err != nil {
+		t.Fatalf("ListDeletedEvents: %v", err)
+	}
+	for _, event := range events {
+		if event.ID == id {
+			return event, true
+		}
+	}
+	return storage.Event{}, false
+}
+
+func testTrash(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	id := create(ctx, t, s, newEvent(unique("clinic"), "Surgery", at(1, 10)))
+	attendee := storage.Attendee{EventID: id, UserID: 3, Role: storage.RoleRequired, Status: storage.RSVPAccepted}
+	if err := s.AddAttendee(ctx, attendee); err != nil {
+		t.Fatalf("AddAttendee: %v", err)
+	}
+	if err := s.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+
+	deleted, ok := inTrash(ctx, t, s, id)
+	if !ok || deleted.DeletedAt == nil || len(deleted.Attendees) != 1 {
+		t.Fatalf("ListDeletedEvents: got %+v, %t", deleted, ok)
+	}
+	if err := s.UndeleteEvent(ctx, id); err != nil {
+		t.Fatalf("UndeleteEvent: %v", err)
+	}
+	if err := s.UndeleteEvent(ctx, id); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("UndeleteEvent of a live event: got %v, want ErrEventNotFound", err)
+	}
+	event, err := s.GetEvent(ctx, id)
+	if err != nil || event.DeletedAt != nil || len(event.Attendees) != 1 {
+		t.Errorf("GetEvent after undelete: got %+v, %v", event, err)
+	}
+
+	if err := s.DeleteEvent(ctx, id); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	deleted, _ = inTrash(ctx, t, s, id)
+	if _, err := s.PurgeDeletedEvents(ctx, deleted.DeletedAt.Add(-time.Hour)); err != nil {
+		t.Fatalf("PurgeDeletedEvents: %v", err)
+	}
+	if _, ok := inTrash(ctx, t, s, id); !ok {
+		t.Errorf("PurgeDeletedEvents removed an event deleted after the cutoff")
+	}
+	n, err := s.PurgeDeletedEvents(ctx, deleted.DeletedAt.Add(time.Second))
+	if err != nil || n < 1 {
+		t.Fatalf("PurgeDeletedEvents: got %d, %v", n, err)
+	}
+	if _, ok := inTrash(ctx, t, s, id); ok {
+		t.Errorf("PurgeDeletedEvents kept an event deleted before the cutoff")
+	}
+	if err := s.UndeleteEvent(ctx, id); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("UndeleteEvent of a purged event: got %v, want ErrEventNotFound", err)
+	}
+}
+
+func testHistory(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	before := newEvent(unique("clinic"), "Surgery", at(1, 10))
+	before.ID = create(ctx, t, s, before)
+	before.Attendees = []storage.Attendee{
+		{EventID: before.ID, UserID: 3, Role: storage.RoleRequired, Status: storage.RSVPAccepted},
+	}
+	after := before
+	after.Title = "Surgery (moved)"
+	uid := 7
+	entries := []storage.HistoryEntry{
+		{EventID: before.ID, Action: storage.ActionCreated, Actor: "anonymous", At: *at(1, 8), After: &before},
+		{
+			EventID: before.ID, Action: storage.ActionUpdated, Actor: "front-desk", ActorUserID: &uid,
+			At: *at(1, 9), Before: &before, After: &after,
+		},
+	}
+	for _, entry := range entries {
+		if err := s.AddHistory(ctx, entry); err != nil {
+			t.Fatalf("AddHistory(%s): %v", entry.Action, err)
+		}
+	}
+
+	check := func(stage string) {
+		t.Helper()
+		got, err := s.ListHistory(ctx, before.ID)
+		if err != nil {
+			t.Fatalf("ListHistory %s: %v", stage, err)
+		}
+		if len(got) != 2 || got[0].ID <= 0 || got[1].ID <= got[0].ID {
+			t.Fatalf("ListHistory %s: got %+v", stage, got)
+		}
+		for i, want := range entries {
+			if got[i].Action != want.Action || got[i].Actor != want.Actor ||
+				!equalPtr(got[i].ActorUserID, want.ActorUserID) || !got[i].At.Equal(want.At) {
+				t.Errorf("ListHistory %s: entry %d is %+v, want %+v", stage, i, got[i], want)
+			}
+		}
+		if got[0].Before != nil || got[1].Before.Title != before.Title || got[1].After.Title != after.Title ||
+			len(got[1].Before.Attendees) != 1 {
+			t.Errorf("ListHistory %s: unexpected snapshots %+v", stage, got)
+		}
+	}
+	check("of a live event")
+	if got, err := s.ListHistory(ctx, missingID); err != nil || len(got) != 0 {
+		t.Errorf("ListHistory of an unknown event: got %+v, %v", got, err)
+	}
+
+	// The history outlives the event, which can be recreated from it.
+	if err := s.RecreateEvent(ctx, before); !errors.Is(err, storage.ErrEventExists) {
+		t.Errorf("RecreateEvent of a live event: got %v, want ErrEventExists", err)
+	}
+	if err := s.DeleteEvent(ctx, before.ID); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	if err := s.RecreateEvent(ctx, before); !errors.Is(err, storage.ErrEventExists) {
+		t.Errorf("RecreateEvent of an event in the trash: got %v, want ErrEventExists", err)
+	}
+	deleted, _ := inTrash(ctx, t, s, before.ID)
+	if _, err := s.PurgeDeletedEvents(ctx, deleted.DeletedAt.Add(time.Second)); err != nil {
+		t.Fatalf("PurgeDeletedEvents: %v", err)
+	}
+	check("of a purged event")
+
+	if err := s.RecreateEvent(ctx, before); err != nil {
+		t.Fatalf("RecreateEvent: %v", err)
+	}
+	got, err := s.GetEvent(ctx, before.ID)
+	if err != nil {
+		t.Fatalf("GetEvent after RecreateEvent: %v", err)
+	}
+	checkEvent(t, got, before)
+	if len(got.Attendees) != 1 || got.Attendees[0].UserID != 3 {
+		t.Errorf("GetEvent after RecreateEvent: got attendees %v", got.Attendees)
+	}
+}
+
+func testCategories(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	clinic := unique("clinic")
+	category := storage.Category{Name: unique("vaccination"), Color: "#00aa00"}
+	var err error
+	if category.ID, err = s.CreateCategory(ctx, category); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	if _, err := s.CreateCategory(ctx, category); !errors.Is(err, storage.ErrCategoryExists) {
+		t.Errorf("CreateCategory twice: got %v, want ErrCategoryExists", err)
+	}
+	other := storage.Category{Name: unique("follow-up")}
+	if other.ID, err = s.CreateCategory(ctx, other); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	categories, err := s.ListCategories(ctx)
+	if err != nil {
+		t.Fatalf("ListCategories: %v", err)
+	}
+	found := 0
+	for i, c := range categories {
+		if c == category || c == other {
+			found++
+		}
+		if i > 0 && categories[i-1].Name >= c.Name {
+			t.Errorf("ListCategories is not ordered by name: %q before %q", categories[i-1].Name, c.Name)
+		}
+	}
+	if found != 2 {
+		t.Errorf("ListCategories: got %v, want %v and %v among them", categories, category, other)
+	}
+
+	id := create(ctx, t, s, newEvent(clinic, "Flu shot", at(1, 10)))
+	create(ctx, t, s, newEvent(clinic, "Untagged", at(1, 11)))
+	tags := func() string {
+		t.Helper()
+		event, err := s.GetEvent(ctx, id)
+		if err != nil {
+			t.Fatalf("GetEvent: %v", err)
+		}
+		return fmt.Sprint(event.Tags)
+	}
+	if err := s.SetEventTags(ctx, id, []string{category.Name}); err != nil {
+		t.Fatalf("SetEventTags: %v", err)
+	}
+	err = s.SetEventTags(ctx, id, []string{category.Name, unique("unknown")})
+	if !errors.Is(err, storage.ErrCategoryNotFound) {
+		t.Errorf("SetEventTags with an unknown tag: got %v, want ErrCategoryNotFound", err)
+	}
+	if got := tags(); got != fmt.Sprint([]string{category.Name}) {
+		t.Errorf("tags after SetEventTags: got %s", got)
+	}
+	filter := storage.Filter{Period: storage.PeriodAll, Clinic: clinic, Tags: []string{other.Name, category.Name}}
+	if got := titles(ctx, t, s, filter); got != "[Flu shot]" {
+		t.Errorf("ListEvents by tag: got %s", got)
+	}
+
+	renamed := category
+	renamed.Name = unique("vaccinations")
+	if err := s.UpdateCategory(ctx, renamed); err != nil {
+		t.Fatalf("UpdateCategory: %v", err)
+	}
+	if got := tags(); got != fmt.Sprint([]string{renamed.Name}) {
+		t.Errorf("tags after renaming the category: got %s", got)
+	}
+	renamed.Name = other.Name
+	if err := s.UpdateCategory(ctx, renamed); !errors.Is(err, storage.ErrCategoryExists) {
+		t.Errorf("UpdateCategory to a taken name: got %v, want ErrCategoryExists", err)
+	}
+
+	if err := s.DeleteCategory(ctx, category.ID); err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+	if got := tags(); got != "[]" {
+		t.Errorf("tags after deleting the category: got %s", got)
+	}
+}
+
+func testSearch(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	clinic := unique("clinic")
+	for _, event := range []storage.Event{
+		{Title: "Flu shot", Description: "Seasonal vaccination for a child"},
+		{Title: "Check-up", Description: "Ask about the flu shot"},
+		{Title: "Dental", Description: "Flu symptoms, no shot"},
+	} {
+		event.Clinic, event.TimeZone, event.Start = &clinic, "UTC", at(1, 10)
+		create(ctx, t, s, event)
+	}
+
+	search := func(phrases [][]string, limit int) []storage.SearchResult {
+		t.Helper()
+		results, err := s.SearchEvents(ctx, storage.SearchQuery{
+			Phrases: phrases,
+			Filter:  storage.Filter{Period: storage.PeriodAll, Clinic: clinic},
+			Limit:   limit,
+		})
+		if err != nil {
+			t.Fatalf("SearchEvents(%v): %v", phrases, err)
+		}
+		return results
+	}
+	found := func(results []storage.SearchResult) string {
+		names := make([]string, len(results))
+		for i, r := range results {
+			names[i] = r.Event.Title
+		}
+		return fmt.Sprint(names)
+	}
+
+	results := search([][]string{{"flu", "shot"}}, 0)
+	if got := found(results); got != "[Flu shot Check-up]" {
+		t.Fatalf("phrase search: got %s, want title matches first", got)
+	}
+	if results[0].Rank <= results[1].Rank {
+		t.Errorf("phrase search: rank %v of a title match is not above %v", results[0].Rank, results[1].Rank)
+	}
+	if want := storage.HighlightStart + "Flu" + storage.HighlightStop; !strings.HasPrefix(results[0].Title, want) {
+		t.Errorf("phrase search: title highlight %q", results[0].Title)
+	}
+	if got := found(search([][]string{{"flu"}, {"shot"}}, 0)); got != "[Flu shot Check-up Dental]" {
+		t.Errorf("word search: got %s", got)
+	}
+	if got := found(search([][]string{{"flu"}, {"shot"}}, 1)); got != "[Flu shot]" {
+		t.Errorf("limited search: got %s", got)
+	}
+	if got := found(search([][]string{{"vaccination"}, {"dental"}}, 0)); got != "[]" {
+		t.Errorf("search without a common match: got %s", got)
+	}
+}
+
+func testConcurrency(t *testing.T, s storage.Store) {
+	ctx := context.Background()
+	clinic := unique("clinic")
+	shared := create(ctx, t, s, newEvent(clinic, "shared", at(1, 10)))
+	const workers, perWorker = 8, 10
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ids = make(map[int]bool)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				id, err := s.CreateEvent(ctx, newEvent(clinic, "event", at(2, 10)))
+				if err != nil {
+					t.Errorf("CreateEvent: %v", err)
+					return
+				}
+				mu.Lock()
+				ids[id] = true
+				mu.Unlock()
+			}
+			attendee := storage.Attendee{EventID: shared, UserID: w + 1, Role: storage.RoleRequired, Status: storage.RSVPPending}
+			if err := s.AddAttendee(ctx, attendee); err != nil {
+				t.Errorf("AddAttendee: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				if _, err := s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll, Clinic: clinic}); err != nil {
+					t.Errorf("ListEvents: %v", err)
+				}
+				if _, err := s.GetEvent(ctx, shared); err != nil {
+					t.Errorf("GetEvent: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(ids) != workers*perWorker {
+		t.Errorf("got %d distinct IDs for %d events", len(ids), workers*perWorker)
+	}
+	events, err := s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll, Clinic: clinic})
+	if err != nil || len(events) != workers*perWorker+1 {
+		t.Errorf("ListEvents: got %d events, %v", len(events), err)
+	}
+	if attendees, err := s.ListAttendees(ctx, shared); err != nil || len(attendees) != workers {
+		t.Errorf("ListAttendees: got %v, %v", attendees, err)
+	}
+}
+
+// testTransactions checks WithinTx on the backends that implement storage.Transactor.
+func testTransactions(t *testing.T, s storage.Store) {
+	tr, ok := s.(storage.Transactor)
+	if !ok {
+		t.Skip("the backend has no transactions")
+	}
+	ctx := context.Background()
+	clinic := unique("clinic")
+	filter := storage.Filter{Period: storage.PeriodAll, Clinic: clinic}
+
+	errAbort := errors.New("abort")
+	err := tr.WithinTx(ctx, func(tx storage.Store) error {
+		id := create(ctx, t, tx, newEvent(clinic, "rolled back", at(1, 10)))
+		if _, err := tx.GetEvent(ctx, id); err != nil {
+			t.Errorf("GetEvent of an event created in the transaction: %v", err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Errorf("WithinTx with a failing function: got %v, want %v", err, errAbort)
+	}
+	if got := titles(ctx, t, s, filter); got != "[]" {
+		t.Errorf("ListEvents after a rolled back transaction: got %s", got)
+	}
+
+	var id int
+	err = tr.WithinTx(ctx, func(tx storage.Store) error {
+		var err error
+		if id, err = tx.CreateEvent(ctx, newEvent(clinic, "committed", at(1, 10))); err != nil {
+			return err
+		}
+		organizer := storage.Attendee{EventID: id, UserID: 1, Role: storage.RoleOrganizer, Status: storage.RSVPAccepted}
+		return tx.AddAttendee(ctx, organizer)
+	})
+	if err != nil {
+		t.Fatalf("WithinTx: %v", err)
+	}
+	if attendees, err := s.ListAttendees(ctx, id); err != nil || len(attendees) != 1 {
+		t.Errorf("ListAttendees after a committed transaction: got %v, %v", attendees, err)
+	}
+
+	// Concurrent read-modify-write transactions must not lose updates.
+	const workers = 4
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := tr.WithinTx(ctx, func(tx storage.Store) error {
+				event, err := tx.GetEvent(ctx, id)
+				if err != nil {
+					return err
+				}
+				event.Description += "+"
+				return tx.UpdateEvent(ctx, event)
+			})
+			if err != nil {
+				t.Errorf("WithinTx: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if event, err := s.GetEvent(ctx, id); err != nil || event.Description != strings.Repeat("+", workers) {
+		t.Errorf("GetEvent after concurrent transactions: got %q, %v", event.Description, err)
+	}
+}
+
+func testContextCancellation(t *testing.T, s storage.Store) {
+	clinic := unique("clinic")
+	id := create(context.Background(), t, s, newEvent(clinic, "Kept", at(1, 10)))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := make(map[string]error)
+	_, errs["CreateEvent"] = s.CreateEvent(ctx, newEvent(clinic, "Canceled", nil))
+	_, errs["CreateEvents"] = s.CreateEvents(ctx, []storage.Event{newEvent(clinic, "Canceled", nil)})
+	_, errs["GetEvent"] = s.GetEvent(ctx, id)
+	_, errs["ListEvents"] = s.ListEvents(ctx, storage.Filter{Period: storage.PeriodAll, Clinic: clinic})
+	errs["UpdateEvent"] = s.UpdateEvent(ctx, storage.Event{ID: id, Title: "Canceled"})
+	errs["DeleteEvent"] = s.DeleteEvent(ctx, id)
+	errs["AddAttendee"] = s.AddAttendee(ctx, storage.Attendee{EventID: id, UserID: 1})
+	_, errs["ListAttendees"] = s.ListAttendees(ctx, id)
+	errs["UpdateAttendee"] = s.UpdateAttendee(ctx, storage.Attendee{EventID: id, UserID: 1})
+	errs["RemoveAttendee"] = s.RemoveAttendee(ctx, id, 1)
+	for name, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s with a canceled context: got %v, want context.Canceled", name, err)
+		}
+	}
+
+	filter := storage.Filter{Period: storage.PeriodAll, Clinic: clinic}
+	if got := titles(context.Background(), t, s, filter); got != "[Kept]" {
+		t.Errorf("canceled writes changed the store: got %s", got)
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// Store is the behavior expected from every storage backend. The storagetest package
+// checks that a backend implements it the same way as the others.
+type Store interface {
+	CreateEvent(ctx context.Context, event Event) (int, error)
+	GetEvent(ctx context.Context, id int) (Event, error)
+	ListEvents(ctx context.Context, filter Filter) ([]Event, error)
+	UpdateEvent(ctx context.Context, event Event) error
+	DeleteEvent(ctx context.Context, id int) error
+	CreateEvents(ctx context.Context, events []Event) ([]int, error)
+	DeleteEvents(ctx context.Context, ids []int) error
+
+	AddAttendee(ctx context.Context, attendee Attendee) error
+	ListAttendees(ctx context.Context, eventID int) ([]Attendee, error)
+	UpdateAttendee(ctx context.Context, attendee Attendee) error
+	RemoveAttendee(ctx context.Context, eventID, userID int) error
+
+	AddHistory(ctx context.Context, entry HistoryEntry) error
+	ListHistory(ctx context.Context, eventID int) ([]HistoryEntry, error)
+	RecreateEvent(ctx context.Context, event Event) error
+
+	ListDeletedEvents(ctx context.Context) ([]Event, error)
+	UndeleteEvent(ctx context.Context, id int) error
+	PurgeDeletedEvents(ctx context.Context, before time.Time) (int, error)
+
+	CreateCategory(ctx context.Context, category Category) (int, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+	UpdateCategory(ctx context.Context, category Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	SetEventTags(ctx context.Context, eventID int, tags []string) error
+
+	SearchEvents(ctx context.Context, query SearchQuery) ([]SearchResult, error)
+}
+
+// Transactor is implemented by the backends that can run several operations as one unit of
+// work. WithinTx calls fn with a Store whose writes take effect together when fn returns
+// nil, and not at all when it returns an error, which WithinTx returns. Other clients do not
+// see the writes before that, and the reads of fn are not affected by other clients' writes.
+//
+// fn may run more than once, when the backend retries a transaction that conflicted with
+// another one, so it must have no effects other than through tx. fn must not use the Store
+// that WithinTx was called on, and must return the error of a failed operation of tx: the
+// transaction may not be usable after it.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(tx Store) error) error
+}
@@ -0,0 +1,130 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkInterval limits how often the files are stat'ed for changes.
+var checkInterval = time.Second
+
+// Reloader serves a certificate and CA pool loaded from disk and reloads them
+// whenever one of the files' modification time changes.
+type Reloader struct {
+	certFile, keyFile, caFile string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	pool      *x509.CertPool
+	modTimes  [3]time.Time
+	lastCheck time.Time
+}
+
+// NewReloader loads the files once and returns a Reloader. Empty paths are skipped.
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader) files() [3]string {
+	return [3]string{r.certFile, r.keyFile, r.caFile}
+}
+
+func (r *Reloader) stat() ([3]time.Time, error) {
+	var times [3]time.Time
+	for i, path := range r.files() {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return times, err
+		}
+		times[i] = info.ModTime()
+	}
+	return times, nil
+}
+
+// load reads all files; the caller must hold mu or own r exclusively.
+func (r *Reloader) load() error {
+	times, err := r.stat()
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		c, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("tls: failed to load key pair: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("tls: failed to read CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates found in %s", r.caFile)
+		}
+	}
+
+	r.cert, r.pool, r.modTimes = cert, pool, times
+	return nil
+}
+
+// refresh reloads the files if they changed since the last load. A failed reload
+// (e.g. a half-written file) keeps serving the previous material.
+func (r *Reloader) refresh() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastCheck) < checkInterval {
+		return
+	}
+	r.lastCheck = time.Now()
+
+	times, err := r.stat()
+	if err != nil || times == r.modTimes {
+		return
+	}
+	_ = r.load()
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.refresh()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cert, nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate.
+func (r *Reloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.refresh()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cert, nil
+}
+
+// CAPool returns the current CA pool.
+func (r *Reloader) CAPool() (*x509.CertPool, error) {
+	r.refresh()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pool == nil {
+		return nil, fmt.Errorf("tls: no CA file configured")
+	}
+	return r.pool, nil
+}
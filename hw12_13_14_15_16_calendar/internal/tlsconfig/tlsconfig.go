@@ -0,0 +1,152 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+var ErrNoCertificate = errors.New("tls: cert_file and key_file must be set together")
+
+// Enabled reports whether the listener or client described by cfg should use TLS.
+func Enabled(cfg config.TLSConf) bool {
+	return cfg.CertFile != "" || cfg.KeyFile != "" || cfg.CAFile != ""
+}
+
+// ServerConfig builds a server-side tls.Config whose certificate and client CA pool are
+// reloaded when the files change. When CAFile is set, clients must present a certificate
+// signed by it (mTLS).
+func ServerConfig(cfg config.TLSConf) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, ErrNoCertificate
+	}
+	r, err := NewReloader(cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	base := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+	if cfg.CAFile == "" {
+		return base, nil
+	}
+
+	base.ClientAuth = tls.RequireAndVerifyClientCert
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		pool, err := r.CAPool()
+		if err != nil {
+			return nil, err
+		}
+		c := base.Clone()
+		c.ClientCAs = pool
+		c.GetConfigForClient = nil
+		return c, nil
+	}
+	return base, nil
+}
+
+// ClientConfig builds a client-side tls.Config. The optional client certificate and the
+// CA pool used to verify the server are reloaded when the files change.
+func ClientConfig(cfg config.TLSConf) (*tls.Config, error) {
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, ErrNoCertificate
+	}
+	r, err := NewReloader(cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+	}
+	if cfg.CertFile != "" {
+		c.GetClientCertificate = r.GetClientCertificate
+	}
+	if cfg.CAFile != "" {
+		// Verification is done in VerifyConnection so that a rotated CA file is picked up
+		// without rebuilding the dialer; skipping the built-in check is therefore safe.
+		c.InsecureSkipVerify = true //nolint:gosec
+		c.VerifyConnection = func(cs tls.ConnectionState) error {
+			pool, err := r.CAPool()
+			if err != nil {
+				return err
+			}
+			return verifyServer(cs, pool, cfg.ServerName)
+		}
+	}
+	return c, nil
+}
+
+func verifyServer(cs tls.ConnectionState, roots *x509.CertPool, serverName string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server presented no certificate")
+	}
+	if serverName == "" {
+		serverName = cs.ServerName
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       serverName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+		return fmt.Errorf("tls: failed to verify server certificate: %w", err)
+	}
+	return nil
+}
+
+// Identity returns the identity carried by a verified client certificate: the first
+// URI or DNS SAN, falling back to the subject common name.
+func Identity(cert *x509.Certificate) string {
+	switch {
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	default:
+		return cert.Subject.CommonName
+	}
+}
+
+func stateIdentity(state tls.ConnectionState) (string, bool) {
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return Identity(state.VerifiedChains[0][0]), true
+}
+
+// PeerIdentity extracts the client certificate identity of a gRPC call made over mTLS.
+func PeerIdentity(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", false
+	}
+	return stateIdentity(info.State)
+}
+
+// RequestIdentity extracts the client certificate identity of an HTTP request made over mTLS.
+func RequestIdentity(r *http.Request) (string, bool) {
+	if r.TLS == nil {
+		return "", false
+	}
+	return stateIdentity(*r.TLS)
+}
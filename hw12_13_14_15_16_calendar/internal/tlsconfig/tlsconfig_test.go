@@ -0,0 +1,185 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "calendar test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue writes a leaf certificate and key signed by the CA and returns their paths.
+func (ca *testCA) issue(t *testing.T, dir, name string, serial int64, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	if usage == x509.ExtKeyUsageServerAuth {
+		tmpl.DNSNames = []string{"localhost"}
+		tmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(dir, name+".crt")
+	keyPath := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certPath, keyPath
+}
+
+func (ca *testCA) write(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0o600))
+	return path
+}
+
+// serve accepts TLS connections and reports the client identity of each handshake.
+func serve(t *testing.T, cfg *tls.Config) (string, <-chan string) {
+	t.Helper()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	identities := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			if err := tlsConn.Handshake(); err == nil {
+				identity, _ := stateIdentity(tlsConn.ConnectionState())
+				identities <- identity
+			}
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String(), identities
+}
+
+func TestMutualTLSWithIdentity(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caPath := ca.write(t, dir)
+	serverCert, serverKey := ca.issue(t, dir, "server", 2, x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, dir, "staff-42", 3, x509.ExtKeyUsageClientAuth)
+
+	serverCfg, err := ServerConfig(config.TLSConf{CertFile: serverCert, KeyFile: serverKey, CAFile: caPath})
+	require.NoError(t, err)
+	addr, identities := serve(t, serverCfg)
+
+	clientCfg, err := ClientConfig(config.TLSConf{
+		CertFile: clientCert, KeyFile: clientKey, CAFile: caPath, ServerName: "localhost",
+	})
+	require.NoError(t, err)
+
+	conn, err := tls.Dial("tcp", addr, clientCfg)
+	require.NoError(t, err)
+	conn.Close()
+	require.Equal(t, "staff-42", <-identities)
+
+	// A client without a certificate is rejected by the mTLS listener.
+	anonymous, err := ClientConfig(config.TLSConf{CAFile: caPath, ServerName: "localhost"})
+	require.NoError(t, err)
+	if conn, err := tls.Dial("tcp", addr, anonymous); err == nil {
+		_, err = conn.Read(make([]byte, 1))
+		require.Error(t, err)
+		conn.Close()
+	}
+}
+
+func TestClientRejectsUnknownServer(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, serverKey := newTestCA(t).issue(t, dir, "server", 2, x509.ExtKeyUsageServerAuth)
+	otherCA := newTestCA(t).write(t, t.TempDir())
+
+	serverCfg, err := ServerConfig(config.TLSConf{CertFile: serverCert, KeyFile: serverKey})
+	require.NoError(t, err)
+	addr, _ := serve(t, serverCfg)
+
+	clientCfg, err := ClientConfig(config.TLSConf{CAFile: otherCA, ServerName: "localhost"})
+	require.NoError(t, err)
+	_, err = tls.Dial("tcp", addr, clientCfg)
+	require.Error(t, err)
+}
+
+func TestServerCertificateHotReload(t *testing.T) {
+	checkInterval = 0
+	defer func() { checkInterval = time.Second }()
+
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caPath := ca.write(t, dir)
+	serverCert, serverKey := ca.issue(t, dir, "server", 2, x509.ExtKeyUsageServerAuth)
+
+	serverCfg, err := ServerConfig(config.TLSConf{CertFile: serverCert, KeyFile: serverKey})
+	require.NoError(t, err)
+	addr, _ := serve(t, serverCfg)
+
+	clientCfg, err := ClientConfig(config.TLSConf{CAFile: caPath, ServerName: "localhost"})
+	require.NoError(t, err)
+
+	servedSerial := func() int64 {
+		conn, err := tls.Dial("tcp", addr, clientCfg)
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+	require.Equal(t, int64(2), servedSerial())
+
+	// Rotate the certificate on disk; make sure the modification time moves forward.
+	ca.issue(t, dir, "server", 7, x509.ExtKeyUsageServerAuth)
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(serverCert, future, future))
+	require.NoError(t, os.Chtimes(serverKey, future, future))
+
+	require.Equal(t, int64(7), servedSerial())
+}
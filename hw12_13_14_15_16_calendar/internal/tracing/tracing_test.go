@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/config"
+	"go.opentelemetry.io/otel"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector is a minimal stand-in for an OTLP/HTTP collector.
+type collector struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || r.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var req collectortrace.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				c.spans = append(c.spans, span.GetName())
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	out, _ := proto.Marshal(&collectortrace.ExportTraceServiceResponse{})
+	_, _ = w.Write(out)
+}
+
+func TestInit_OTLPExportsToCollector(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	ctx := context.Background()
+	shutdown, err := Init(ctx, config.TracingConf{Exporter: "otlp", Endpoint: srv.URL}, "calendar-test")
+	require.NoError(t, err)
+
+	_, span := Tracer().Start(ctx, "test-span")
+	span.End()
+
+	require.NoError(t, shutdown(ctx))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Equal(t, []string{"test-span"}, c.spans)
+}
+
+func TestInit_None(t *testing.T) {
+	shutdown, err := Init(context.Background(), config.TracingConf{}, "calendar-test")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+	require.NotNil(t, otel.GetTextMapPropagator())
+}
+
+func TestInit_UnknownExporter(t *testing.T) {
+	_, err := Init(context.Background(), config.TracingConf{Exporter: "zipkin"}, "calendar-test")
+	require.Error(t, err)
+}
@@ -0,0 +1,174 @@
+// Package validation checks values against rules written in the hw09 validator style,
+// e.g. `validate:"required|max:40"`.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrRequired   = errors.New("value is required")
+	ErrNotAllowed = errors.New("value is not allowed")
+	ErrTooSmall   = errors.New("value is below the minimum")
+	ErrTooLarge   = errors.New("value is above the maximum")
+	ErrTooShort   = errors.New("length is below the minimum")
+	ErrTooLong    = errors.New("length is above the maximum")
+	ErrNotStruct  = errors.New("value is not a struct")
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// FieldError is a rule violated by a field; Field is the name of the struct field.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors lists all violations found in a value; errors.Is and errors.As see each of them.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fe := range e {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// Struct checks the exported fields of v, a struct or a pointer to one, against their
+// `validate` tags. It returns nil or Errors with every violation found.
+func Struct(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrNotStruct, v)
+	}
+
+	var errs Errors
+	for i := 0; i < rv.NumField(); i++ {
+		sf := rv.Type().Field(i)
+		rules := sf.Tag.Get("validate")
+		if !sf.IsExported() || rules == "" {
+			continue
+		}
+		for _, err := range Field(rv.Field(i), rules) {
+			errs = append(errs, FieldError{Field: sf.Name, Err: err})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Field checks v against rules such as "required|in:a,b|min:0|max:10". Bounds apply to
+// numbers and durations, and to the length of strings and slices. A nil pointer only fails
+// required; otherwise the value it points to is checked. The in rule accepts an empty
+// string; use required to forbid it.
+func Field(v reflect.Value, rules string) []error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if contains(strings.Split(rules, "|"), "required") {
+				return []error{ErrRequired}
+			}
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var errs []error
+	for _, rule := range strings.Split(rules, "|") {
+		name, param, _ := strings.Cut(rule, ":")
+		var err error
+		switch name {
+		case "required":
+			if v.IsZero() || (v.Kind() == reflect.Slice && v.Len() == 0) {
+				err = ErrRequired
+			}
+		case "in":
+			if v.Kind() == reflect.String && v.String() != "" && !contains(strings.Split(param, ","), v.String()) {
+				err = fmt.Errorf("%w: %q, want one of %s", ErrNotAllowed, v.String(), param)
+			}
+		case "min", "max":
+			err = checkBound(v, name, param)
+		default:
+			err = fmt.Errorf("unknown validation rule %q", name)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func checkBound(v reflect.Value, rule, param string) error {
+	var value, bound float64
+	tooSmall, tooLarge := ErrTooSmall, ErrTooLarge
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(param)
+		if err != nil {
+			return fmt.Errorf("bad %s rule %q: %w", rule, param, err)
+		}
+		value, bound = float64(v.Int()), float64(d)
+	case v.CanInt() || v.CanUint() || v.CanFloat() || v.Kind() == reflect.String || v.Kind() == reflect.Slice:
+		b, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Errorf("bad %s rule %q: %w", rule, param, err)
+		}
+		bound = b
+		switch {
+		case v.CanInt():
+			value = float64(v.Int())
+		case v.CanUint():
+			value = float64(v.Uint())
+		case v.CanFloat():
+			value = v.Float()
+		case v.Kind() == reflect.String:
+			// Characters, not bytes, like the VARCHAR columns the limits usually come from.
+			value, tooSmall, tooLarge = float64(utf8.RuneCountInString(v.String())), ErrTooShort, ErrTooLong
+		default:
+			value, tooSmall, tooLarge = float64(v.Len()), ErrTooShort, ErrTooLong
+		}
+	default:
+		return fmt.Errorf("%s rule does not apply to %s", rule, v.Type())
+	}
+
+	if rule == "min" && value < bound {
+		return fmt.Errorf("%w %s", tooSmall, param)
+	}
+	if rule == "max" && value > bound {
+		return fmt.Errorf("%w %s", tooLarge, param)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package validation_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whatafunc/Golang_Otus_Labs/hw12_13_14_15_16_calendar/internal/validation"
+)
+
+type record struct {
+	Name    string        `validate:"required|max:5"`
+	Kind    string        `validate:"in:a,b"`
+	Count   int           `validate:"min:1|max:10"`
+	Owner   *int          `validate:"min:1"`
+	Tags    []string      `validate:"max:2"`
+	Timeout time.Duration `validate:"min:1s"`
+	Note    string
+	hidden  string `validate:"required"` //nolint:unused
+}
+
+func TestStruct(t *testing.T) {
+	owner := 3
+	valid := record{Name: "Anna", Kind: "a", Count: 1, Owner: &owner, Timeout: time.Second}
+	require.NoError(t, validation.Struct(valid))
+	require.NoError(t, validation.Struct(&valid))
+
+	valid.Owner = nil
+	require.NoError(t, validation.Struct(valid), "nil pointers only fail required")
+
+	negative := -1
+	err := validation.Struct(record{
+		Name:    "Ангелина",
+		Kind:    "c",
+		Count:   11,
+		Owner:   &negative,
+		Tags:    []string{"x", "y", "z"},
+		Timeout: time.Millisecond,
+	})
+	var errs validation.Errors
+	require.ErrorAs(t, err, &errs)
+	fields := make([]string, len(errs))
+	for i, fe := range errs {
+		fields[i] = fe.Field
+	}
+	require.Equal(t, []string{"Name", "Kind", "Count", "Owner", "Tags", "Timeout"}, fields)
+	for _, want := range []error{
+		validation.ErrTooLong, validation.ErrNotAllowed, validation.ErrTooLarge, validation.ErrTooSmall,
+	} {
+		require.ErrorIs(t, err, want)
+	}
+
+	err = validation.Struct(record{})
+	require.ErrorIs(t, err, validation.ErrRequired)
+	require.Contains(t, err.Error(), "Name: value is required")
+}
+
+func TestStruct_NotStruct(t *testing.T) {
+	err := validation.Struct(42)
+	require.True(t, errors.Is(err, validation.ErrNotStruct), err)
+}
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS attendees (
+    event_id INT NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'required' CHECK (role IN ('organizer', 'required', 'optional')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'tentative')),
+    PRIMARY KEY (event_id, user_id)
+);
+
+-- Looking up the events a user is invited to.
+CREATE INDEX IF NOT EXISTS attendees_user_id_idx ON attendees (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS attendees;
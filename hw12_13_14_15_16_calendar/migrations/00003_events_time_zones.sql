@@ -0,0 +1,22 @@
+-- +goose Up
+-- Existing timestamps were written without a zone by servers running in UTC.
+ALTER TABLE events
+    ALTER COLUMN start TYPE TIMESTAMPTZ USING start AT TIME ZONE 'UTC',
+    ALTER COLUMN "end" TYPE TIMESTAMPTZ USING "end" AT TIME ZONE 'UTC',
+    ALTER COLUMN allday DROP DEFAULT,
+    ALTER COLUMN allday TYPE BOOLEAN USING allday <> 0,
+    ALTER COLUMN allday SET DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Period queries filter on start.
+CREATE INDEX IF NOT EXISTS events_start_idx ON events (start);
+
+-- +goose Down
+DROP INDEX IF EXISTS events_start_idx;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS time_zone,
+    ALTER COLUMN allday DROP DEFAULT,
+    ALTER COLUMN allday TYPE FLOAT USING CASE WHEN allday THEN 1 ELSE 0 END,
+    ALTER COLUMN "end" TYPE TIMESTAMP USING "end" AT TIME ZONE 'UTC',
+    ALTER COLUMN start TYPE TIMESTAMP USING start AT TIME ZONE 'UTC';
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Every write to an event or its attendees is announced on the calendar_events channel, so
+-- that all calendar replicas (and writers outside the service) feed their watchers.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_event_change() RETURNS trigger AS $$
+DECLARE
+    change_type TEXT;
+    event_id INT;
+BEGIN
+    IF TG_TABLE_NAME = 'attendees' THEN
+        change_type := 'updated';
+        event_id := COALESCE(NEW.event_id, OLD.event_id);
+    ELSE
+        change_type := CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END;
+        event_id := COALESCE(NEW.id, OLD.id);
+    END IF;
+    PERFORM pg_notify('calendar_events', json_build_object('type', change_type, 'id', event_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER events_notify AFTER INSERT OR UPDATE OR DELETE ON events
+    FOR EACH ROW EXECUTE FUNCTION notify_event_change();
+CREATE TRIGGER attendees_notify AFTER INSERT OR UPDATE OR DELETE ON attendees
+    FOR EACH ROW EXECUTE FUNCTION notify_event_change();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS attendees_notify ON attendees;
+DROP TRIGGER IF EXISTS events_notify ON events;
+DROP FUNCTION IF EXISTS notify_event_change();
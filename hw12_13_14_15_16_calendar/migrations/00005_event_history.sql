@@ -0,0 +1,30 @@
+-- +goose Up
+-- Append-only history of the writes to events. There is no foreign key, since the history
+-- of a deleted event is kept. before and after are JSON snapshots of storage.Event.
+CREATE TABLE IF NOT EXISTS event_history (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('created', 'updated', 'deleted', 'restored')),
+    actor TEXT NOT NULL,
+    actor_user_id INT,
+    at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    before JSONB,
+    after JSONB
+);
+
+CREATE INDEX IF NOT EXISTS event_history_event_id_idx ON event_history (event_id, id);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION reject_history_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'event_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER event_history_append_only BEFORE UPDATE OR DELETE ON event_history
+    FOR EACH ROW EXECUTE FUNCTION reject_history_change();
+
+-- +goose Down
+DROP TABLE IF EXISTS event_history;
+DROP FUNCTION IF EXISTS reject_history_change();
@@ -0,0 +1,56 @@
+-- +goose Up
+-- Deleted events stay in the table, with deleted_at set, until the producer purges them.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- The trash is listed and purged by deletion time.
+CREATE INDEX IF NOT EXISTS events_deleted_at_idx ON events (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Moving an event to the trash announces a deletion and taking it out a creation. Purging
+-- an event from the trash is not announced again.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_event_change() RETURNS trigger AS $$
+DECLARE
+    change_type TEXT;
+    event_id INT;
+BEGIN
+    IF TG_TABLE_NAME = 'attendees' THEN
+        change_type := 'updated';
+        event_id := COALESCE(NEW.event_id, OLD.event_id);
+    ELSIF TG_OP = 'DELETE' AND OLD.deleted_at IS NOT NULL THEN
+        RETURN NULL;
+    ELSIF TG_OP = 'UPDATE' AND OLD.deleted_at IS DISTINCT FROM NEW.deleted_at THEN
+        change_type := CASE WHEN NEW.deleted_at IS NULL THEN 'created' ELSE 'deleted' END;
+        event_id := NEW.id;
+    ELSE
+        change_type := CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END;
+        event_id := COALESCE(NEW.id, OLD.id);
+    END IF;
+    PERFORM pg_notify('calendar_events', json_build_object('type', change_type, 'id', event_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_event_change() RETURNS trigger AS $$
+DECLARE
+    change_type TEXT;
+    event_id INT;
+BEGIN
+    IF TG_TABLE_NAME = 'attendees' THEN
+        change_type := 'updated';
+        event_id := COALESCE(NEW.event_id, OLD.event_id);
+    ELSE
+        change_type := CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END;
+        event_id := COALESCE(NEW.id, OLD.id);
+    END IF;
+    PERFORM pg_notify('calendar_events', json_build_object('type', change_type, 'id', event_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DELETE FROM events WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS events_deleted_at_idx;
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,79 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    color TEXT NOT NULL DEFAULT '' CHECK (color = '' OR color ~ '^#[0-9a-f]{6}$')
+);
+
+CREATE TABLE IF NOT EXISTS event_categories (
+    event_id INT NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (event_id, category_id)
+);
+
+-- Tag filters look up the events of a category.
+CREATE INDEX IF NOT EXISTS event_categories_category_id_idx ON event_categories (category_id);
+
+-- Listing filters on clinic and service.
+CREATE INDEX IF NOT EXISTS events_clinic_idx ON events (clinic);
+CREATE INDEX IF NOT EXISTS events_service_idx ON events (service);
+
+-- Tagging an event announces an update of the event, like a change to its attendees.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_event_change() RETURNS trigger AS $$
+DECLARE
+    change_type TEXT;
+    event_id INT;
+BEGIN
+    IF TG_TABLE_NAME IN ('attendees', 'event_categories') THEN
+        change_type := 'updated';
+        event_id := COALESCE(NEW.event_id, OLD.event_id);
+    ELSIF TG_OP = 'DELETE' AND OLD.deleted_at IS NOT NULL THEN
+        RETURN NULL;
+    ELSIF TG_OP = 'UPDATE' AND OLD.deleted_at IS DISTINCT FROM NEW.deleted_at THEN
+        change_type := CASE WHEN NEW.deleted_at IS NULL THEN 'created' ELSE 'deleted' END;
+        event_id := NEW.id;
+    ELSE
+        change_type := CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END;
+        event_id := COALESCE(NEW.id, OLD.id);
+    END IF;
+    PERFORM pg_notify('calendar_events', json_build_object('type', change_type, 'id', event_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER event_categories_notify AFTER INSERT OR UPDATE OR DELETE ON event_categories
+    FOR EACH ROW EXECUTE FUNCTION notify_event_change();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS event_categories_notify ON event_categories;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_event_change() RETURNS trigger AS $$
+DECLARE
+    change_type TEXT;
+    event_id INT;
+BEGIN
+    IF TG_TABLE_NAME = 'attendees' THEN
+        change_type := 'updated';
+        event_id := COALESCE(NEW.event_id, OLD.event_id);
+    ELSIF TG_OP = 'DELETE' AND OLD.deleted_at IS NOT NULL THEN
+        RETURN NULL;
+    ELSIF TG_OP = 'UPDATE' AND OLD.deleted_at IS DISTINCT FROM NEW.deleted_at THEN
+        change_type := CASE WHEN NEW.deleted_at IS NULL THEN 'created' ELSE 'deleted' END;
+        event_id := NEW.id;
+    ELSE
+        change_type := CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END;
+        event_id := COALESCE(NEW.id, OLD.id);
+    END IF;
+    PERFORM pg_notify('calendar_events', json_build_object('type', change_type, 'id', event_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP INDEX IF EXISTS events_service_idx;
+DROP INDEX IF EXISTS events_clinic_idx;
+DROP TABLE IF EXISTS event_categories;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Words of titles (weight A) and descriptions (weight B) for full-text search. The simple
+-- configuration lowercases words without stemming, like the memory backend.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', description), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS events_search_idx ON events USING GIN (search);
+
+-- +goose Down
+DROP INDEX IF EXISTS events_search_idx;
+ALTER TABLE events DROP COLUMN IF EXISTS search;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- The UID and resource name a CalDAV client gave the events it created, so that its later
+-- requests to the same resource find them. Empty for events named after their ID.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS uid TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS resource_name TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS events_resource_name_idx ON events (userid, resource_name) WHERE resource_name <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS events_resource_name_idx;
+ALTER TABLE events
+    DROP COLUMN IF EXISTS resource_name,
+    DROP COLUMN IF EXISTS uid;